@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"net/url"
 	"os"
@@ -29,7 +28,6 @@ var currentPassword string
 var totpCode string
 var pbkdf2Iterations int
 var interactive bool
-var JSON bool
 
 var listSessionsCmd = &cobra.Command{
 	Use:         "list-sessions",
@@ -37,41 +35,42 @@ var listSessionsCmd = &cobra.Command{
 	Short:       "List active sessions",
 	Annotations: map[string]string{"group": "Session Management"},
 	Run: func(cmd *cobra.Command, args []string) {
-		_, response, err := api.New().GetJSON("/api/admin/sessions/list", nil)
+		sessions, err := api.New().ListSessions(cmd.Context())
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
 			os.Exit(1)
 		}
 
-		sessions, ok := response["sessions"].([]interface{})
-		if !ok || len(sessions) == 0 {
-			fmt.Println("No active sessions found.")
-			return
-		}
-
-		bold := color.New(color.Bold).SprintFunc()
-		green := color.New(color.FgGreen).SprintFunc()
-		yellow := color.New(color.FgYellow).SprintFunc()
-		cyan := color.New(color.FgCyan).SprintFunc()
-
-		fmt.Println(bold("Active Sessions:"))
-		for _, s := range sessions {
-			session := s.(map[string]interface{})
-			current := session["isCurrentSession"].(bool)
-			colorize := green
-			if !current {
-				colorize = yellow
+		renderList(sessions, sessions, func() {
+			if len(sessions) == 0 {
+				fmt.Println("No active sessions found.")
+				return
 			}
 
-			t := session["lastSeen"].(string)
-			parsed, _ := time.Parse(time.RFC3339, t)
+			bold := color.New(color.Bold).SprintFunc()
+			green := color.New(color.FgGreen).SprintFunc()
+			yellow := color.New(color.FgYellow).SprintFunc()
+			cyan := color.New(color.FgCyan).SprintFunc()
 
-			fmt.Printf("- %s (%s)\n", colorize(session["partialToken"]), session["type"])
-			fmt.Printf("  User: %s\n", cyan(session["username"]))
-			fmt.Printf("  Name: %v\n", session["tokenName"])
-			fmt.Printf("  Seen: %s from %s\n", parsed.Local().Format("2006-01-02 15:04:05"), session["lastSeenRemoteAddress"])
-			fmt.Printf("  Agent: %s\n", session["lastSeenUserAgent"])
-		}
+			fmt.Println(bold("Active Sessions:"))
+			for _, session := range sessions {
+				colorize := green
+				if !session.IsCurrentSession {
+					colorize = yellow
+				}
+
+				seen := session.LastSeen
+				if parsed, err := time.Parse(time.RFC3339, seen); err == nil {
+					seen = parsed.Local().Format("2006-01-02 15:04:05")
+				}
+
+				fmt.Printf("- %s (%s)\n", colorize(session.PartialToken), session.Type)
+				fmt.Printf("  User: %s\n", cyan(session.Username))
+				fmt.Printf("  Name: %v\n", session.TokenName)
+				fmt.Printf("  Seen: %s from %s\n", seen, session.LastSeenRemoteAddress)
+				fmt.Printf("  Agent: %s\n", session.LastSeenUserAgent)
+			}
+		})
 	},
 }
 
@@ -86,21 +85,22 @@ var deleteSessionCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		fmt.Print("Are you sure you want to delete this session? (yes/no): ")
+		fmt.Fprint(messages(), "Are you sure you want to delete this session? (yes/no): ")
 		var confirm string
 		fmt.Scanln(&confirm)
 		if confirm != "yes" {
-			fmt.Println("❌ Aborted.")
+			fmt.Fprintln(messages(), "❌ Aborted.")
 			return
 		}
 
-		q := url.Values{"partialToken": {sessionID}}
-		if _, _, err := api.New().GetJSON("/api/admin/sessions/delete", q); err != nil {
+		if err := api.New().DeleteSession(cmd.Context(), sessionID); err != nil {
 			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Printf("✅ Session '%s' deleted successfully.\n", sessionID)
+		render(map[string]string{"partialToken": sessionID}, func() {
+			fmt.Printf("✅ Session '%s' deleted successfully.\n", sessionID)
+		})
 	},
 }
 
@@ -115,17 +115,18 @@ var createTokenCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		q := url.Values{"user": {createTokenUser}, "tokenName": {createTokenName}}
-		_, response, err := api.New().GetJSON("/api/admin/sessions/createToken", q)
+		token, err := api.New().CreateToken(cmd.Context(), createTokenUser, createTokenName)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Println("✅ Token created successfully:")
-		fmt.Printf("  Username: %s\n", response["username"])
-		fmt.Printf("  Token Name: %s\n", response["tokenName"])
-		fmt.Printf("  Token: %s\n", response["token"])
+		render(token, func() {
+			fmt.Println("✅ Token created successfully:")
+			fmt.Printf("  Username: %s\n", token.Username)
+			fmt.Printf("  Token Name: %s\n", token.TokenName)
+			fmt.Printf("  Token: %s\n", token.Token)
+		})
 	},
 }
 
@@ -134,37 +135,37 @@ var adminListUsersCmd = &cobra.Command{
 	Aliases: []string{"lu"},
 	Short:   "List all system users",
 	Run: func(cmd *cobra.Command, args []string) {
-		_, response, err := api.New().GetJSON("/api/admin/users/list", nil)
+		users, err := api.New().ListUsers(cmd.Context())
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
 			os.Exit(1)
 		}
 
-		users, ok := response["users"].([]interface{})
-		if !ok || len(users) == 0 {
-			fmt.Println("No users found.")
-			return
-		}
+		renderList(users, users, func() {
+			if len(users) == 0 {
+				fmt.Println("No users found.")
+				return
+			}
 
-		bold := color.New(color.Bold).SprintFunc()
-		green := color.New(color.FgGreen).SprintFunc()
-		blue := color.New(color.FgBlue).SprintFunc()
-		red := color.New(color.FgRed).SprintFunc()
+			bold := color.New(color.Bold).SprintFunc()
+			green := color.New(color.FgGreen).SprintFunc()
+			blue := color.New(color.FgBlue).SprintFunc()
+			red := color.New(color.FgRed).SprintFunc()
 
-		fmt.Println(bold("User List:"))
-		for _, u := range users {
-			user := u.(map[string]interface{})
-			status := green("Enabled")
-			if user["disabled"].(bool) {
-				status = red("Disabled")
-			}
+			fmt.Println(bold("User List:"))
+			for _, user := range users {
+				status := green("Enabled")
+				if user.Disabled {
+					status = red("Disabled")
+				}
 
-			fmt.Printf("- %s (%s)\n", bold(user["displayName"]), blue(user["username"]))
-			fmt.Printf("  Status: %s\n", status)
-			fmt.Printf("  Previous Session: %s from %s\n", user["previousSessionLoggedOn"], user["previousSessionRemoteAddress"])
-			fmt.Printf("  Recent Session: %s from %s\n", user["recentSessionLoggedOn"], user["recentSessionRemoteAddress"])
-			fmt.Println()
-		}
+				fmt.Printf("- %s (%s)\n", bold(user.DisplayName), blue(user.Username))
+				fmt.Printf("  Status: %s\n", status)
+				fmt.Printf("  Previous Session: %s from %s\n", user.PreviousSessionLoggedOn, user.PreviousSessionRemoteAddress)
+				fmt.Printf("  Recent Session: %s from %s\n", user.RecentSessionLoggedOn, user.RecentSessionRemoteAddress)
+				fmt.Println()
+			}
+		})
 	},
 }
 
@@ -178,38 +179,38 @@ var adminGetUserCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		q := url.Values{"user": {getUser}, "includeGroups": {"true"}}
-		_, user, err := api.New().GetJSON("/api/admin/users/get", q)
+		user, err := api.New().GetUser(cmd.Context(), getUser)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
 			os.Exit(1)
 		}
 
-		bold := color.New(color.Bold).SprintFunc()
-		green := color.New(color.FgGreen).SprintFunc()
-		blue := color.New(color.FgBlue).SprintFunc()
-		red := color.New(color.FgRed).SprintFunc()
+		render(user, func() {
+			bold := color.New(color.Bold).SprintFunc()
+			green := color.New(color.FgGreen).SprintFunc()
+			blue := color.New(color.FgBlue).SprintFunc()
+			red := color.New(color.FgRed).SprintFunc()
 
-		fmt.Printf("%s (%s)\n", bold(user["displayName"]), blue(user["username"]))
-		status := green("Enabled")
-		if user["disabled"].(bool) {
-			status = red("Disabled")
-		}
-		fmt.Printf("  Status: %s\n", status)
-		fmt.Printf("  Groups: %v\n", user["groups"])
-		fmt.Printf("  Session Timeout: %v seconds\n", user["sessionTimeoutSeconds"])
-		fmt.Printf("  Previous Login: %s from %s\n", user["previousSessionLoggedOn"], user["previousSessionRemoteAddress"])
-		fmt.Printf("  Recent Login: %s from %s\n", user["recentSessionLoggedOn"], user["recentSessionRemoteAddress"])
-		fmt.Println()
-		if sessions, ok := user["sessions"].([]interface{}); ok && len(sessions) > 0 {
-			fmt.Println(bold("Sessions:"))
-			for _, s := range sessions {
-				session := s.(map[string]interface{})
-				fmt.Printf("- Token: %s (%s)\n", blue(session["partialToken"]), session["type"])
-				fmt.Printf("  Seen: %s from %s\n", session["lastSeen"], session["lastSeenRemoteAddress"])
-				fmt.Printf("  Agent: %s\n", session["lastSeenUserAgent"])
+			fmt.Printf("%s (%s)\n", bold(user.DisplayName), blue(user.Username))
+			status := green("Enabled")
+			if user.Disabled {
+				status = red("Disabled")
 			}
-		}
+			fmt.Printf("  Status: %s\n", status)
+			fmt.Printf("  Groups: %v\n", user.Groups)
+			fmt.Printf("  Session Timeout: %v seconds\n", user.SessionTimeoutSeconds)
+			fmt.Printf("  Previous Login: %s from %s\n", user.PreviousSessionLoggedOn, user.PreviousSessionRemoteAddress)
+			fmt.Printf("  Recent Login: %s from %s\n", user.RecentSessionLoggedOn, user.RecentSessionRemoteAddress)
+			fmt.Println()
+			if len(user.Sessions) > 0 {
+				fmt.Println(bold("Sessions:"))
+				for _, session := range user.Sessions {
+					fmt.Printf("- Token: %s (%s)\n", blue(session.PartialToken), session.Type)
+					fmt.Printf("  Seen: %s from %s\n", session.LastSeen, session.LastSeenRemoteAddress)
+					fmt.Printf("  Agent: %s\n", session.LastSeenUserAgent)
+				}
+			}
+		})
 	},
 }
 
@@ -218,34 +219,30 @@ var adminCheckUpdateCmd = &cobra.Command{
 	Aliases: []string{"cu"},
 	Short:   "Check for available updates",
 	Run: func(cmd *cobra.Command, args []string) {
-		result, respData, err := api.New().GetJSON("/api/user/checkForUpdate", nil)
+		update, err := api.New().CheckForUpdate(cmd.Context())
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
 			os.Exit(1)
 		}
 
-		if JSON {
-			raw, _ := json.MarshalIndent(result, "", "  ")
-			fmt.Println(string(raw))
-			return
-		}
-
-		bold := color.New(color.Bold).SprintFunc()
-		green := color.New(color.FgGreen).SprintFunc()
-		red := color.New(color.FgRed).SprintFunc()
-		cyan := color.New(color.FgCyan).SprintFunc()
-
-		fmt.Printf("%s: %s -> %s\n", bold("Version"), cyan(respData["currentVersion"]), green(respData["updateVersion"]))
-
-		if respData["updateAvailable"].(bool) {
-			fmt.Printf("⚠️  %s\n", bold(red(respData["updateTitle"])))
-			fmt.Printf("%s\n\n", respData["updateMessage"])
-			fmt.Printf("Download: %s\n", cyan(respData["downloadLink"]))
-			fmt.Printf("Instructions: %s\n", cyan(respData["instructionsLink"]))
-			fmt.Printf("Changelog: %s\n", cyan(respData["changeLogLink"]))
-		} else {
-			fmt.Println("✅ You are using the latest version.")
-		}
+		render(update, func() {
+			bold := color.New(color.Bold).SprintFunc()
+			green := color.New(color.FgGreen).SprintFunc()
+			red := color.New(color.FgRed).SprintFunc()
+			cyan := color.New(color.FgCyan).SprintFunc()
+
+			fmt.Printf("%s: %s -> %s\n", bold("Version"), cyan(update.CurrentVersion), green(update.UpdateVersion))
+
+			if update.UpdateAvailable {
+				fmt.Printf("⚠️  %s\n", bold(red(update.UpdateTitle)))
+				fmt.Printf("%s\n\n", update.UpdateMessage)
+				fmt.Printf("Download: %s\n", cyan(update.DownloadLink))
+				fmt.Printf("Instructions: %s\n", cyan(update.InstructionsLink))
+				fmt.Printf("Changelog: %s\n", cyan(update.ChangeLogLink))
+			} else {
+				fmt.Println("✅ You are using the latest version.")
+			}
+		})
 	},
 }
 
@@ -308,12 +305,15 @@ var adminChangePasswordCmd = &cobra.Command{
 			params.Set("iterations", fmt.Sprintf("%d", pbkdf2Iterations))
 		}
 
-		if _, _, err := api.New().GetJSON("/api/user/changePassword", params); err != nil {
+		_, response, err := api.New().GetJSON(cmd.Context(), "/api/user/changePassword", params)
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Println("✅ Password changed successfully.")
+		render(response, func() {
+			fmt.Println("✅ Password changed successfully.")
+		})
 	},
 }
 
@@ -321,11 +321,10 @@ func init() {
 	adminChangePasswordCmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Prompt for password interactively")
 	adminChangePasswordCmd.Flags().StringVarP(&currentPassword, "current", "c", "", "Current password (insecure)")
 	adminChangePasswordCmd.Flags().StringVarP(&newPassword, "new", "n", "", "New password (insecure)")
-	adminChangePasswordCmd.Flags().StringVarP(&totpCode, "totp", "o", "", "6-digit TOTP code if 2FA is enabled")
+	adminChangePasswordCmd.Flags().StringVar(&totpCode, "totp", "", "6-digit TOTP code if 2FA is enabled")
 	adminChangePasswordCmd.Flags().IntVar(&pbkdf2Iterations, "iterations", 0, "Number of iterations for PBKDF2 SHA256 password hashing")
 	adminCmd.AddCommand(adminChangePasswordCmd)
 	adminCmd.AddCommand(adminCheckUpdateCmd)
-	adminCheckUpdateCmd.Flags().BoolVar(&JSON, "json", false, "Output raw JSON response")
 
 	adminGetUserCmd.Flags().StringVarP(&getUser, "user", "u", "", "User to query")
 	adminCmd.AddCommand(adminGetUserCmd)
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// runWithStub executes `tdns <args>` against a server answering body and
+// returns what the command printed.
+func runWithStub(t *testing.T, body string, args ...string) (string, error) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	}))
+	defer srv.Close()
+
+	oldHost := viper.GetString("host")
+	viper.Set("host", srv.URL)
+	defer viper.Set("host", oldHost)
+	resetOutput(t)
+
+	oldStdout := os.Stdout
+	rp, wp, _ := os.Pipe()
+	os.Stdout = wp
+	defer func() { os.Stdout = oldStdout }()
+	var out bytes.Buffer
+	done := make(chan struct{})
+	go func() { _, _ = io.Copy(&out, rp); close(done) }()
+
+	rootCmd.SetArgs(args)
+	defer rootCmd.SetArgs(nil)
+	err := rootCmd.Execute()
+	wp.Close()
+	<-done
+	return out.String(), err
+}
+
+// resetOutput puts the output flags back to their defaults before and after
+// the test, as flag values outlive a command run.
+func resetOutput(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		rootCmd.PersistentFlags().Set("output", outputText)
+		rootCmd.PersistentFlags().Set("json", "false")
+		rootCmd.PersistentFlags().Lookup("output").Changed = false
+		rootCmd.PersistentFlags().Lookup("json").Changed = false
+		output = outputSpec{format: outputText}
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestAdminListSessionsToleratesMissingFields(t *testing.T) {
+	// The second session lacks isCurrentSession and lastSeen, which used to
+	// panic on the type assertions.
+	out, err := runWithStub(t, `{"status":"ok","response":{"sessions":[
+		{"username":"admin","isCurrentSession":true,"partialToken":"abc","type":"Standard","lastSeen":"2024-01-01T00:00:00Z"},
+		{"username":"ci","partialToken":"def","type":"ApiToken"}]}}`, "admin", "list-sessions")
+	if err != nil {
+		t.Fatalf("admin list-sessions: %v", err)
+	}
+	for _, want := range []string{"abc", "def", "ci"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output is missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestSettingsGetToleratesMissingTSIGKeys(t *testing.T) {
+	out, err := runWithStub(t, `{"status":"ok","response":{"version":"13.6"}}`, "settings", "get")
+	if err != nil {
+		t.Fatalf("settings get: %v", err)
+	}
+	if !strings.Contains(out, "13.6") || !strings.Contains(out, "TSIG Keys:") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestRecordsGetToleratesMissingRData(t *testing.T) {
+	recordType = ""
+	out, err := runWithStub(t, `{"status":"ok","response":{"records":[{"name":"example.com","type":"NS","ttl":3600}]}}`,
+		"records", "get", "example.com")
+	if err != nil {
+		t.Fatalf("records get: %v", err)
+	}
+	if !strings.Contains(out, "None") || !strings.Contains(out, "3600") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
@@ -0,0 +1,326 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"tdns/internal/api"
+	"tdns/pkg/technitium"
+)
+
+var (
+	appsStore     bool
+	appFile       string
+	appConfigFile string
+)
+
+// appRoles names what an app class does, as the web console's app list does.
+func appRoles(c technitium.AppClass) []string {
+	var roles []string
+	for _, r := range []struct {
+		is   bool
+		name string
+	}{
+		{c.IsAppRecordRequestHandler, "APP records"},
+		{c.IsRequestController, "request controller"},
+		{c.IsAuthoritativeRequestHandler, "authoritative"},
+		{c.IsRequestBlockingHandler, "blocking"},
+		{c.IsQueryLogger, "query logger"},
+		{c.IsPostProcessor, "post processor"},
+	} {
+		if r.is {
+			roles = append(roles, r.name)
+		}
+	}
+	return roles
+}
+
+// findStoreApp returns the App Store app called name.
+func findStoreApp(apps []technitium.StoreApp, name string) (technitium.StoreApp, error) {
+	for _, a := range apps {
+		if strings.EqualFold(a.Name, name) {
+			return a, nil
+		}
+	}
+	return technitium.StoreApp{}, fmt.Errorf("%q is not in the DNS App Store; give its package with --file", name)
+}
+
+// installApp installs or updates the app name from --file, or else from the
+// DNS App Store. It returns nil when the app is already up to date.
+func installApp(cmd *cobra.Command, name string, update bool) *technitium.DNSApp {
+	client := api.New()
+	var app *technitium.DNSApp
+	if appFile != "" {
+		f, err := os.Open(appFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		if update {
+			app, err = client.UpdateApp(cmd.Context(), name, f)
+		} else {
+			app, err = client.InstallApp(cmd.Context(), name, f)
+		}
+		if err != nil {
+			exitIfInterrupted(cmd.Context(), err, "the server may have installed the app.")
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		return app
+	}
+
+	store, err := client.ListStoreApps(cmd.Context())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+	s, err := findStoreApp(store, name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+	if update && s.Installed && !s.UpdateAvailable {
+		return nil
+	}
+	fmt.Fprintf(messages(), "Downloading %s %s from the DNS App Store\n", s.Name, s.Version)
+	if update {
+		app, err = client.DownloadAndUpdateApp(cmd.Context(), s.Name, s.URL)
+	} else {
+		app, err = client.DownloadAndInstallApp(cmd.Context(), s.Name, s.URL)
+	}
+	if err != nil {
+		exitIfInterrupted(cmd.Context(), err, "the server may have installed the app.")
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+	return app
+}
+
+var appsCmd = &cobra.Command{
+	Use:     "apps",
+	Aliases: []string{"app"},
+	Short:   "Manage DNS Apps and their config",
+	Long: `Install, update and uninstall DNS Apps, such as Split Horizon, Failover or
+Query Logs, and get or set their config so it can be kept as code.`,
+}
+
+var appsListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List the installed apps, or those of the DNS App Store",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		client := api.New()
+		if appsStore {
+			apps, err := client.ListStoreApps(cmd.Context())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+				os.Exit(1)
+			}
+			renderList(apps, apps, func() {
+				fmt.Println(bold("DNS App Store:"))
+				for _, a := range apps {
+					state := ""
+					switch {
+					case a.UpdateAvailable:
+						state = " " + yellow(fmt.Sprintf("(%s installed, update available)", a.InstalledVersion))
+					case a.Installed:
+						state = " " + green("(installed)")
+					}
+					fmt.Printf("- %s %s%s\n", cyan(a.Name), a.Version, state)
+					if a.Description != "" {
+						fmt.Printf("  %s\n", grey(strings.TrimSpace(a.Description)))
+					}
+				}
+			})
+			return
+		}
+
+		apps, err := client.ListApps(cmd.Context())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		renderList(apps, apps, func() {
+			if len(apps) == 0 {
+				fmt.Println("No apps installed.")
+				return
+			}
+			fmt.Println(bold("Installed Apps:"))
+			for _, a := range apps {
+				update := ""
+				if a.UpdateAvailable {
+					update = " " + yellow(fmt.Sprintf("(update to %s available)", a.UpdateVersion))
+				}
+				fmt.Printf("- %s %s%s\n", cyan(a.Name), a.Version, update)
+				for _, c := range a.Classes {
+					roles := ""
+					if r := appRoles(c); len(r) > 0 {
+						roles = " " + grey("("+strings.Join(r, ", ")+")")
+					}
+					fmt.Printf("  %s%s\n", c.ClassPath, roles)
+				}
+			}
+		})
+	},
+}
+
+var appsInstallCmd = &cobra.Command{
+	Use:     "install [name]",
+	Aliases: []string{"i"},
+	Short:   "Install an app from a zip file or the DNS App Store",
+	Long: `Install the app name from its package, a zip file given with --file.
+Without --file the app is looked up in the DNS App Store and the server
+downloads it from there.
+
+  tdns apps install "Split Horizon" --file SplitHorizonApp.zip`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		app := installApp(cmd, args[0], false)
+		render(app, func() {
+			fmt.Printf("✅ Installed %s %s\n", app.Name, app.Version)
+		})
+	},
+}
+
+var appsUpdateCmd = &cobra.Command{
+	Use:     "update [name]",
+	Aliases: []string{"up"},
+	Short:   "Update an app from a zip file or the DNS App Store",
+	Long: `Update the installed app name from a zip file given with --file, or else
+from the DNS App Store when it has a newer version. The app's config is kept.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		app := installApp(cmd, args[0], true)
+		if app == nil {
+			render(map[string]string{"name": args[0], "result": "up to date"}, func() {
+				fmt.Printf("✅ %s is up to date.\n", args[0])
+			})
+			return
+		}
+		render(app, func() {
+			fmt.Printf("✅ Updated %s to %s\n", app.Name, app.Version)
+		})
+	},
+}
+
+var appsUninstallCmd = &cobra.Command{
+	Use:     "uninstall [name]",
+	Aliases: []string{"rm"},
+	Short:   "Uninstall an app and delete its config",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if !confirmed(fmt.Sprintf("Are you sure you want to uninstall %s and delete its config?", args[0])) {
+			return
+		}
+		if err := api.New().UninstallApp(cmd.Context(), args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		render(map[string]string{"name": args[0], "result": "uninstalled"}, func() {
+			fmt.Printf("✅ Uninstalled %s\n", args[0])
+		})
+	},
+}
+
+var appsConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Get or set the config of an app",
+	Long: `Get or set the JSON config of an installed app. Together they let an app's
+config live in version control:
+
+  tdns apps config get Failover --file failover.json
+  tdns apps config set Failover --file failover.json`,
+}
+
+var appsConfigGetCmd = &cobra.Command{
+	Use:   "get [name]",
+	Short: "Print an app's config, or write it to --file",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := api.New().GetAppConfig(cmd.Context(), args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		if config == "" {
+			fmt.Fprintf(os.Stderr, "⚠️  %s has no config.\n", args[0])
+			return
+		}
+		if !strings.HasSuffix(config, "\n") {
+			config += "\n"
+		}
+
+		if appConfigFile == "" || appConfigFile == "-" {
+			fmt.Print(config)
+			return
+		}
+		// App configs can hold credentials, such as a database password,
+		// so the file is readable by the owner only.
+		if err := os.WriteFile(appConfigFile, []byte(config), 0o600); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		if err := os.Chmod(appConfigFile, 0o600); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		render(map[string]string{"name": args[0], "file": appConfigFile}, func() {
+			fmt.Printf("✅ Saved the config of %s to %s\n", args[0], appConfigFile)
+		})
+	},
+}
+
+var appsConfigSetCmd = &cobra.Command{
+	Use:   "set [name]",
+	Short: "Replace an app's config with --file, or stdin",
+	Long: `Replace the config of an app with the JSON of --file, or of stdin when
+--file is not given or is -. The app reloads its config straight away.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var data []byte
+		var err error
+		source := appConfigFile
+		if appConfigFile == "" || appConfigFile == "-" {
+			data, err = io.ReadAll(os.Stdin)
+			source = "stdin"
+		} else {
+			data, err = os.ReadFile(appConfigFile)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		if !json.Valid(data) {
+			fmt.Fprintf(os.Stderr, "❌ %s: not valid JSON\n", source)
+			os.Exit(1)
+		}
+
+		if err := api.New().SetAppConfig(cmd.Context(), args[0], string(data)); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		render(map[string]string{"name": args[0], "result": "updated"}, func() {
+			fmt.Printf("✅ Updated the config of %s from %s\n", args[0], source)
+		})
+	},
+}
+
+func init() {
+	appsListCmd.Flags().BoolVar(&appsStore, "store", false, "List the apps of the DNS App Store instead")
+	for _, c := range []*cobra.Command{appsInstallCmd, appsUpdateCmd} {
+		c.Flags().StringVarP(&appFile, "file", "f", "", "App package (zip file) to upload")
+	}
+	appsUninstallCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Assume yes when asking for confirmation")
+	appsConfigGetCmd.Flags().StringVarP(&appConfigFile, "file", "f", "", "Write the config to this file")
+	appsConfigSetCmd.Flags().StringVarP(&appConfigFile, "file", "f", "", "Read the config from this file (default stdin)")
+	appsConfigCmd.AddCommand(appsConfigGetCmd, appsConfigSetCmd)
+	appsCmd.AddCommand(appsListCmd, appsInstallCmd, appsUpdateCmd, appsUninstallCmd, appsConfigCmd)
+	rootCmd.AddCommand(appsCmd)
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"tdns/pkg/technitium"
+)
+
+func TestAppRoles(t *testing.T) {
+	got := appRoles(technitium.AppClass{IsAppRecordRequestHandler: true, IsQueryLogger: true})
+	if want := []string{"APP records", "query logger"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("roles = %v, want %v", got, want)
+	}
+}
+
+func TestAppsUpdateUpToDate(t *testing.T) {
+	out, err := runWithStub(t, `{"status":"ok","response":{"storeApps":[
+		{"name":"Failover","version":"7.0","url":"https://download.technitium.com/dns/apps/FailoverApp.zip","installed":true,"installedVersion":"7.0"}]}}`,
+		"apps", "update", "failover")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "failover is up to date") {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestAppsConfigSet(t *testing.T) {
+	var name, config string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/apps/config/set" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		r.ParseForm()
+		name, config = r.URL.Query().Get("name"), r.PostForm.Get("config")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"status":"ok","response":{}}`)
+	}))
+	defer srv.Close()
+
+	oldHost := viper.GetString("host")
+	viper.Set("host", srv.URL)
+	defer viper.Set("host", oldHost)
+	resetOutput(t)
+	t.Cleanup(func() { appConfigFile = "" })
+
+	file := filepath.Join(t.TempDir(), "split-horizon.json")
+	want := "{\n  \"enableAddressTranslation\": false\n}\n"
+	if err := os.WriteFile(file, []byte(want), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	oldStdout := os.Stdout
+	rp, wp, _ := os.Pipe()
+	os.Stdout = wp
+	defer func() { os.Stdout = oldStdout }()
+	go func() { _, _ = io.Copy(io.Discard, rp) }()
+
+	rootCmd.SetArgs([]string{"apps", "config", "set", "Split Horizon", "--file", file})
+	defer rootCmd.SetArgs(nil)
+	err := rootCmd.Execute()
+	wp.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "Split Horizon" || config != want {
+		t.Errorf("name = %q, config = %q", name, config)
+	}
+}
+
+func TestAppsConfigGetWritesPrivateFile(t *testing.T) {
+	t.Cleanup(func() { appConfigFile = "" })
+	file := filepath.Join(t.TempDir(), "mysql.json")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := runWithStub(t, `{"status":"ok","response":{"config":"{\"password\": \"s3cret\"}"}}`,
+		"apps", "config", "get", "Query Logs (MySQL)", "--file", file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(file)
+	if err != nil || fi.Mode().Perm() != 0o600 {
+		t.Errorf("config file mode = %v, want 0600 (%v)", fi.Mode().Perm(), err)
+	}
+}
@@ -0,0 +1,304 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"tdns/internal/api"
+	"tdns/pkg/technitium"
+)
+
+var (
+	blockListAllow    bool
+	blockListInterval int
+	blockingPauseFor  time.Duration
+	blockingNoWait    bool
+)
+
+// blockListEntry is a block list subscription as listed by `blocklists list`.
+type blockListEntry struct {
+	URL       string `json:"url"`
+	AllowList bool   `json:"allowList"`
+}
+
+// blockListInfo is the structured output of `blocklists list`.
+type blockListInfo struct {
+	EnableBlocking      bool             `json:"enableBlocking"`
+	BlockingType        string           `json:"blockingType"`
+	UpdateIntervalHours int              `json:"updateIntervalHours"`
+	PausedUntil         string           `json:"pausedUntil,omitempty"`
+	Lists               []blockListEntry `json:"lists"`
+}
+
+func blockListEntries(urls []string) []blockListEntry {
+	out := make([]blockListEntry, 0, len(urls))
+	for _, u := range urls {
+		e := blockListEntry{URL: u}
+		if rest, ok := strings.CutPrefix(u, technitium.AllowListPrefix); ok {
+			e.URL, e.AllowList = rest, true
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// addBlockLists returns urls with each of add appended unless already there.
+// allow marks the added URLs as allow lists.
+func addBlockLists(urls, add []string, allow bool) (out, added []string) {
+	out = append([]string{}, urls...)
+	have := map[string]bool{}
+	for _, e := range blockListEntries(urls) {
+		have[e.URL] = true
+	}
+	for _, u := range add {
+		if have[u] {
+			continue
+		}
+		have[u] = true
+		if allow {
+			u = technitium.AllowListPrefix + u
+		}
+		out = append(out, u)
+		added = append(added, u)
+	}
+	return out, added
+}
+
+// removeBlockLists returns urls without remove, which may be given with or
+// without the allow list prefix. It fails when one of remove isn't in urls.
+func removeBlockLists(urls, remove []string) ([]string, error) {
+	drop := map[string]bool{}
+	for _, u := range remove {
+		drop[strings.TrimPrefix(u, technitium.AllowListPrefix)] = false
+	}
+	var out []string
+	for i, e := range blockListEntries(urls) {
+		if _, ok := drop[e.URL]; ok {
+			drop[e.URL] = true
+			continue
+		}
+		out = append(out, urls[i])
+	}
+	for _, u := range remove {
+		if !drop[strings.TrimPrefix(u, technitium.AllowListPrefix)] {
+			return nil, fmt.Errorf("%s is not a configured block list", u)
+		}
+	}
+	return out, nil
+}
+
+// pauseMinutes converts --for to the whole minutes the API takes, rounding
+// up.
+func pauseMinutes(d time.Duration) (int, error) {
+	if d <= 0 {
+		return 0, fmt.Errorf("--for must be positive")
+	}
+	return int(math.Ceil(d.Minutes())), nil
+}
+
+var blockListsCmd = &cobra.Command{
+	Use:     "blocklists",
+	Aliases: []string{"bl"},
+	Short:   "Manage block list subscriptions",
+	Long: `Manage the block list URLs the server downloads blocked domains from, and
+how often it downloads them again. Lists marked as allow lists hold domains to
+allow instead. Changes are saved with the server settings.`,
+}
+
+var blockListsListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List the block list URLs and the blocking settings",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		s, err := api.New().GetSettings(cmd.Context())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		info := blockListInfo{
+			EnableBlocking:      s.EnableBlocking,
+			BlockingType:        s.BlockingType,
+			UpdateIntervalHours: s.BlockListURLUpdateIntervalHours,
+			PausedUntil:         s.TemporaryDisableBlockingTill,
+			Lists:               blockListEntries(s.BlockListURLs),
+		}
+
+		renderList(info, info.Lists, func() {
+			status := green("enabled")
+			if !info.EnableBlocking {
+				status = yellow("disabled")
+			}
+			if info.PausedUntil != "" {
+				status = yellow("paused until " + info.PausedUntil)
+			}
+			fmt.Printf("%s %s (%s), lists updated every %dh\n\n", bold("Blocking:"), status, info.BlockingType, info.UpdateIntervalHours)
+			if len(info.Lists) == 0 {
+				fmt.Println("No block lists configured.")
+				return
+			}
+			fmt.Println(bold("Block Lists:"))
+			for _, e := range info.Lists {
+				kind := ""
+				if e.AllowList {
+					kind = " " + grey("(allow list)")
+				}
+				fmt.Printf("- %s%s\n", cyan(e.URL), kind)
+			}
+		})
+	},
+}
+
+var blockListsAddCmd = &cobra.Command{
+	Use:     "add [url]...",
+	Aliases: []string{"a"},
+	Short:   "Subscribe to block lists",
+	Long: `Add block list URLs to the server's subscriptions. URLs already configured
+are skipped. With --allow the URLs are added as allow lists. --interval also
+sets how often, in hours, the lists are downloaded again. Run
+'tdns blocklists update' to download a new list straight away.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := api.New()
+		s, err := client.GetSettings(cmd.Context())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		urls, added := addBlockLists(s.BlockListURLs, args, blockListAllow)
+		if len(added) == 0 && blockListInterval <= 0 {
+			render(map[string]interface{}{"added": []string{}}, func() {
+				fmt.Println("✅ Block lists already configured; nothing to do.")
+			})
+			return
+		}
+		if err := client.SetBlockLists(cmd.Context(), urls, blockListInterval); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		render(map[string]interface{}{"added": append([]string{}, added...)}, func() {
+			for _, u := range added {
+				fmt.Printf("✅ Added %s\n", strings.TrimPrefix(u, technitium.AllowListPrefix))
+			}
+			if blockListInterval > 0 {
+				fmt.Printf("✅ Update interval set to %dh\n", blockListInterval)
+			}
+		})
+	},
+}
+
+var blockListsRemoveCmd = &cobra.Command{
+	Use:     "remove [url]...",
+	Aliases: []string{"rm"},
+	Short:   "Unsubscribe from block lists",
+	Args:    cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := api.New()
+		s, err := client.GetSettings(cmd.Context())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		urls, err := removeBlockLists(s.BlockListURLs, args)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		if err := client.SetBlockLists(cmd.Context(), urls, blockListInterval); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		render(map[string]interface{}{"removed": args}, func() {
+			for _, u := range args {
+				fmt.Printf("✅ Removed %s\n", u)
+			}
+		})
+	},
+}
+
+var blockListsUpdateCmd = &cobra.Command{
+	Use:     "update",
+	Aliases: []string{"up"},
+	Short:   "Download the block lists again now",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := api.New().ForceUpdateBlockLists(cmd.Context()); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		render(map[string]string{"result": "updating"}, func() {
+			fmt.Println("✅ Block list update started; the server downloads the lists in the background.")
+		})
+	},
+}
+
+var blockingCmd = &cobra.Command{
+	Use:   "blocking",
+	Short: "Control domain blocking",
+}
+
+var blockingPauseCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "Turn blocking off for a while",
+	Long: `Turn blocking off for --for (rounded up to whole minutes); the server turns
+it back on by itself. On a terminal the time left is counted down until then;
+interrupting the countdown doesn't end the pause. --no-wait returns straight
+away.
+
+  tdns blocking pause --for 10m`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		minutes, err := pauseMinutes(blockingPauseFor)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		till, err := api.New().TemporaryDisableBlocking(cmd.Context(), minutes)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		render(map[string]interface{}{"minutes": minutes, "until": till.Format(time.RFC3339)}, func() {
+			fmt.Printf("⏸  Blocking paused until %s\n", till.Local().Format(time.TimeOnly))
+			if blockingNoWait || !term.IsTerminal(int(os.Stdout.Fd())) {
+				return
+			}
+			ctx := cmd.Context()
+			tick := time.NewTicker(time.Second)
+			defer tick.Stop()
+			for left := time.Until(till); left > 0; left = time.Until(till) {
+				fmt.Printf("\r   Resumes in %s   ", left.Round(time.Second))
+				select {
+				case <-ctx.Done():
+					fmt.Printf("\n   Blocking stays paused until %s.\n", till.Local().Format(time.TimeOnly))
+					return
+				case <-tick.C:
+				}
+			}
+			fmt.Println("\r▶️  Blocking resumed.          ")
+		})
+	},
+}
+
+func init() {
+	blockListsAddCmd.Flags().BoolVar(&blockListAllow, "allow", false, "Add the URLs as allow lists")
+	for _, c := range []*cobra.Command{blockListsAddCmd, blockListsRemoveCmd} {
+		c.Flags().IntVar(&blockListInterval, "interval", 0, "Also set the update interval, in hours")
+	}
+	blockListsCmd.AddCommand(blockListsListCmd, blockListsAddCmd, blockListsRemoveCmd, blockListsUpdateCmd)
+	rootCmd.AddCommand(blockListsCmd)
+
+	blockingPauseCmd.Flags().DurationVar(&blockingPauseFor, "for", 5*time.Minute, "How long to pause blocking for")
+	blockingPauseCmd.Flags().BoolVar(&blockingNoWait, "no-wait", false, "Don't count down until blocking resumes")
+	blockingCmd.AddCommand(blockingPauseCmd)
+	rootCmd.AddCommand(blockingCmd)
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAddBlockLists(t *testing.T) {
+	urls := []string{"https://a.example/list.txt", "!https://b.example/allow.txt"}
+	got, added := addBlockLists(urls, []string{"https://b.example/allow.txt", "https://c.example/list.txt"}, true)
+	want := []string{"https://a.example/list.txt", "!https://b.example/allow.txt", "!https://c.example/list.txt"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("urls = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(added, []string{"!https://c.example/list.txt"}) {
+		t.Errorf("added = %v", added)
+	}
+}
+
+func TestRemoveBlockLists(t *testing.T) {
+	urls := []string{"https://a.example/list.txt", "!https://b.example/allow.txt"}
+	got, err := removeBlockLists(urls, []string{"https://b.example/allow.txt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, []string{"https://a.example/list.txt"}) {
+		t.Errorf("urls = %v", got)
+	}
+	if _, err := removeBlockLists(urls, []string{"https://z.example/list.txt"}); err == nil {
+		t.Error("removing an unknown URL succeeded")
+	}
+}
+
+func TestPauseMinutes(t *testing.T) {
+	for d, want := range map[time.Duration]int{10 * time.Minute: 10, 90 * time.Second: 2, time.Second: 1, 2 * time.Hour: 120} {
+		if got, err := pauseMinutes(d); err != nil || got != want {
+			t.Errorf("pauseMinutes(%s) = %d, %v; want %d", d, got, err, want)
+		}
+	}
+	if _, err := pauseMinutes(0); err == nil {
+		t.Error("pauseMinutes(0) succeeded")
+	}
+}
+
+func TestBlockingPause(t *testing.T) {
+	out, err := runWithStub(t, `{"status":"ok","response":{"temporaryDisableBlockingTill":"2024-05-01T10:15:00Z"}}`,
+		"blocking", "pause", "--for", "15m", "-o", "json")
+	if err != nil {
+		t.Fatalf("blocking pause: %v", err)
+	}
+	if !strings.Contains(out, `"minutes": 15`) || !strings.Contains(out, `"until": "2024-05-01T10:15:00Z"`) {
+		t.Errorf("output:\n%s", out)
+	}
+}
+
+func TestBlockListsList(t *testing.T) {
+	out, err := runWithStub(t, `{"status":"ok","response":{"enableBlocking":true,"blockingType":"NxDomain",
+		"blockListUrlUpdateIntervalHours":24,"blockListUrls":["https://a.example/list.txt","!https://b.example/allow.txt"]}}`,
+		"blocklists", "list")
+	if err != nil {
+		t.Fatalf("blocklists list: %v", err)
+	}
+	for _, want := range []string{"enabled", "NxDomain", "24h", "https://a.example/list.txt", "https://b.example/allow.txt", "(allow list)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output is missing %q:\n%s", want, out)
+		}
+	}
+}
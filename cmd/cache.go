@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"tdns/internal/api"
+	"tdns/pkg/technitium"
+)
+
+// cacheRecordLine formats a cached record like a zone file line.
+func cacheRecordLine(r technitium.CachedRecord) string {
+	keys := make([]string, 0, len(r.RData))
+	for k := range r.RData {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	values := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values = append(values, rdataString(r.RData[k]))
+	}
+	line := fmt.Sprintf("%s %s %s %s", r.Name, grey(fmt.Sprintf("%d", r.TTL)), blue(r.Type), strings.Join(values, " "))
+	if r.DNSSECStatus != "" && r.DNSSECStatus != "Disabled" {
+		line += " " + yellow(r.DNSSECStatus)
+	}
+	return line
+}
+
+var cacheCmd = &cobra.Command{
+	Use:     "cache",
+	Aliases: []string{"ca"},
+	Short:   "Browse and flush the DNS server's cache",
+}
+
+var cacheListCmd = &cobra.Command{
+	Use:     "list [domain]",
+	Aliases: []string{"ls"},
+	Short:   "List the cached records of a domain and its cached subdomains",
+	Long: `List what the server has cached for a domain: its records, with their
+remaining TTL, and the subdomains that have cached data, which can be listed
+in turn. Without a domain the top-level domains in the cache are listed.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		domain := ""
+		if len(args) == 1 {
+			domain = strings.TrimSuffix(args[0], ".")
+		}
+		list, err := api.New().ListCache(cmd.Context(), domain)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		renderList(list, list.Records, func() {
+			name := list.Domain
+			if name == "" {
+				name = "."
+			}
+			fmt.Printf("%s %s\n", bold("Cache:"), cyan(name))
+			if len(list.Records) == 0 && len(list.Zones) == 0 {
+				fmt.Println("Nothing cached.")
+				return
+			}
+			if len(list.Records) > 0 {
+				fmt.Println()
+				for _, r := range list.Records {
+					fmt.Println("  " + cacheRecordLine(r))
+				}
+			}
+			if len(list.Zones) > 0 {
+				fmt.Printf("\n%s\n", bold("Subdomains:"))
+				for _, z := range list.Zones {
+					fmt.Printf("  %s\n", z)
+				}
+			}
+		})
+	},
+}
+
+var cacheDeleteCmd = &cobra.Command{
+	Use:     "delete [domain]",
+	Aliases: []string{"del", "rm"},
+	Short:   "Remove a domain and its subdomains from the cache",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		domain := strings.TrimSuffix(args[0], ".")
+
+		if !confirmed(fmt.Sprintf("Are you sure you want to remove %s and its subdomains from the cache?", domain)) {
+			return
+		}
+
+		if err := api.New().DeleteCached(cmd.Context(), domain); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		render(zoneAction{Zone: domain, Result: "deleted"}, func() {
+			fmt.Printf("✅ Removed %s from the cache.\n", domain)
+		})
+	},
+}
+
+var cacheFlushCmd = &cobra.Command{
+	Use:   "flush",
+	Short: "Empty the whole cache",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !confirmed("Are you sure you want to flush the entire DNS cache?") {
+			return
+		}
+
+		if err := api.New().FlushCache(cmd.Context()); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		render(map[string]string{"result": "flushed"}, func() {
+			fmt.Println("✅ Cache flushed.")
+		})
+	},
+}
+
+func init() {
+	cacheDeleteCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Assume yes when asking for confirmation")
+	cacheFlushCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Assume yes when asking for confirmation")
+	cacheCmd.AddCommand(cacheListCmd)
+	cacheCmd.AddCommand(cacheDeleteCmd)
+	cacheCmd.AddCommand(cacheFlushCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
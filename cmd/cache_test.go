@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCacheList(t *testing.T) {
+	out, err := runWithStub(t, `{"status":"ok","response":{"domain":"example.com","zones":["www"],
+		"records":[{"name":"example.com","type":"MX","ttl":"120 (2 mins)","rData":{"exchange":"mail.example.com","preference":10}}]}}`,
+		"cache", "list", "example.com.")
+	if err != nil {
+		t.Fatalf("cache list: %v", err)
+	}
+	for _, want := range []string{"example.com", "120", "MX", "mail.example.com 10", "Subdomains:", "www.example.com"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output is missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestCacheFlushJSON(t *testing.T) {
+	t.Cleanup(func() { assumeYes = false })
+	out, err := runWithStub(t, `{"status":"ok","response":{}}`, "cache", "flush", "--yes", "-o", "json")
+	if err != nil {
+		t.Fatalf("cache flush: %v", err)
+	}
+	if !strings.Contains(out, `"result": "flushed"`) {
+		t.Errorf("output:\n%s", out)
+	}
+}
@@ -0,0 +1,375 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+)
+
+var (
+	profileUse   bool
+	profileForce bool
+)
+
+// defaultConfigFile is where the config is written when none was loaded.
+const defaultConfigFile = "config.json"
+
+// profileErr is set by initConfig when the selected profile can't be used.
+// Only the commands that manage profiles may run despite it; anything else
+// would silently talk to the wrong server.
+var profileErr error
+
+// profileNamePattern keeps names usable as viper keys, which are case
+// insensitive and use dots as separators.
+var profileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// activeProfile is the profile selected with --profile or TDNS_PROFILE,
+// falling back to the config file's `current` key.
+func activeProfile() string {
+	return profileName(viper.GetViper())
+}
+
+// profileName is activeProfile for a given viper instance.
+func profileName(v *viper.Viper) string {
+	if p := v.GetString("profile"); p != "" {
+		return strings.ToLower(p)
+	}
+	return strings.ToLower(v.GetString("current"))
+}
+
+// applyProfile merges the active profile's settings over the top-level ones
+// of the config file. Flags and environment variables still take precedence,
+// as viper ranks them above config values.
+func applyProfile(v *viper.Viper) error {
+	name := profileName(v)
+	if name == "" {
+		return nil
+	}
+	raw, ok := v.GetStringMap("profiles")[name]
+	if !ok {
+		return fmt.Errorf("profile %q not found in the config file (see 'tdns config list-profiles')", name)
+	}
+	settings, ok := raw.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("profile %q in the config file is not an object", name)
+	}
+	return v.MergeConfigMap(settings)
+}
+
+// managesProfiles reports whether cmd works on the config file itself, so
+// must run even when the selected profile is unusable.
+func managesProfiles(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c == configCmd || c == initCmd {
+			return true
+		}
+	}
+	return false
+}
+
+// configPath is the config file in use, or config.json in the current
+// directory when none was found.
+func configPath() string {
+	if p := viper.ConfigFileUsed(); p != "" {
+		return p
+	}
+	return defaultConfigFile
+}
+
+// readConfigFile returns the raw contents of the config file at path, or an
+// empty config when it does not exist. Unknown keys are kept, so rewriting
+// the file doesn't drop settings this command knows nothing about.
+func readConfigFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]interface{}{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	cfg := map[string]interface{}{}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// writeConfigFile writes cfg to path, readable by the owner only since it
+// holds API tokens.
+func writeConfigFile(path string, cfg map[string]interface{}) error {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		return fmt.Errorf("failed to restrict config file permissions: %w", err)
+	}
+	return nil
+}
+
+// configProfiles returns the `profiles` object of cfg, creating it if needed.
+// Keys are lower-cased to match how viper looks them up.
+func configProfiles(cfg map[string]interface{}) map[string]interface{} {
+	profiles := map[string]interface{}{}
+	if m, ok := cfg["profiles"].(map[string]interface{}); ok {
+		for k, v := range m {
+			profiles[strings.ToLower(k)] = v
+		}
+	}
+	cfg["profiles"] = profiles
+	return profiles
+}
+
+// checkProfileName normalizes name and rejects what viper couldn't look up.
+func checkProfileName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !profileNamePattern.MatchString(name) {
+		return "", fmt.Errorf("invalid profile name %q: use letters, digits, '-' and '_'", name)
+	}
+	return name, nil
+}
+
+// addProfile stores settings as profile name in the config file at path,
+// making it the current profile when makeCurrent is set or it's the first.
+func addProfile(path, name string, settings map[string]interface{}, makeCurrent, force bool) error {
+	cfg, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+	profiles := configProfiles(cfg)
+	if _, exists := profiles[name]; exists && !force {
+		return fmt.Errorf("profile %q already exists in %s (use --force to replace it)", name, path)
+	}
+	profiles[name] = settings
+	if cur, _ := cfg["current"].(string); makeCurrent || cur == "" {
+		cfg["current"] = name
+	}
+	return writeConfigFile(path, cfg)
+}
+
+// promptLine asks question and returns the answer, or def when it's empty.
+func promptLine(in *bufio.Reader, question, def string) (string, error) {
+	if def != "" {
+		fmt.Printf("%s [%s]: ", question, def)
+	} else {
+		fmt.Printf("%s: ", question)
+	}
+	line, err := in.ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+		return "", err
+	}
+	if line = strings.TrimSpace(line); line == "" {
+		return def, nil
+	}
+	return line, nil
+}
+
+// promptSecret reads a secret without echoing it when stdin is a terminal.
+func promptSecret(in *bufio.Reader, question string) (string, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return promptLine(in, question, "")
+	}
+	fmt.Printf("%s: ", question)
+	b, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	return strings.TrimSpace(string(b)), err
+}
+
+// promptProfileSettings asks for the connection settings of a profile.
+func promptProfileSettings(in *bufio.Reader, host string) (map[string]interface{}, error) {
+	host, err := promptLine(in, "API endpoint", host)
+	if err != nil {
+		return nil, err
+	}
+	token, err := promptSecret(in, "API token (leave empty to set it later)")
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"host": host, "token": token}, nil
+}
+
+// profileSettingsFromFlags builds profile settings from the global connection
+// flags given on the command line.
+func profileSettingsFromFlags(cmd *cobra.Command) map[string]interface{} {
+	settings := map[string]interface{}{}
+	flags := cmd.Flags()
+	if flags.Changed("endpoint") {
+		settings["host"], _ = flags.GetString("endpoint")
+	}
+	if flags.Changed("token") {
+		settings["token"], _ = flags.GetString("token")
+	}
+	if flags.Changed("legacy-token") {
+		settings["legacy_token"], _ = flags.GetBool("legacy-token")
+	}
+	if flags.Changed("timeout") {
+		d, _ := flags.GetDuration("timeout")
+		settings["timeout"] = d.String()
+	}
+	return settings
+}
+
+var configCmd = &cobra.Command{
+	Use:     "config",
+	Aliases: []string{"cfg"},
+	Short:   "Manage the config file and its server profiles",
+	Long: `Manage the config file and its server profiles.
+
+A profile is a named set of connection settings (host, token, legacy_token,
+timeout) kept under "profiles" in the config file. The profile named by
+--profile, the TDNS_PROFILE environment variable or the file's "current" key
+is applied over the top-level settings; flags and environment variables still
+override it.`,
+}
+
+var configListProfilesCmd = &cobra.Command{
+	Use:     "list-profiles",
+	Aliases: []string{"lp", "ls"},
+	Short:   "List the profiles in the config file",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		path := configPath()
+		cfg, err := readConfigFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		profiles := configProfiles(cfg)
+		if len(profiles) == 0 && !structuredOutput() {
+			fmt.Printf("No profiles defined in %s. Add one with 'tdns config add-profile'.\n", path)
+			return
+		}
+
+		names := make([]string, 0, len(profiles))
+		for name := range profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		type profileEntry struct {
+			Name   string `json:"name"`
+			Host   string `json:"host"`
+			Active bool   `json:"active"`
+		}
+		active := activeProfile()
+		entries := make([]profileEntry, 0, len(names))
+		for _, name := range names {
+			host := ""
+			if p, ok := profiles[name].(map[string]interface{}); ok {
+				host, _ = p["host"].(string)
+			}
+			entries = append(entries, profileEntry{Name: name, Host: host, Active: name == active})
+		}
+
+		renderList(entries, entries, func() {
+			fmt.Printf("Profiles in %s:\n", path)
+			for _, e := range entries {
+				marker := " "
+				if e.Active {
+					marker = green("*")
+				}
+				fmt.Printf("%s %-15s %s\n", marker, e.Name, grey(e.Host))
+			}
+		})
+	},
+}
+
+var configUseProfileCmd = &cobra.Command{
+	Use:     "use-profile [name]",
+	Aliases: []string{"use"},
+	Short:   "Make a profile the current one",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name, err := checkProfileName(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		path := configPath()
+		cfg, err := readConfigFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		if _, ok := configProfiles(cfg)[name]; !ok {
+			fmt.Fprintf(os.Stderr, "❌ Profile %q not found in %s\n", name, path)
+			os.Exit(1)
+		}
+		cfg["current"] = name
+		if err := writeConfigFile(path, cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		render(map[string]string{"current": name}, func() {
+			fmt.Printf("✅ Now using profile '%s'.\n", name)
+		})
+	},
+}
+
+var configAddProfileCmd = &cobra.Command{
+	Use:     "add-profile [name]",
+	Aliases: []string{"add"},
+	Short:   "Add a profile to the config file",
+	Long: `Add a profile to the config file, taking its settings from the --endpoint,
+--token, --legacy-token and --timeout flags:
+
+  tdns config add-profile lab -e http://lab-dns:5380 -t <token>
+
+Without --endpoint the settings are prompted for. The first profile added
+becomes the current one; pass --use to switch to a later one.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name, err := checkProfileName(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		settings := profileSettingsFromFlags(cmd)
+		if _, ok := settings["host"]; !ok {
+			prompted, err := promptProfileSettings(bufio.NewReader(os.Stdin), defaultHost)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Failed to read profile settings: %v\n", err)
+				os.Exit(1)
+			}
+			for k, v := range prompted {
+				if _, set := settings[k]; !set {
+					settings[k] = v
+				}
+			}
+		}
+
+		path := configPath()
+		if err := addProfile(path, name, settings, profileUse, profileForce); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		absPath, _ := filepath.Abs(path)
+		render(map[string]string{"name": name, "config": absPath}, func() {
+			fmt.Printf("✅ Profile '%s' saved to %s\n", name, absPath)
+		})
+	},
+}
+
+func init() {
+	configAddProfileCmd.Flags().BoolVar(&profileUse, "use", false, "Make the new profile the current one")
+	configAddProfileCmd.Flags().BoolVar(&profileForce, "force", false, "Replace the profile if it already exists")
+	configCmd.AddCommand(configListProfilesCmd)
+	configCmd.AddCommand(configUseProfileCmd)
+	configCmd.AddCommand(configAddProfileCmd)
+	rootCmd.AddCommand(configCmd)
+}
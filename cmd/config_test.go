@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+const profilesConfig = `{
+  "host": "http://top-level:5380",
+  "token": "top-token",
+  "current": "prod",
+  "profiles": {
+    "prod": {"host": "https://prod:5380", "token": "prod-token", "timeout": "10s"},
+    "Lab": {"host": "http://lab:5380"}
+  }
+}`
+
+func newProfilesViper(t *testing.T) *viper.Viper {
+	t.Helper()
+	v := viper.New()
+	v.SetConfigType("json")
+	if err := v.ReadConfig(strings.NewReader(profilesConfig)); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestApplyProfileUsesCurrent(t *testing.T) {
+	v := newProfilesViper(t)
+	if err := applyProfile(v); err != nil {
+		t.Fatalf("applyProfile: %v", err)
+	}
+	if got := v.GetString("host"); got != "https://prod:5380" {
+		t.Errorf("host = %q, want the prod profile's", got)
+	}
+	if got := v.GetString("token"); got != "prod-token" {
+		t.Errorf("token = %q, want the prod profile's", got)
+	}
+	if got := v.GetDuration("timeout").String(); got != "10s" {
+		t.Errorf("timeout = %s, want 10s", got)
+	}
+}
+
+func TestApplyProfileSelectedProfileWins(t *testing.T) {
+	v := newProfilesViper(t)
+	v.Set("profile", "LAB")
+	if err := applyProfile(v); err != nil {
+		t.Fatalf("applyProfile: %v", err)
+	}
+	if got := v.GetString("host"); got != "http://lab:5380" {
+		t.Errorf("host = %q, want the lab profile's", got)
+	}
+	// Settings the profile leaves out fall back to the top level.
+	if got := v.GetString("token"); got != "top-token" {
+		t.Errorf("token = %q, want the top-level one", got)
+	}
+}
+
+func TestApplyProfileUnknown(t *testing.T) {
+	v := newProfilesViper(t)
+	v.Set("profile", "staging")
+	if err := applyProfile(v); err == nil || !strings.Contains(err.Error(), `"staging"`) {
+		t.Errorf("want an error naming the missing profile, got %v", err)
+	}
+}
+
+func TestApplyProfileWithoutProfiles(t *testing.T) {
+	v := viper.New()
+	v.Set("host", "http://plain:5380")
+	if err := applyProfile(v); err != nil {
+		t.Fatalf("applyProfile: %v", err)
+	}
+	if got := v.GetString("host"); got != "http://plain:5380" {
+		t.Errorf("host = %q, want it untouched", got)
+	}
+}
+
+func TestAddProfileKeepsOtherSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"host": "http://top:5380", "custom": 1}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := addProfile(path, "lab", map[string]interface{}{"host": "http://lab:5380"}, false, false); err != nil {
+		t.Fatalf("addProfile: %v", err)
+	}
+	if err := addProfile(path, "prod", map[string]interface{}{"host": "https://prod:5380"}, false, false); err != nil {
+		t.Fatalf("addProfile: %v", err)
+	}
+	if err := addProfile(path, "lab", map[string]interface{}{}, false, false); err == nil {
+		t.Error("adding an existing profile without force should fail")
+	}
+
+	cfg, err := readConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg["current"] != "lab" {
+		t.Errorf("current = %v, want the first profile added", cfg["current"])
+	}
+	if cfg["custom"] != float64(1) || cfg["host"] != "http://top:5380" {
+		t.Errorf("other settings were not kept: %v", cfg)
+	}
+	if n := len(configProfiles(cfg)); n != 2 {
+		t.Errorf("got %d profiles, want 2", n)
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0o600 {
+		t.Errorf("config file mode = %v, want 0600 (%v)", fi.Mode().Perm(), err)
+	}
+}
+
+func TestConfigUseProfileCmd(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile(defaultConfigFile, []byte(profilesConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	oldStdout := os.Stdout
+	rp, wp, _ := os.Pipe()
+	os.Stdout = wp
+	defer func() { os.Stdout = oldStdout }()
+	var out bytes.Buffer
+	done := make(chan struct{})
+	go func() { _, _ = io.Copy(&out, rp); close(done) }()
+
+	rootCmd.SetArgs([]string{"config", "use-profile", "lab"})
+	defer rootCmd.SetArgs(nil)
+	err := rootCmd.Execute()
+	wp.Close()
+	<-done
+	if err != nil {
+		t.Fatalf("config use-profile: %v", err)
+	}
+
+	cfg, err := readConfigFile(defaultConfigFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg["current"] != "lab" {
+		t.Errorf("current = %v, want lab", cfg["current"])
+	}
+	if !strings.Contains(out.String(), "Now using profile 'lab'") {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+}
@@ -29,12 +29,14 @@ var convertCmd = &cobra.Command{
 		}
 
 		q := url.Values{"zone": {zone}, "type": {zoneType}}
-		if _, _, err := api.New().GetJSON("/api/zones/convert", q); err != nil {
+		if _, _, err := api.New().GetJSON(cmd.Context(), "/api/zones/convert", q); err != nil {
 			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Printf("✅ Zone %v converted to %v successfully.\n", bold(zone), cyan(zoneType))
+		render(zoneAction{Zone: zone, Result: "converted to " + zoneType}, func() {
+			fmt.Printf("✅ Zone %v converted to %v successfully.\n", bold(zone), cyan(zoneType))
+		})
 	},
 }
 
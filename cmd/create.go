@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
@@ -26,7 +27,7 @@ var validZoneTypes = map[string]bool{
 
 // createZone calls /api/zones/create and returns the domain name reported by
 // the server. It is shared by the `create` command and `import --create`.
-func createZone(client *api.Client, zone, zoneType string, useSoaSerialDateScheme bool, primaryNameServerAddresses string) (string, error) {
+func createZone(ctx context.Context, client *api.Client, zone, zoneType string, useSoaSerialDateScheme bool, primaryNameServerAddresses string) (string, error) {
 	q := url.Values{
 		"zone":                   {zone},
 		"type":                   {zoneType},
@@ -36,7 +37,7 @@ func createZone(client *api.Client, zone, zoneType string, useSoaSerialDateSchem
 		q.Set("primaryNameServerAddresses", primaryNameServerAddresses)
 	}
 
-	_, response, err := client.GetJSON("/api/zones/create", q)
+	_, response, err := client.GetJSON(ctx, "/api/zones/create", q)
 	if err != nil {
 		return "", err
 	}
@@ -74,14 +75,19 @@ var createCmd = &cobra.Command{
 		}
 
 		client := api.New()
+		var done []zoneAction
 		for _, zone := range args {
-			domain, err := createZone(client, zone, zoneType, useSerial, nameServers)
+			domain, err := createZone(cmd.Context(), client, zone, zoneType, useSerial, nameServers)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "❌ Failed to create zone %s: %v\n", zone, err)
 				os.Exit(1)
 			}
-			fmt.Printf("✅ Zone %v created successfully.\n", domain)
+			done = append(done, zoneAction{Zone: domain, Result: "created"})
+			if !structuredOutput() {
+				fmt.Printf("✅ Zone %v created successfully.\n", domain)
+			}
 		}
+		renderList(done, done, func() {})
 	},
 }
 
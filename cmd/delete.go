@@ -17,13 +17,18 @@ var deleteCmd = &cobra.Command{
 	Args:    cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		client := api.New()
+		var done []zoneAction
 		for _, zone := range args {
-			if _, _, err := client.GetJSON("/api/zones/delete", url.Values{"zone": {zone}}); err != nil {
+			if _, _, err := client.GetJSON(cmd.Context(), "/api/zones/delete", url.Values{"zone": {zone}}); err != nil {
 				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
 				os.Exit(1)
 			}
-			fmt.Printf("✅ Zone '%s' deleted successfully.\n", zone)
+			done = append(done, zoneAction{Zone: zone, Result: "deleted"})
+			if !structuredOutput() {
+				fmt.Printf("✅ Zone '%s' deleted successfully.\n", zone)
+			}
 		}
+		renderList(done, done, func() {})
 	},
 }
 
@@ -0,0 +1,442 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"tdns/internal/api"
+)
+
+var (
+	dhcpScopeDataFile string
+	dhcpScopeStdin    bool
+)
+
+// dhcpScopeStringParams are the /api/dhcp/scopes/set parameters that `dhcp
+// scopes set` takes as string flags of the same name.
+var dhcpScopeStringParams = []struct{ name, usage string }{
+	{"newName", "Rename the scope"},
+	{"startingAddress", "First address of the range"},
+	{"endingAddress", "Last address of the range"},
+	{"subnetMask", "Subnet mask, such as 255.255.255.0"},
+	{"leaseTimeDays", "Lease time, days part"},
+	{"leaseTimeHours", "Lease time, hours part"},
+	{"leaseTimeMinutes", "Lease time, minutes part"},
+	{"offerDelayTime", "Milliseconds to wait before sending an offer"},
+	{"domainName", "Domain name given to clients"},
+	{"dnsTtl", "TTL of the DNS records of leases"},
+	{"serverAddress", "Next server (siaddr) address"},
+	{"serverHostName", "Boot server host name"},
+	{"bootFileName", "Boot file name"},
+	{"routerAddress", "Default gateway"},
+	{"pingCheckTimeout", "Milliseconds to wait for a ping reply"},
+	{"pingCheckRetries", "Pings to send before offering an address"},
+}
+
+// dhcpScopeListParams are the list parameters of /api/dhcp/scopes/set, which
+// the flags take comma-separated.
+var dhcpScopeListParams = []struct{ name, usage string }{
+	{"domainSearchList", "Comma-separated domain search list"},
+	{"dnsServers", "Comma-separated DNS servers (ignored with --useThisDnsServer)"},
+	{"winsServers", "Comma-separated WINS servers"},
+	{"ntpServers", "Comma-separated NTP servers"},
+	{"ntpServerDomainNames", "Comma-separated NTP server domain names"},
+	{"tftpServerAddresses", "Comma-separated TFTP servers (option 150)"},
+	{"capwapAcIpAddresses", "Comma-separated CAPWAP access controllers (option 138)"},
+	{"exclusions", "Comma-separated excluded ranges, as start-end"},
+	{"staticRoutes", "Comma-separated static routes, as destination/mask/router"},
+}
+
+// dhcpScopeBoolParams are the boolean parameters of /api/dhcp/scopes/set.
+var dhcpScopeBoolParams = []struct{ name, usage string }{
+	{"useThisDnsServer", "Give this server's address as the DNS server"},
+	{"dnsUpdates", "Add DNS records for leases"},
+	{"dnsOverwriteForDynamicLease", "Overwrite existing DNS records of dynamic leases"},
+	{"pingCheckEnabled", "Ping an address before offering it"},
+	{"allowOnlyReservedLeases", "Only give out reserved leases"},
+	{"blockLocallyAdministeredMacAddresses", "Ignore clients with locally administered MAC addresses"},
+	{"ignoreClientIdentifierOption", "Identify clients by MAC address only"},
+}
+
+// dhcpScopeTables are the scope settings that `dhcp scopes get --output json`
+// shows as lists of objects, with the fields of each object in the order the
+// set API takes them, pipe-separated.
+var dhcpScopeTables = map[string][]string{
+	"exclusions":     {"startingAddress", "endingAddress"},
+	"staticRoutes":   {"destination", "subnetMask", "router"},
+	"vendorInfo":     {"identifier", "information"},
+	"genericOptions": {"code", "value"},
+	"reservedLeases": {"hostName", "hardwareAddress", "address", "comments"},
+}
+
+// dhcpScopeFlagTables are the table settings that have a flag: their rows
+// are comma-separated, and their fields split by these separators.
+var dhcpScopeFlagTables = map[string]string{
+	"exclusions":   "-",
+	"staticRoutes": "/",
+}
+
+// dhcpScopeValue formats a JSON scope setting, as shown by `dhcp scopes get
+// --output json`, as an /api/dhcp/scopes/set parameter.
+func dhcpScopeValue(key string, v interface{}) (string, error) {
+	fields, isTable := dhcpScopeTables[key]
+	list, isList := v.([]interface{})
+	switch {
+	case isList && len(list) == 0:
+		return "false", nil
+	case isTable && isList:
+		var parts []string
+		for _, row := range list {
+			m, ok := row.(map[string]interface{})
+			if !ok {
+				return "", fmt.Errorf("%s: want a list of objects", key)
+			}
+			for _, f := range fields {
+				parts = append(parts, strOrEmpty(m[f]))
+			}
+		}
+		return strings.Join(parts, "|"), nil
+	case v == nil:
+		return "", nil
+	}
+	if _, ok := v.(map[string]interface{}); ok {
+		return "", fmt.Errorf("%s is an object, which can't be set", key)
+	}
+	return queryValue(v), nil
+}
+
+// dhcpScopeListFlag converts the value of a list flag to the API's form:
+// comma-separated, or for tables such as exclusions, given as
+// "10.0.0.1-10.0.0.9,10.0.0.20-10.0.0.29", pipe-separated fields. An empty
+// value clears the list.
+func dhcpScopeListFlag(key, value string) (string, error) {
+	value = joinCSV(value)
+	if value == "" {
+		return "false", nil
+	}
+	sep, ok := dhcpScopeFlagTables[key]
+	if !ok {
+		return value, nil
+	}
+	rows := strings.Split(value, ",")
+	want := len(dhcpScopeTables[key])
+	var parts []string
+	for _, row := range rows {
+		fields := strings.Split(row, sep)
+		if len(fields) != want {
+			return "", fmt.Errorf("invalid --%s entry %q: want %d fields separated by %q", key, row, want, sep)
+		}
+		for _, f := range fields {
+			parts = append(parts, strings.TrimSpace(f))
+		}
+	}
+	return strings.Join(parts, "|"), nil
+}
+
+// dhcpScopeQuery builds the query of /api/dhcp/scopes/set for scope from
+// the JSON input and the flags, which take precedence.
+func dhcpScopeQuery(cmd *cobra.Command, scope string, base map[string]interface{}) (url.Values, error) {
+	q := url.Values{}
+	for k, v := range base {
+		// The scope is named by the argument; a rename is newName.
+		if k == "name" {
+			continue
+		}
+		value, err := dhcpScopeValue(k, v)
+		if err != nil {
+			return nil, err
+		}
+		if value != "" {
+			q.Set(k, value)
+		}
+	}
+	for _, p := range dhcpScopeStringParams {
+		if cmd.Flags().Changed(p.name) {
+			v, _ := cmd.Flags().GetString(p.name)
+			q.Set(p.name, v)
+		}
+	}
+	for _, p := range dhcpScopeListParams {
+		if !cmd.Flags().Changed(p.name) {
+			continue
+		}
+		v, _ := cmd.Flags().GetString(p.name)
+		value, err := dhcpScopeListFlag(p.name, v)
+		if err != nil {
+			return nil, err
+		}
+		q.Set(p.name, value)
+	}
+	for _, p := range dhcpScopeBoolParams {
+		if cmd.Flags().Changed(p.name) {
+			v, _ := cmd.Flags().GetBool(p.name)
+			q.Set(p.name, boolToStr(v))
+		}
+	}
+	if len(q) == 0 {
+		return nil, fmt.Errorf("no settings provided — use flags and/or --data-file/--stdin")
+	}
+	q.Set("name", scope)
+	return q, nil
+}
+
+// printDHCPScope prints the response of /api/dhcp/scopes/get.
+func printDHCPScope(scope map[string]interface{}) {
+	bold := color.New(color.Bold).SprintFunc()
+	blue := color.New(color.FgBlue).SprintFunc()
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	gray := color.New(color.FgHiBlack).SprintFunc()
+
+	fmt.Printf("%s %s\n", bold("Scope:"), blue(str(scope["name"])))
+	fmt.Printf("%s %s - %s\n", bold("Range:"), str(scope["startingAddress"]), str(scope["endingAddress"]))
+	fmt.Printf("%s %s\n", bold("Subnet Mask:"), str(scope["subnetMask"]))
+	fmt.Printf("%s %sd %sh %sm\n", bold("Lease Time:"), str(scope["leaseTimeDays"]), str(scope["leaseTimeHours"]), str(scope["leaseTimeMinutes"]))
+	if v := strOrEmpty(scope["offerDelayTime"]); v != "" && v != "0" {
+		fmt.Printf("%s %sms\n", bold("Offer Delay:"), v)
+	}
+	fmt.Println()
+
+	fmt.Printf("%s %s\n", bold("Gateway:"), str(scope["routerAddress"]))
+	if toBool(scope["useThisDnsServer"]) {
+		fmt.Printf("%s %s\n", bold("DNS Servers:"), green("this server"))
+	} else {
+		printStringSlice("DNS Servers", scope["dnsServers"], gray, 2)
+	}
+	if v := strOrEmpty(scope["domainName"]); v != "" {
+		fmt.Printf("%s %s\n", bold("Domain Name:"), v)
+	}
+	printStringSlice("Domain Search List", scope["domainSearchList"], gray, 2)
+	printStringSlice("WINS Servers", scope["winsServers"], gray, 2)
+	printStringSlice("NTP Servers", scope["ntpServers"], gray, 2)
+	printStringSlice("NTP Server Domain Names", scope["ntpServerDomainNames"], gray, 2)
+	fmt.Println()
+
+	printBool("DNS Updates", scope["dnsUpdates"], green, yellow)
+	if v, ok := scope["dnsTtl"]; ok {
+		fmt.Printf("%s %s\n", bold("DNS TTL:"), str(v))
+	}
+	printBool("Ping Check", scope["pingCheckEnabled"], green, yellow)
+	printBool("Allow Only Reserved Leases", scope["allowOnlyReservedLeases"], yellow, green)
+	fmt.Println()
+
+	printDHCPTable("Exclusions", scope["exclusions"], func(m map[string]interface{}) string {
+		return fmt.Sprintf("%s - %s", str(m["startingAddress"]), str(m["endingAddress"]))
+	})
+	printDHCPTable("Static Routes", scope["staticRoutes"], func(m map[string]interface{}) string {
+		return fmt.Sprintf("%s/%s via %s", str(m["destination"]), str(m["subnetMask"]), str(m["router"]))
+	})
+	printDHCPTable("Reserved Leases", scope["reservedLeases"], func(m map[string]interface{}) string {
+		line := fmt.Sprintf("%s %s %s", blue(str(m["address"])), str(m["hardwareAddress"]), strOrEmpty(m["hostName"]))
+		if c := strOrEmpty(m["comments"]); c != "" {
+			line += " " + gray(c)
+		}
+		return line
+	})
+	printDHCPTable("Vendor Info", scope["vendorInfo"], func(m map[string]interface{}) string {
+		return fmt.Sprintf("%s: %s", str(m["identifier"]), str(m["information"]))
+	})
+	printDHCPTable("Options", scope["genericOptions"], func(m map[string]interface{}) string {
+		return fmt.Sprintf("%s: %s", str(m["code"]), str(m["value"]))
+	})
+
+	if v := strOrEmpty(scope["bootFileName"]); v != "" {
+		fmt.Printf("%s %s (%s %s)\n", bold("Boot File:"), v, strOrEmpty(scope["serverAddress"]), strOrEmpty(scope["serverHostName"]))
+	}
+}
+
+// printDHCPTable prints a list of objects of a scope, one line per row.
+func printDHCPTable(title string, v interface{}, row func(map[string]interface{}) string) {
+	bold := color.New(color.Bold).SprintFunc()
+	rows, ok := v.([]interface{})
+	if !ok {
+		return
+	}
+	fmt.Println(bold(title + ":"))
+	if len(rows) == 0 {
+		fmt.Println("  (none)")
+	}
+	for _, r := range rows {
+		m, _ := r.(map[string]interface{})
+		fmt.Println("  • " + row(m))
+	}
+	fmt.Println()
+}
+
+var dhcpCmd = &cobra.Command{
+	Use:   "dhcp",
+	Short: "Manage the DHCP server",
+}
+
+var dhcpScopesCmd = &cobra.Command{
+	Use:     "scopes",
+	Aliases: []string{"scope", "sc"},
+	Short:   "Manage DHCP scopes",
+}
+
+var dhcpScopesListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List the DHCP scopes",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		scopes, err := api.New().ListDHCPScopes(cmd.Context())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		renderList(scopes, scopes, func() {
+			if len(scopes) == 0 {
+				fmt.Println("No DHCP scopes found.")
+				return
+			}
+			for _, s := range scopes {
+				status := green("Enabled")
+				if !s.Enabled {
+					status = grey("Disabled")
+				}
+				fmt.Printf("%s (%s)\n", bold(s.Name), status)
+				fmt.Printf("  Range: %s - %s\n", s.StartingAddress, s.EndingAddress)
+				fmt.Printf("  Network: %s/%s\n", s.NetworkAddress, s.SubnetMask)
+				if s.InterfaceAddress != "" {
+					fmt.Printf("  Interface: %s\n", s.InterfaceAddress)
+				}
+				fmt.Println()
+			}
+		})
+	},
+}
+
+var dhcpScopesGetCmd = &cobra.Command{
+	Use:     "get [scope]",
+	Aliases: []string{"ge"},
+	Short:   "Show the configuration of a DHCP scope",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		_, scope, err := api.New().GetJSON(cmd.Context(), "/api/dhcp/scopes/get", url.Values{"name": {args[0]}})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		render(scope, func() { printDHCPScope(scope) })
+	},
+}
+
+var dhcpScopesSetCmd = &cobra.Command{
+	Use:   "set [scope]",
+	Short: "Create a DHCP scope or change its settings",
+	Long: `Change the settings of a DHCP scope with /api/dhcp/scopes/set, creating the
+scope if it doesn't exist (a new scope needs --startingAddress, --endingAddress
+and --subnetMask). Settings are given as flags named like the API parameters,
+or as a JSON object with --data-file or --stdin, such as an edited copy of
+'tdns dhcp scopes get <scope> --output json'; flags override the JSON.
+Settings not given are left unchanged, and an empty list clears a list.
+
+  tdns dhcp scopes set Office --startingAddress 10.0.0.100 --endingAddress 10.0.0.199 \
+    --subnetMask 255.255.255.0 --routerAddress 10.0.0.1 --useThisDnsServer
+  tdns dhcp scopes set Office --exclusions 10.0.0.150-10.0.0.159
+  tdns dhcp scopes set Office --data-file office-scope.json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		base, err := readJSONInput(dhcpScopeDataFile, dhcpScopeStdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		q, err := dhcpScopeQuery(cmd, args[0], base)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		// Reservations and options make a scope too long for a URL, and
+		// shouldn't end up in access logs, so it is posted.
+		_, response, err := api.New().PostFormJSON(cmd.Context(), "/api/dhcp/scopes/set", q)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		name := args[0]
+		if n := q.Get("newName"); n != "" {
+			name = n
+		}
+		render(response, func() {
+			fmt.Printf("✅ DHCP scope %s saved.\n", name)
+		})
+	},
+}
+
+var dhcpScopesEnableCmd = &cobra.Command{
+	Use:     "enable [scope]",
+	Aliases: []string{"en"},
+	Short:   "Start serving leases from a DHCP scope",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := api.New().EnableDHCPScope(cmd.Context(), args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		render(map[string]string{"scope": args[0], "result": "enabled"}, func() {
+			fmt.Printf("✅ DHCP scope %s enabled.\n", args[0])
+		})
+	},
+}
+
+var dhcpScopesDisableCmd = &cobra.Command{
+	Use:     "disable [scope]",
+	Aliases: []string{"dis"},
+	Short:   "Stop serving leases from a DHCP scope",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := api.New().DisableDHCPScope(cmd.Context(), args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		render(map[string]string{"scope": args[0], "result": "disabled"}, func() {
+			fmt.Printf("✅ DHCP scope %s disabled.\n", args[0])
+		})
+	},
+}
+
+var dhcpScopesDeleteCmd = &cobra.Command{
+	Use:     "delete [scope]",
+	Aliases: []string{"del", "rm"},
+	Short:   "Delete a DHCP scope and its leases",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if !confirmed(fmt.Sprintf("Are you sure you want to delete DHCP scope %s and its leases?", args[0])) {
+			return
+		}
+		if err := api.New().DeleteDHCPScope(cmd.Context(), args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		render(map[string]string{"scope": args[0], "result": "deleted"}, func() {
+			fmt.Printf("✅ DHCP scope %s deleted.\n", args[0])
+		})
+	},
+}
+
+func init() {
+	dhcpScopesSetCmd.Flags().StringVarP(&dhcpScopeDataFile, "data-file", "f", "", "Path to a JSON object of scope settings")
+	dhcpScopesSetCmd.Flags().BoolVar(&dhcpScopeStdin, "stdin", false, "Read a JSON object of scope settings from stdin")
+	for _, p := range append(dhcpScopeStringParams, dhcpScopeListParams...) {
+		dhcpScopesSetCmd.Flags().String(p.name, "", p.usage)
+	}
+	for _, p := range dhcpScopeBoolParams {
+		dhcpScopesSetCmd.Flags().Bool(p.name, false, p.usage)
+	}
+	dhcpScopesDeleteCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Assume yes when asking for confirmation")
+
+	dhcpScopesCmd.AddCommand(dhcpScopesListCmd, dhcpScopesGetCmd, dhcpScopesSetCmd, dhcpScopesEnableCmd, dhcpScopesDisableCmd, dhcpScopesDeleteCmd)
+	dhcpCmd.AddCommand(dhcpScopesCmd)
+	rootCmd.AddCommand(dhcpCmd)
+}
@@ -0,0 +1,318 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"tdns/internal/api"
+	"tdns/pkg/technitium"
+)
+
+var (
+	dhcpLeaseScope string
+	dhcpLeaseSort  string
+
+	dhcpReservedScope    string
+	dhcpReservedMAC      string
+	dhcpReservedIP       string
+	dhcpReservedHostName string
+	dhcpReservedComments string
+)
+
+// leaseTimeLayouts are the formats the server has used for lease times.
+var leaseTimeLayouts = []string{time.RFC3339, "01/02/2006 15:04:05", "1/2/2006 3:04:05 PM", "2006-01-02 15:04:05"}
+
+func parseLeaseTime(s string) (time.Time, bool) {
+	for _, layout := range leaseTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// normalizeMAC formats a MAC address the way the DHCP API does, as
+// 00-11-22-33-44-55. Colons, dashes and dots are accepted as separators.
+func normalizeMAC(s string) (string, error) {
+	digits := strings.NewReplacer(":", "", "-", "", ".", "").Replace(strings.TrimSpace(s))
+	b, err := hex.DecodeString(digits)
+	if err != nil || len(b) != 6 {
+		return "", fmt.Errorf("invalid MAC address %q", s)
+	}
+	parts := make([]string, len(b))
+	for i, x := range b {
+		parts[i] = fmt.Sprintf("%02X", x)
+	}
+	return strings.Join(parts, "-"), nil
+}
+
+// sortLeases sorts leases by address, hostname or expiry. Leases with the
+// same key are ordered by address.
+func sortLeases(leases []technitium.DHCPLease, by string) error {
+	addr := func(l technitium.DHCPLease) netip.Addr {
+		a, _ := netip.ParseAddr(l.Address)
+		return a
+	}
+	byAddress := func(i, j int) bool { return addr(leases[i]).Less(addr(leases[j])) }
+	var less func(i, j int) bool
+	switch strings.ToLower(by) {
+	case "address", "ip":
+		less = byAddress
+	case "hostname", "host":
+		less = func(i, j int) bool {
+			a, b := strings.ToLower(leases[i].HostName), strings.ToLower(leases[j].HostName)
+			if a != b {
+				return a < b
+			}
+			return byAddress(i, j)
+		}
+	case "expiry", "expires":
+		less = func(i, j int) bool {
+			a, aok := parseLeaseTime(leases[i].LeaseExpires)
+			b, bok := parseLeaseTime(leases[j].LeaseExpires)
+			if aok && bok && !a.Equal(b) {
+				return a.Before(b)
+			}
+			if aok != bok {
+				return aok
+			}
+			return byAddress(i, j)
+		}
+	default:
+		return fmt.Errorf("unknown --sort %q (use address, hostname or expiry)", by)
+	}
+	sort.SliceStable(leases, less)
+	return nil
+}
+
+// findLease returns the lease whose address or MAC address is key, in scope
+// unless scope is empty.
+func findLease(leases []technitium.DHCPLease, key, scope string) (technitium.DHCPLease, error) {
+	mac, macErr := normalizeMAC(key)
+	var found []technitium.DHCPLease
+	for _, l := range leases {
+		if scope != "" && !strings.EqualFold(l.Scope, scope) {
+			continue
+		}
+		if l.Address == key || (macErr == nil && strings.EqualFold(l.HardwareAddress, mac)) {
+			found = append(found, l)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return technitium.DHCPLease{}, fmt.Errorf("no lease found for %s", key)
+	case 1:
+		return found[0], nil
+	}
+	scopes := make([]string, 0, len(found))
+	for _, l := range found {
+		scopes = append(scopes, l.Scope)
+	}
+	return technitium.DHCPLease{}, fmt.Errorf("%s has leases in several scopes (%s); choose one with --scope", key, strings.Join(scopes, ", "))
+}
+
+// printLeases prints leases one per line, in aligned columns.
+func printLeases(leases []technitium.DHCPLease) {
+	wAddr, wMAC, wHost := len("ADDRESS"), len("MAC"), len("HOSTNAME")
+	for _, l := range leases {
+		wAddr = max(wAddr, len(l.Address))
+		wMAC = max(wMAC, len(l.HardwareAddress))
+		wHost = max(wHost, len(l.HostName))
+	}
+	fmt.Println(bold(fmt.Sprintf("%-*s  %-*s  %-*s  %-8s  %-19s  %s", wAddr, "ADDRESS", wMAC, "MAC", wHost, "HOSTNAME", "TYPE", "EXPIRES", "SCOPE")))
+	for _, l := range leases {
+		typ := fmt.Sprintf("%-8s", l.Type)
+		if l.Type == "Reserved" {
+			typ = blue(typ)
+		}
+		fmt.Printf("%s  %-*s  %-*s  %s  %-19s  %s\n", cyan(fmt.Sprintf("%-*s", wAddr, l.Address)), wMAC, l.HardwareAddress,
+			wHost, l.HostName, typ, l.LeaseExpires, grey(l.Scope))
+	}
+}
+
+// leaseAction looks up the lease named by args[0] and passes it to do. With
+// question set, the lease found is confirmed first with the question it
+// returns.
+func leaseAction(cmd *cobra.Command, args []string, result, done string, question func(l technitium.DHCPLease) string, do func(client *api.Client, l technitium.DHCPLease) error) {
+	client := api.New()
+	leases, err := client.ListDHCPLeases(cmd.Context())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+	lease, err := findLease(leases, args[0], dhcpLeaseScope)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+	if question != nil && !confirmed(question(lease)) {
+		return
+	}
+	if err := do(client, lease); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+	render(map[string]string{"scope": lease.Scope, "address": lease.Address, "hardwareAddress": lease.HardwareAddress, "result": result}, func() {
+		fmt.Printf("✅ %s (%s) in scope %s %s.\n", lease.Address, lease.HardwareAddress, lease.Scope, done)
+	})
+}
+
+var dhcpLeasesCmd = &cobra.Command{
+	Use:     "leases",
+	Aliases: []string{"lease", "le"},
+	Short:   "List and manage DHCP leases",
+	Long: `List and manage the leases of the DHCP server. Leases are named by their IP
+or MAC address; --scope picks one when the address has leases in several
+scopes.`,
+}
+
+var dhcpLeasesListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List the DHCP leases",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		all, err := api.New().ListDHCPLeases(cmd.Context())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		leases := []technitium.DHCPLease{}
+		for _, l := range all {
+			if dhcpLeaseScope == "" || strings.EqualFold(l.Scope, dhcpLeaseScope) {
+				leases = append(leases, l)
+			}
+		}
+		if err := sortLeases(leases, dhcpLeaseSort); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		renderList(leases, leases, func() {
+			if len(leases) == 0 {
+				fmt.Println("No DHCP leases found.")
+				return
+			}
+			printLeases(leases)
+		})
+	},
+}
+
+var dhcpLeasesRemoveCmd = &cobra.Command{
+	Use:     "remove [ip|mac]",
+	Aliases: []string{"rm"},
+	Short:   "Remove a lease, freeing its address",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		question := func(l technitium.DHCPLease) string {
+			return fmt.Sprintf("Are you sure you want to remove the lease of %s (%s) in scope %s?", l.Address, l.HardwareAddress, l.Scope)
+		}
+		leaseAction(cmd, args, "removed", "removed", question, func(client *api.Client, l technitium.DHCPLease) error {
+			return client.RemoveDHCPLease(cmd.Context(), l.Scope, l.HardwareAddress)
+		})
+	},
+}
+
+var dhcpLeasesReserveCmd = &cobra.Command{
+	Use:   "reserve [ip|mac]",
+	Short: "Turn a dynamic lease into a reservation",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		leaseAction(cmd, args, "reserved", "is now reserved", nil, func(client *api.Client, l technitium.DHCPLease) error {
+			return client.ConvertToReservedLease(cmd.Context(), l.Scope, l.HardwareAddress)
+		})
+	},
+}
+
+var dhcpLeasesUnreserveCmd = &cobra.Command{
+	Use:   "unreserve [ip|mac]",
+	Short: "Turn a reserved lease back into a dynamic one",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		leaseAction(cmd, args, "dynamic", "is now dynamic", nil, func(client *api.Client, l technitium.DHCPLease) error {
+			return client.ConvertToDynamicLease(cmd.Context(), l.Scope, l.HardwareAddress)
+		})
+	},
+}
+
+var dhcpReservedCmd = &cobra.Command{
+	Use:     "reserved",
+	Aliases: []string{"res"},
+	Short:   "Add and remove reserved leases of a scope",
+	Long: `Add and remove static reservations, which pin an address of a scope to a MAC
+address whether or not the client has a lease yet. 'tdns dhcp scopes get'
+lists a scope's reservations.`,
+}
+
+var dhcpReservedAddCmd = &cobra.Command{
+	Use:     "add",
+	Aliases: []string{"a"},
+	Short:   "Reserve an address for a MAC address",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		mac, err := normalizeMAC(dhcpReservedMAC)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		if _, err := netip.ParseAddr(dhcpReservedIP); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ invalid --ip %q\n", dhcpReservedIP)
+			os.Exit(1)
+		}
+		lease := technitium.ReservedLease{HardwareAddress: mac, Address: dhcpReservedIP, HostName: dhcpReservedHostName, Comments: dhcpReservedComments}
+		if err := api.New().AddReservedLease(cmd.Context(), dhcpReservedScope, lease); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		render(lease, func() {
+			fmt.Printf("✅ Reserved %s for %s in scope %s.\n", lease.Address, mac, dhcpReservedScope)
+		})
+	},
+}
+
+var dhcpReservedRemoveCmd = &cobra.Command{
+	Use:     "remove",
+	Aliases: []string{"rm"},
+	Short:   "Remove the reservation of a MAC address",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		mac, err := normalizeMAC(dhcpReservedMAC)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		if err := api.New().RemoveReservedLease(cmd.Context(), dhcpReservedScope, mac); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		render(map[string]string{"scope": dhcpReservedScope, "hardwareAddress": mac, "result": "removed"}, func() {
+			fmt.Printf("✅ Removed the reservation of %s from scope %s.\n", mac, dhcpReservedScope)
+		})
+	},
+}
+
+func init() {
+	dhcpLeasesCmd.PersistentFlags().StringVar(&dhcpLeaseScope, "scope", "", "Only consider leases of this scope")
+	dhcpLeasesListCmd.Flags().StringVar(&dhcpLeaseSort, "sort", "address", "Sort by address, hostname or expiry")
+	dhcpLeasesRemoveCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Assume yes when asking for confirmation")
+	dhcpLeasesCmd.AddCommand(dhcpLeasesListCmd, dhcpLeasesRemoveCmd, dhcpLeasesReserveCmd, dhcpLeasesUnreserveCmd)
+
+	dhcpReservedCmd.PersistentFlags().StringVar(&dhcpReservedScope, "scope", "", "Scope of the reservation")
+	dhcpReservedCmd.PersistentFlags().StringVar(&dhcpReservedMAC, "mac", "", "MAC address of the client")
+	_ = dhcpReservedCmd.MarkPersistentFlagRequired("scope")
+	_ = dhcpReservedCmd.MarkPersistentFlagRequired("mac")
+	dhcpReservedAddCmd.Flags().StringVar(&dhcpReservedIP, "ip", "", "Address to reserve")
+	dhcpReservedAddCmd.Flags().StringVar(&dhcpReservedHostName, "hostname", "", "Host name of the client")
+	dhcpReservedAddCmd.Flags().StringVar(&dhcpReservedComments, "comments", "", "Comments")
+	_ = dhcpReservedAddCmd.MarkFlagRequired("ip")
+	dhcpReservedCmd.AddCommand(dhcpReservedAddCmd, dhcpReservedRemoveCmd)
+
+	dhcpCmd.AddCommand(dhcpLeasesCmd, dhcpReservedCmd)
+}
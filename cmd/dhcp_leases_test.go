@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"tdns/pkg/technitium"
+)
+
+func TestNormalizeMAC(t *testing.T) {
+	for _, in := range []string{"00:11:22:aa:bb:cc", "00-11-22-AA-BB-CC", "0011.22aa.bbcc", "001122aabbcc"} {
+		if got, err := normalizeMAC(in); err != nil || got != "00-11-22-AA-BB-CC" {
+			t.Errorf("normalizeMAC(%q) = %q, %v", in, got, err)
+		}
+	}
+	for _, in := range []string{"", "00:11:22", "00:11:22:33:44:zz", "10.0.0.5"} {
+		if _, err := normalizeMAC(in); err == nil {
+			t.Errorf("normalizeMAC(%q) was accepted", in)
+		}
+	}
+}
+
+func TestSortLeases(t *testing.T) {
+	leases := []technitium.DHCPLease{
+		{Address: "10.0.0.20", HostName: "b", LeaseExpires: "2026-10-18T10:00:00Z"},
+		{Address: "10.0.0.3", HostName: "", LeaseExpires: "2026-10-18T09:00:00Z"},
+		{Address: "10.0.0.100", HostName: "A", LeaseExpires: "2026-10-19T08:00:00Z"},
+	}
+	addresses := func() string {
+		var out []string
+		for _, l := range leases {
+			out = append(out, l.Address)
+		}
+		return strings.Join(out, " ")
+	}
+	for by, want := range map[string]string{
+		"address":  "10.0.0.3 10.0.0.20 10.0.0.100",
+		"hostname": "10.0.0.3 10.0.0.100 10.0.0.20",
+		"expiry":   "10.0.0.3 10.0.0.20 10.0.0.100",
+	} {
+		if err := sortLeases(leases, by); err != nil {
+			t.Fatal(err)
+		}
+		if got := addresses(); got != want {
+			t.Errorf("sort by %s = %s; want %s", by, got, want)
+		}
+	}
+	if err := sortLeases(leases, "size"); err == nil {
+		t.Error("unknown sort key was accepted")
+	}
+}
+
+func TestFindLease(t *testing.T) {
+	leases := []technitium.DHCPLease{
+		{Scope: "LAN", Address: "10.0.0.5", HardwareAddress: "00-11-22-33-44-55"},
+		{Scope: "Guest", Address: "10.1.0.5", HardwareAddress: "00-11-22-33-44-55"},
+	}
+	if l, err := findLease(leases, "10.1.0.5", ""); err != nil || l.Scope != "Guest" {
+		t.Errorf("by address = %+v, %v", l, err)
+	}
+	if _, err := findLease(leases, "00:11:22:33:44:55", ""); err == nil {
+		t.Error("a MAC with leases in two scopes was not reported as ambiguous")
+	}
+	if l, err := findLease(leases, "00:11:22:33:44:55", "lan"); err != nil || l.Address != "10.0.0.5" {
+		t.Errorf("by MAC in scope = %+v, %v", l, err)
+	}
+	if _, err := findLease(leases, "10.0.0.6", ""); err == nil {
+		t.Error("an unknown address was found")
+	}
+}
+
+func TestDHCPLeasesList(t *testing.T) {
+	t.Cleanup(func() { dhcpLeaseScope, dhcpLeaseSort = "", "address" })
+	out, err := runWithStub(t, `{"status":"ok","response":{"leases":[
+		{"scope":"LAN","type":"Dynamic","hardwareAddress":"00-11-22-33-44-55","address":"10.0.0.9","hostName":"laptop"},
+		{"scope":"Guest","type":"Dynamic","hardwareAddress":"00-11-22-33-44-66","address":"10.1.0.2","hostName":"phone"},
+		{"scope":"LAN","type":"Reserved","hardwareAddress":"00-11-22-33-44-77","address":"10.0.0.10","hostName":"desktop"}]}}`,
+		"dhcp", "leases", "list", "--scope", "LAN", "--sort", "hostname", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []technitium.DHCPLease
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("%v: %s", err, out)
+	}
+	if len(got) != 2 || got[0].HostName != "desktop" || got[1].HostName != "laptop" {
+		t.Errorf("leases = %+v", got)
+	}
+}
+
+func TestDHCPLeasesRemoveConfirmsTheLeaseFound(t *testing.T) {
+	withStdin(t, "no\n")
+	out, err := runWithStub(t, `{"status":"ok","response":{"leases":[
+		{"scope":"LAN","type":"Dynamic","hardwareAddress":"00-11-22-33-44-55","address":"10.0.0.9","hostName":"laptop"}]}}`,
+		"dhcp", "leases", "remove", "00:11:22:33:44:55")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "remove the lease of 10.0.0.9 (00-11-22-33-44-55) in scope LAN?") || !strings.Contains(out, "Aborted") {
+		t.Errorf("output = %q", out)
+	}
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestDHCPScopeValue(t *testing.T) {
+	var scope map[string]interface{}
+	if err := json.Unmarshal([]byte(`{"exclusions":[{"startingAddress":"10.0.0.1","endingAddress":"10.0.0.9"}],
+		"reservedLeases":[{"hostName":"printer","hardwareAddress":"00-11-22-33-44-55","address":"10.0.0.50","comments":null}],
+		"dnsServers":["10.0.0.2","10.0.0.3"],"ntpServers":[],"leaseTimeDays":7,"dnsUpdates":true}`), &scope); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{
+		"exclusions":     "10.0.0.1|10.0.0.9",
+		"reservedLeases": "printer|00-11-22-33-44-55|10.0.0.50|",
+		"dnsServers":     "10.0.0.2,10.0.0.3",
+		"ntpServers":     "false",
+		"leaseTimeDays":  "7",
+		"dnsUpdates":     "true",
+	} {
+		if got, err := dhcpScopeValue(key, scope[key]); err != nil || got != want {
+			t.Errorf("%s = %q, %v; want %q", key, got, err, want)
+		}
+	}
+}
+
+func TestDHCPScopeListFlag(t *testing.T) {
+	for _, tt := range []struct{ key, in, want string }{
+		{"exclusions", "10.0.0.1-10.0.0.9, 10.0.0.20-10.0.0.29", "10.0.0.1|10.0.0.9|10.0.0.20|10.0.0.29"},
+		{"staticRoutes", "172.16.0.0/255.255.0.0/10.0.0.254", "172.16.0.0|255.255.0.0|10.0.0.254"},
+		{"dnsServers", "10.0.0.2, 10.0.0.3", "10.0.0.2,10.0.0.3"},
+		{"exclusions", "", "false"},
+	} {
+		if got, err := dhcpScopeListFlag(tt.key, tt.in); err != nil || got != tt.want {
+			t.Errorf("%s %q = %q, %v; want %q", tt.key, tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := dhcpScopeListFlag("exclusions", "10.0.0.1"); err == nil {
+		t.Error("an exclusion without an end was accepted")
+	}
+}
+
+func TestDHCPScopesSetFlagsOverrideFile(t *testing.T) {
+	var got url.Values
+	var rawQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want the scope posted as a form", r.Method)
+		}
+		r.ParseForm()
+		got, rawQuery = r.PostForm, r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"status":"ok","response":{}}`)
+	}))
+	defer srv.Close()
+
+	oldHost := viper.GetString("host")
+	viper.Set("host", srv.URL)
+	defer viper.Set("host", oldHost)
+	resetOutput(t)
+	t.Cleanup(func() { dhcpScopeDataFile = "" })
+
+	file := filepath.Join(t.TempDir(), "scope.json")
+	if err := os.WriteFile(file, []byte(`{"name":"Old","routerAddress":"10.0.0.1","leaseTimeDays":1,
+		"exclusions":[{"startingAddress":"10.0.0.1","endingAddress":"10.0.0.9"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	oldStdout := os.Stdout
+	rp, wp, _ := os.Pipe()
+	os.Stdout = wp
+	defer func() { os.Stdout = oldStdout }()
+	go func() { _, _ = io.Copy(io.Discard, rp) }()
+
+	rootCmd.SetArgs([]string{"dhcp", "scopes", "set", "Office", "-f", file, "--leaseTimeDays", "3", "--useThisDnsServer"})
+	defer rootCmd.SetArgs(nil)
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("dhcp scopes set: %v", err)
+	}
+	wp.Close()
+
+	for k, want := range map[string]string{
+		"name":             "Office",
+		"routerAddress":    "10.0.0.1",
+		"leaseTimeDays":    "3",
+		"useThisDnsServer": "true",
+		"exclusions":       "10.0.0.1|10.0.0.9",
+	} {
+		if got.Get(k) != want {
+			t.Errorf("%s = %q, want %q", k, got.Get(k), want)
+		}
+	}
+	if strings.Contains(rawQuery, "exclusions") {
+		t.Errorf("the scope is in the URL: %s", rawQuery)
+	}
+}
+
+func TestDHCPScopesGet(t *testing.T) {
+	out, err := runWithStub(t, `{"status":"ok","response":{"name":"Office","startingAddress":"10.0.0.100","endingAddress":"10.0.0.199",
+		"subnetMask":"255.255.255.0","leaseTimeDays":7,"leaseTimeHours":0,"leaseTimeMinutes":0,"routerAddress":"10.0.0.1",
+		"useThisDnsServer":false,"dnsServers":["10.0.0.2"],"exclusions":[{"startingAddress":"10.0.0.150","endingAddress":"10.0.0.159"}],
+		"reservedLeases":[],"genericOptions":[{"code":66,"value":"74:66:74:70"}]}}`, "dhcp", "scopes", "get", "Office")
+	if err != nil {
+		t.Fatalf("dhcp scopes get: %v", err)
+	}
+	for _, want := range []string{"Office", "10.0.0.100 - 10.0.0.199", "7d 0h 0m", "10.0.0.1", "10.0.0.2", "10.0.0.150 - 10.0.0.159", "66: 74:66:74:70"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output is missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestDHCPScopesEnableNamesScope(t *testing.T) {
+	out, err := runWithStub(t, `{"status":"ok","response":{}}`, "dhcp", "scopes", "enable", "Office", "-o", "json")
+	if err != nil {
+		t.Fatalf("dhcp scopes enable: %v", err)
+	}
+	var got map[string]string
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("%v: %s", err, out)
+	}
+	if len(got) != 2 || got["scope"] != "Office" || got["result"] != "enabled" {
+		t.Errorf("output = %v", got)
+	}
+}
@@ -20,13 +20,18 @@ var disableZoneCmd = &cobra.Command{
 		bold := color.New(color.Bold).SprintFunc()
 
 		client := api.New()
+		var done []zoneAction
 		for _, zone := range args {
-			if _, _, err := client.GetJSON("/api/zones/disable", url.Values{"zone": {zone}}); err != nil {
+			if _, _, err := client.GetJSON(cmd.Context(), "/api/zones/disable", url.Values{"zone": {zone}}); err != nil {
 				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
 				os.Exit(1)
 			}
-			fmt.Printf("✅ Zone %v disabled successfully.\n", bold(zone))
+			done = append(done, zoneAction{Zone: zone, Result: "disabled"})
+			if !structuredOutput() {
+				fmt.Printf("✅ Zone %v disabled successfully.\n", bold(zone))
+			}
 		}
+		renderList(done, done, func() {})
 	},
 }
 
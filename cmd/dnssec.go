@@ -0,0 +1,394 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"tdns/internal/api"
+	"tdns/internal/zonefile"
+	"tdns/pkg/technitium"
+)
+
+var (
+	dnssecAlgorithm    string
+	dnssecHash         string
+	dnssecKSKSize      int
+	dnssecZSKSize      int
+	dnssecKeySize      int
+	dnssecCurve        string
+	dnssecNxProof      string
+	dnssecIterations   int
+	dnssecSaltLength   int
+	dnssecKeyTTL       int
+	dnssecRolloverDays int
+	dnssecKeyType      string
+	dnssecDigest       string
+)
+
+// dnssecKeyAlgorithm checks the --algorithm flag and returns it with the
+// curve to use: --curve, or the usual curve of the algorithm when empty.
+func dnssecKeyAlgorithm() (algorithm, curve string, err error) {
+	algorithm = strings.ToUpper(dnssecAlgorithm)
+	curve = strings.ToUpper(dnssecCurve)
+	switch algorithm {
+	case "RSA":
+		return algorithm, "", nil
+	case "ECDSA":
+		if curve == "" {
+			curve = "P256"
+		}
+	case "EDDSA":
+		if curve == "" {
+			curve = "ED25519"
+		}
+	default:
+		return "", "", fmt.Errorf("unknown algorithm %q (use RSA, ECDSA or EDDSA)", dnssecAlgorithm)
+	}
+	return algorithm, curve, nil
+}
+
+// parseKeyTag parses a DNSSEC key tag argument.
+func parseKeyTag(s string) (uint16, error) {
+	tag, err := strconv.ParseUint(s, 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid key tag %q", s)
+	}
+	return uint16(tag), nil
+}
+
+// dsRecordLines formats ds as DS records of zone in zone file syntax, one
+// per digest. With digest set only that digest type is included. The
+// server's algorithm and digest names are given as numbers, looked up in the
+// tables the DS record flags use.
+func dsRecordLines(zone string, ds []technitium.DSRecord, digest string) []string {
+	number := func(table map[string]int, name string) string {
+		if n, ok := table[normalizeMnemonic(name)]; ok {
+			return strconv.Itoa(n)
+		}
+		return name
+	}
+	var lines []string
+	for _, r := range ds {
+		for _, d := range r.Digests {
+			if digest != "" && !strings.EqualFold(d.DigestType, digest) {
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("%s IN DS %d %s %s %s", zonefile.Fqdn(zone), r.KeyTag,
+				number(dnssecAlgorithms, r.Algorithm), number(dsDigestTypes, d.DigestType), d.Digest))
+		}
+	}
+	return lines
+}
+
+var dnssecCmd = &cobra.Command{
+	Use:     "dnssec",
+	Aliases: []string{"dn"},
+	Short:   "Sign zones and manage their DNSSEC keys",
+}
+
+var dnssecSignCmd = &cobra.Command{
+	Use:   "sign [zone]",
+	Short: "Sign a primary zone with DNSSEC",
+	Long: `Sign a primary zone with new key signing (KSK) and zone signing (ZSK) keys.
+
+The algorithm is RSA, ECDSA or EDDSA. RSA takes --hash and the key sizes;
+ECDSA and EDDSA take --curve (P256 or P384, ED25519 or ED448), which defaults
+to P256 and ED25519. Non-existence is proven with NSEC3 by default, with no
+extra iterations or salt as RFC 9276 recommends, or with NSEC.
+
+Once signed, publish the DS record printed by 'tdns dnssec ds' at the parent.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		zone := args[0]
+		algorithm, curve, err := dnssecKeyAlgorithm()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		nxProof := strings.ToUpper(dnssecNxProof)
+		if nxProof != "NSEC" && nxProof != "NSEC3" {
+			fmt.Fprintf(os.Stderr, "❌ unknown --nx-proof %q (use NSEC or NSEC3)\n", dnssecNxProof)
+			os.Exit(1)
+		}
+
+		opts := technitium.SignOptions{
+			Algorithm:       algorithm,
+			HashAlgorithm:   strings.ToUpper(dnssecHash),
+			KSKKeySize:      dnssecKSKSize,
+			ZSKKeySize:      dnssecZSKSize,
+			Curve:           curve,
+			NxProof:         nxProof,
+			Iterations:      dnssecIterations,
+			SaltLength:      dnssecSaltLength,
+			DNSKEYTTL:       dnssecKeyTTL,
+			ZSKRolloverDays: dnssecRolloverDays,
+		}
+		if err := api.New().SignZone(cmd.Context(), zone, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		render(zoneAction{Zone: zone, Result: "signed"}, func() {
+			fmt.Printf("✅ Zone %s signed with %s and %s.\n", bold(zone), algorithm, nxProof)
+			fmt.Printf("   Publish its DS record at the parent: tdns dnssec ds %s\n", zone)
+		})
+	},
+}
+
+var dnssecUnsignCmd = &cobra.Command{
+	Use:   "unsign [zone]",
+	Short: "Remove DNSSEC from a zone and delete its keys",
+	Long: `Remove DNSSEC from a zone and delete its keys.
+
+Remove the zone's DS records from the parent first, and wait for them to
+expire from caches: while the parent still has them, validating resolvers
+treat the unsigned zone as bogus.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		zone := args[0]
+		if !confirmed(fmt.Sprintf("Unsign zone '%s' and delete its DNSSEC keys?", zone)) {
+			return
+		}
+
+		if err := api.New().UnsignZone(cmd.Context(), zone); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		render(zoneAction{Zone: zone, Result: "unsigned"}, func() {
+			fmt.Printf("✅ Zone %s unsigned.\n", bold(zone))
+		})
+	},
+}
+
+var dnssecPropertiesCmd = &cobra.Command{
+	Use:     "properties [zone]",
+	Aliases: []string{"props", "pr"},
+	Short:   "Show a zone's DNSSEC status and keys",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		props, err := api.New().GetDNSSECProperties(cmd.Context(), args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		renderList(props, props.DNSSECPrivateKeys, func() {
+			fmt.Printf("%s %s\n", bold("Zone:"), blue(props.Name))
+			fmt.Printf("%s %s\n", bold("DNSSEC:"), props.DNSSECStatus)
+			if props.DNSSECStatus == "SignedWithNSEC3" {
+				fmt.Printf("%s %d iterations, %d byte salt\n", bold("NSEC3:"), props.NSEC3Iterations, props.NSEC3SaltLength)
+			}
+			fmt.Printf("%s %d\n", bold("DNSKEY TTL:"), props.DNSKEYTTL)
+			if len(props.DNSSECPrivateKeys) == 0 {
+				return
+			}
+
+			fmt.Println(bold("Keys:"))
+			for _, k := range props.DNSSECPrivateKeys {
+				kind := "ZSK"
+				if k.KeyType == technitium.KeySigningKey {
+					kind = "KSK"
+				}
+				state := k.State
+				if k.IsRetiring {
+					state += ", retiring"
+				}
+				fmt.Printf("- %s %s %s (%s)\n", cyan(k.KeyTag), kind, k.Algorithm, state)
+				fmt.Printf("  Since: %s\n", k.StateChangedOn)
+				if k.StateReadyBy != "" {
+					fmt.Printf("  Ready by: %s\n", k.StateReadyBy)
+				}
+				if k.KeyType == technitium.ZoneSigningKey {
+					rollover := "off"
+					if k.RolloverDays > 0 {
+						rollover = fmt.Sprintf("every %d days", k.RolloverDays)
+					}
+					fmt.Printf("  Rollover: %s\n", rollover)
+				}
+			}
+		})
+	},
+}
+
+var dnssecAddKeyCmd = &cobra.Command{
+	Use:     "add-key [zone]",
+	Aliases: []string{"ak"},
+	Short:   "Generate a new KSK or ZSK for a signed zone",
+	Long: `Generate a new key signing (--type ksk) or zone signing (--type zsk) key for
+a signed zone. The key is only generated; run 'tdns dnssec publish-all' to
+publish it.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		zone := args[0]
+		algorithm, curve, err := dnssecKeyAlgorithm()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		opts := technitium.AddKeyOptions{
+			Algorithm:     algorithm,
+			HashAlgorithm: strings.ToUpper(dnssecHash),
+			KeySize:       dnssecKeySize,
+			Curve:         curve,
+			RolloverDays:  dnssecRolloverDays,
+		}
+		switch strings.ToLower(dnssecKeyType) {
+		case "ksk":
+			opts.KeyType = technitium.KeySigningKey
+		case "zsk":
+			opts.KeyType = technitium.ZoneSigningKey
+		default:
+			fmt.Fprintf(os.Stderr, "❌ unknown --type %q (use ksk or zsk)\n", dnssecKeyType)
+			os.Exit(1)
+		}
+
+		if err := api.New().AddDNSSECKey(cmd.Context(), zone, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		render(map[string]string{"zone": zone, "keyType": opts.KeyType, "algorithm": algorithm}, func() {
+			fmt.Printf("✅ %s key generated for zone %s.\n", strings.ToUpper(dnssecKeyType), bold(zone))
+			fmt.Printf("   Publish it with: tdns dnssec publish-all %s\n", zone)
+		})
+	},
+}
+
+var dnssecPublishAllCmd = &cobra.Command{
+	Use:     "publish-all [zone]",
+	Aliases: []string{"pa"},
+	Short:   "Publish all generated keys of a zone",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		zone := args[0]
+		if err := api.New().PublishAllDNSSECKeys(cmd.Context(), zone); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		render(zoneAction{Zone: zone, Result: "published"}, func() {
+			fmt.Printf("✅ Generated keys of zone %s published.\n", bold(zone))
+		})
+	},
+}
+
+var dnssecRolloverCmd = &cobra.Command{
+	Use:     "rollover [zone] [keyTag]",
+	Aliases: []string{"ro"},
+	Short:   "Roll over a key",
+	Long: `Replace an active key by a new one of the same kind. The old key is retired
+once the new one is active. Rolling over a KSK needs the new DS record to be
+published at the parent before the old one is removed.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		zone := args[0]
+		tag, err := parseKeyTag(args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		if err := api.New().RolloverDNSKey(cmd.Context(), zone, tag); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		render(map[string]interface{}{"zone": zone, "keyTag": tag, "result": "rolled over"}, func() {
+			fmt.Printf("✅ Rollover of key %d of zone %s started.\n", tag, bold(zone))
+		})
+	},
+}
+
+var dnssecRetireCmd = &cobra.Command{
+	Use:     "retire [zone] [keyTag]",
+	Aliases: []string{"re"},
+	Short:   "Retire a key",
+	Long:    `Retire a key. The server refuses when it is the last active key of its kind.`,
+	Args:    cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		zone := args[0]
+		tag, err := parseKeyTag(args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		if err := api.New().RetireDNSKey(cmd.Context(), zone, tag); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		render(map[string]interface{}{"zone": zone, "keyTag": tag, "result": "retired"}, func() {
+			fmt.Printf("✅ Key %d of zone %s retired.\n", tag, bold(zone))
+		})
+	},
+}
+
+var dnssecDSCmd = &cobra.Command{
+	Use:   "ds [zone]",
+	Short: "Print the DS records to publish at the parent zone",
+	Long: `Print a DS record for each key signing key of a signed zone, in zone file
+syntax, for your registrar or the parent zone. Pick a digest type with
+--digest; SHA256 is what most registries expect.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		zone := args[0]
+		ds, err := api.New().ViewDS(cmd.Context(), zone)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		renderList(ds, ds, func() {
+			lines := dsRecordLines(zone, ds, dnssecDigest)
+			if len(lines) == 0 {
+				fmt.Println("No DS records found.")
+				return
+			}
+			for _, l := range lines {
+				fmt.Println(l)
+			}
+		})
+	},
+}
+
+// addKeyAlgorithmFlags adds the flags choosing a key algorithm to cmd.
+func addKeyAlgorithmFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&dnssecAlgorithm, "algorithm", "ECDSA", "Key algorithm: RSA, ECDSA or EDDSA")
+	cmd.Flags().StringVar(&dnssecHash, "hash", "SHA256", "RSA hash algorithm: MD5, SHA1, SHA256 or SHA512")
+	cmd.Flags().StringVar(&dnssecCurve, "curve", "", "ECDSA or EDDSA curve: P256, P384, ED25519 or ED448 (default P256 or ED25519)")
+}
+
+func init() {
+	addKeyAlgorithmFlags(dnssecSignCmd)
+	dnssecSignCmd.Flags().IntVar(&dnssecKSKSize, "ksk-size", 2048, "RSA KSK size in bits")
+	dnssecSignCmd.Flags().IntVar(&dnssecZSKSize, "zsk-size", 1024, "RSA ZSK size in bits")
+	dnssecSignCmd.Flags().StringVar(&dnssecNxProof, "nx-proof", "NSEC3", "Proof of non-existence: NSEC or NSEC3")
+	dnssecSignCmd.Flags().IntVar(&dnssecIterations, "iterations", 0, "NSEC3 iterations")
+	dnssecSignCmd.Flags().IntVar(&dnssecSaltLength, "salt-length", 0, "NSEC3 salt length in bytes")
+	dnssecSignCmd.Flags().IntVar(&dnssecKeyTTL, "dnskey-ttl", 0, "DNSKEY TTL in seconds (0 for the server default)")
+	dnssecSignCmd.Flags().IntVar(&dnssecRolloverDays, "zsk-rollover-days", 30, "Days between automatic ZSK rollovers (0 disables)")
+
+	dnssecUnsignCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Assume yes when asking for confirmation")
+
+	addKeyAlgorithmFlags(dnssecAddKeyCmd)
+	dnssecAddKeyCmd.Flags().StringVar(&dnssecKeyType, "type", "", "Key type: ksk or zsk")
+	dnssecAddKeyCmd.Flags().IntVar(&dnssecKeySize, "key-size", 2048, "RSA key size in bits")
+	dnssecAddKeyCmd.Flags().IntVar(&dnssecRolloverDays, "rollover-days", 30, "Days between automatic rollovers of a ZSK (0 disables)")
+	_ = dnssecAddKeyCmd.MarkFlagRequired("type")
+
+	dnssecDSCmd.Flags().StringVar(&dnssecDigest, "digest", "", "Only print this digest type, such as SHA256")
+
+	dnssecCmd.AddCommand(dnssecSignCmd)
+	dnssecCmd.AddCommand(dnssecUnsignCmd)
+	dnssecCmd.AddCommand(dnssecPropertiesCmd)
+	dnssecCmd.AddCommand(dnssecAddKeyCmd)
+	dnssecCmd.AddCommand(dnssecPublishAllCmd)
+	dnssecCmd.AddCommand(dnssecRolloverCmd)
+	dnssecCmd.AddCommand(dnssecRetireCmd)
+	dnssecCmd.AddCommand(dnssecDSCmd)
+	rootCmd.AddCommand(dnssecCmd)
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDnssecDS(t *testing.T) {
+	out, err := runWithStub(t, `{"status":"ok","response":{"name":"example.com","dsRecords":[{"keyTag":12345,
+		"dnsKeyState":"Active","algorithm":"ECDSAP256SHA256","publicKey":"abc",
+		"digests":[{"digestType":"SHA1","digest":"AAAA"},{"digestType":"SHA256","digest":"BBBB"}]}]}}`,
+		"dnssec", "ds", "example.com")
+	if err != nil {
+		t.Fatalf("dnssec ds: %v", err)
+	}
+	want := "example.com. IN DS 12345 13 1 AAAA\nexample.com. IN DS 12345 13 2 BBBB\n"
+	if out != want {
+		t.Errorf("got\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestDnssecPropertiesTable(t *testing.T) {
+	out, err := runWithStub(t, `{"status":"ok","response":{"name":"example.com","dnssecStatus":"SignedWithNSEC",
+		"dnssecPrivateKeys":[{"keyTag":1,"keyType":"KeySigningKey","algorithm":"ED25519","state":"Active"},
+		{"keyTag":2,"keyType":"ZoneSigningKey","algorithm":"ED25519","state":"Ready","rolloverDays":30}]}}`,
+		"dnssec", "properties", "example.com", "-o", "csv")
+	if err != nil {
+		t.Fatalf("dnssec properties: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "keyTag,keyType,") || !strings.HasPrefix(lines[2], "2,ZoneSigningKey,ED25519,Ready") {
+		t.Errorf("unexpected csv output:\n%s", out)
+	}
+}
@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"tdns/internal/api"
+	"tdns/pkg/technitium"
+)
+
+var domainListReplace bool
+
+// domainEntry is a domain of the allowed or blocked list in table and csv
+// output.
+type domainEntry struct {
+	Domain string `json:"domain"`
+}
+
+// domainListResult is the structured output of import.
+type domainListResult struct {
+	List    string   `json:"list"`
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// underDomain returns the domains of list that are domain or its
+// subdomains, sorted.
+func underDomain(list []string, domain string) []string {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	var out []string
+	for _, d := range list {
+		l := strings.ToLower(d)
+		if domain == "" || l == domain || strings.HasSuffix(l, "."+domain) {
+			out = append(out, d)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// syncDomains returns which of want are missing from have and which of have
+// are not in want, comparing without regard to case.
+func syncDomains(have, want []string) (add, remove []string) {
+	haveSet := map[string]bool{}
+	for _, d := range have {
+		haveSet[strings.ToLower(d)] = true
+	}
+	wantSet := map[string]bool{}
+	for _, d := range want {
+		l := strings.ToLower(d)
+		if !wantSet[l] && !haveSet[l] {
+			add = append(add, d)
+		}
+		wantSet[l] = true
+	}
+	for _, d := range have {
+		if !wantSet[strings.ToLower(d)] {
+			remove = append(remove, d)
+		}
+	}
+	sort.Strings(add)
+	sort.Strings(remove)
+	return add, remove
+}
+
+// domainListCmd builds the `allowed` or `blocked` command, which manage the
+// server list of that name.
+func domainListCmd(list, short string) *cobra.Command {
+	parent := &cobra.Command{
+		Use:   list,
+		Short: short,
+		Long: short + `. A listed domain covers its subdomains too.
+
+import and export use a plain text file with one domain per line, so the list
+can be kept in version control; blank lines and # comments are skipped.`,
+	}
+
+	listCmd := &cobra.Command{
+		Use:     "list [domain]",
+		Aliases: []string{"ls"},
+		Short:   fmt.Sprintf("List the %s domains, or those under a domain", list),
+		Args:    cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			domains, err := api.New().ExportDomains(cmd.Context(), list)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+				os.Exit(1)
+			}
+			under := ""
+			if len(args) == 1 {
+				under = args[0]
+			}
+			domains = underDomain(domains, under)
+
+			rows := make([]domainEntry, 0, len(domains))
+			for _, d := range domains {
+				rows = append(rows, domainEntry{d})
+			}
+			renderList(append([]string{}, domains...), rows, func() {
+				if len(domains) == 0 {
+					fmt.Printf("No %s domains found.\n", list)
+					return
+				}
+				fmt.Println(bold(fmt.Sprintf("%s domains:", strings.ToUpper(list[:1])+list[1:])))
+				for _, d := range domains {
+					fmt.Printf("- %s\n", cyan(d))
+				}
+			})
+		},
+	}
+
+	addCmd := &cobra.Command{
+		Use:     "add [domain]...",
+		Aliases: []string{"a"},
+		Short:   fmt.Sprintf("Add domains to the %s list", list),
+		Args:    cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			client := api.New()
+			var results []zoneAction
+			for _, d := range args {
+				d = strings.TrimSuffix(d, ".")
+				if err := client.AddDomain(cmd.Context(), list, d); err != nil {
+					fmt.Fprintf(os.Stderr, "❌ %s: %v\n", d, err)
+					os.Exit(1)
+				}
+				results = append(results, zoneAction{Zone: d, Result: "added"})
+			}
+			renderList(results, results, func() {
+				for _, r := range results {
+					fmt.Printf("✅ Added %s to the %s list.\n", r.Zone, list)
+				}
+			})
+		},
+	}
+
+	deleteCmd := &cobra.Command{
+		Use:     "delete [domain]...",
+		Aliases: []string{"del", "rm"},
+		Short:   fmt.Sprintf("Remove domains from the %s list", list),
+		Args:    cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if !confirmed(fmt.Sprintf("Are you sure you want to remove %s from the %s list?", strings.Join(args, ", "), list)) {
+				return
+			}
+			client := api.New()
+			var results []zoneAction
+			for _, d := range args {
+				d = strings.TrimSuffix(d, ".")
+				if err := client.DeleteDomain(cmd.Context(), list, d); err != nil {
+					fmt.Fprintf(os.Stderr, "❌ %s: %v\n", d, err)
+					os.Exit(1)
+				}
+				results = append(results, zoneAction{Zone: d, Result: "deleted"})
+			}
+			renderList(results, results, func() {
+				for _, r := range results {
+					fmt.Printf("✅ Removed %s from the %s list.\n", r.Zone, list)
+				}
+			})
+		},
+	}
+
+	flushCmd := &cobra.Command{
+		Use:   "flush",
+		Short: fmt.Sprintf("Remove every domain from the %s list", list),
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if !confirmed(fmt.Sprintf("Are you sure you want to remove every domain from the %s list?", list)) {
+				return
+			}
+			if err := api.New().FlushDomains(cmd.Context(), list); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+				os.Exit(1)
+			}
+			render(map[string]string{"list": list, "result": "flushed"}, func() {
+				fmt.Printf("✅ The %s list is empty.\n", list)
+			})
+		},
+	}
+
+	importCmd := &cobra.Command{
+		Use:     "import [file]",
+		Aliases: []string{"im"},
+		Short:   fmt.Sprintf("Add the domains of a file (or stdin) to the %s list", list),
+		Long: fmt.Sprintf(`Add the domains of a file, one per line, to the %s list. Without a file,
+or with -, the domains are read from stdin. Domains already listed are left
+alone; with --replace, listed domains the file doesn't have are removed, so
+the list ends up matching the file (give --yes to confirm that when reading
+stdin).`, list),
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var in io.Reader = os.Stdin
+			source := "stdin"
+			if len(args) == 1 && args[0] != "-" {
+				f, err := os.Open(args[0])
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+					os.Exit(1)
+				}
+				defer f.Close()
+				in, source = f, args[0]
+			}
+			want, err := technitium.ReadDomains(in)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %s: %v\n", source, err)
+				os.Exit(1)
+			}
+
+			client := api.New()
+			have, err := client.ExportDomains(cmd.Context(), list)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+				os.Exit(1)
+			}
+			add, remove := syncDomains(have, want)
+			if !domainListReplace {
+				remove = nil
+			}
+			if len(remove) > 0 && !confirmed(fmt.Sprintf("Are you sure you want to remove %d domain(s) that %s doesn't list from the %s list?", len(remove), source, list)) {
+				return
+			}
+
+			if len(add) > 0 {
+				if err := client.ImportDomains(cmd.Context(), list, add); err != nil {
+					fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+					os.Exit(1)
+				}
+			}
+			for _, d := range remove {
+				if err := client.DeleteDomain(cmd.Context(), list, d); err != nil {
+					fmt.Fprintf(os.Stderr, "❌ %s: %v\n", d, err)
+					os.Exit(1)
+				}
+			}
+
+			result := domainListResult{List: list, Added: append([]string{}, add...), Removed: append([]string{}, remove...)}
+			render(result, func() {
+				for _, d := range add {
+					fmt.Printf("%s %s\n", green("+"), d)
+				}
+				for _, d := range remove {
+					fmt.Printf("%s %s\n", yellow("-"), d)
+				}
+				fmt.Printf("✅ %s list: %d added, %d removed.\n", strings.ToUpper(list[:1])+list[1:], len(add), len(remove))
+			})
+		},
+	}
+
+	exportCmd := &cobra.Command{
+		Use:     "export [file]",
+		Aliases: []string{"ex"},
+		Short:   fmt.Sprintf("Write the %s list to a file (or stdout), one domain per line", list),
+		Args:    cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			domains, err := api.New().ExportDomains(cmd.Context(), list)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+				os.Exit(1)
+			}
+			sort.Strings(domains)
+			data := strings.Join(domains, "\n")
+			if data != "" {
+				data += "\n"
+			}
+
+			if len(args) == 0 || args[0] == "-" {
+				fmt.Print(data)
+				return
+			}
+			if err := os.WriteFile(args[0], []byte(data), 0644); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+				os.Exit(1)
+			}
+			render(map[string]interface{}{"file": args[0], "domains": len(domains)}, func() {
+				fmt.Printf("✅ Exported %d %s domain(s) to %s\n", len(domains), list, args[0])
+			})
+		},
+	}
+
+	deleteCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Assume yes when asking for confirmation")
+	flushCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Assume yes when asking for confirmation")
+	importCmd.Flags().BoolVar(&domainListReplace, "replace", false, "Remove listed domains the file doesn't have")
+	importCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Assume yes when asking for confirmation")
+	parent.AddCommand(listCmd, addCmd, deleteCmd, flushCmd, importCmd, exportCmd)
+	return parent
+}
+
+func init() {
+	rootCmd.AddCommand(domainListCmd(technitium.AllowedList, "Manage the allowed domains, which are never blocked"))
+	rootCmd.AddCommand(domainListCmd(technitium.BlockedList, "Manage the blocked domains, which are always blocked"))
+}
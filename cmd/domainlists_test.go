@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestUnderDomain(t *testing.T) {
+	list := []string{"b.example.com", "example.com", "notexample.com", "Ads.Example.com", "other.net"}
+	if got, want := underDomain(list, "example.com."), []string{"Ads.Example.com", "b.example.com", "example.com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("underDomain = %v, want %v", got, want)
+	}
+	if got := underDomain(list, ""); len(got) != len(list) {
+		t.Errorf("underDomain without a domain = %v", got)
+	}
+}
+
+func TestSyncDomains(t *testing.T) {
+	add, remove := syncDomains([]string{"a.example", "B.example", "c.example"}, []string{"b.example", "d.example", "d.example"})
+	if want := []string{"d.example"}; !reflect.DeepEqual(add, want) {
+		t.Errorf("add = %v, want %v", add, want)
+	}
+	if want := []string{"a.example", "c.example"}; !reflect.DeepEqual(remove, want) {
+		t.Errorf("remove = %v, want %v", remove, want)
+	}
+}
+
+func TestBlockedImportReplace(t *testing.T) {
+	var mu sync.Mutex
+	var imported string
+	var deleted []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/api/blocked/export":
+			w.Header().Set("Content-Type", "text/plain")
+			fmt.Fprint(w, "ads.example\r\nold.example\r\n")
+			return
+		case "/api/blocked/import":
+			r.ParseForm()
+			imported = r.PostForm.Get("blockedZones")
+		case "/api/blocked/delete":
+			deleted = append(deleted, r.URL.Query().Get("domain"))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"status":"ok","response":{}}`)
+	}))
+	defer srv.Close()
+
+	oldHost := viper.GetString("host")
+	viper.Set("host", srv.URL)
+	defer viper.Set("host", oldHost)
+	resetOutput(t)
+	t.Cleanup(func() { assumeYes, domainListReplace = false, false })
+
+	file := filepath.Join(t.TempDir(), "blocked.txt")
+	if err := os.WriteFile(file, []byte("# ads\nads.example\ntracker.example\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	oldStdout := os.Stdout
+	rp, wp, _ := os.Pipe()
+	os.Stdout = wp
+	defer func() { os.Stdout = oldStdout }()
+	go func() { _, _ = io.Copy(io.Discard, rp) }()
+
+	rootCmd.SetArgs([]string{"blocked", "import", file, "--replace", "--yes"})
+	defer rootCmd.SetArgs(nil)
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("blocked import: %v", err)
+	}
+	wp.Close()
+
+	if imported != "tracker.example" {
+		t.Errorf("imported %q, want only the missing domain", imported)
+	}
+	if strings.Join(deleted, ",") != "old.example" {
+		t.Errorf("deleted %v, want [old.example]", deleted)
+	}
+}
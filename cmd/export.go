@@ -15,16 +15,30 @@ import (
 
 var exportOutputDir string
 
+// exportedZone is the structured output of export for one zone: the file it
+// was written to, or its content when no --output-dir was given.
+type exportedZone struct {
+	Zone    string `json:"zone"`
+	File    string `json:"file,omitempty"`
+	Content string `json:"content,omitempty"`
+}
+
 var exportCmd = &cobra.Command{
 	Use:     "export [zones...]",
 	Aliases: []string{"ex"},
 	Short:   "Export one or more DNS zones",
 	Args:    cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
 		client := api.New()
-		for _, zone := range args {
-			resp, err := client.Get("/api/zones/export", url.Values{"zone": {zone}})
+		var exported []exportedZone
+		for i, zone := range args {
+			// Zones already written are complete; say which ones were not.
+			interrupted := fmt.Sprintf("exported %d of %d zones, stopped at '%s'.", i, len(args), zone)
+
+			resp, err := client.Get(ctx, "/api/zones/export", url.Values{"zone": {zone}})
 			if err != nil {
+				exitIfInterrupted(ctx, err, interrupted)
 				fmt.Printf("Export failed for %s: %v\n", zone, err)
 				continue
 			}
@@ -32,6 +46,7 @@ var exportCmd = &cobra.Command{
 			body, err := io.ReadAll(resp.Body)
 			resp.Body.Close()
 			if err != nil {
+				exitIfInterrupted(ctx, err, interrupted)
 				fmt.Printf("Failed to read response for %s: %v\n", zone, err)
 				continue
 			}
@@ -54,16 +69,26 @@ var exportCmd = &cobra.Command{
 					fmt.Printf("Failed to write to %s: %v\n", outPath, err)
 					continue
 				}
-				fmt.Printf("✅ Zone '%s' exported to %s\n", zone, outPath)
+				exported = append(exported, exportedZone{Zone: zone, File: outPath})
+				if !structuredOutput() {
+					fmt.Printf("✅ Zone '%s' exported to %s\n", zone, outPath)
+				}
 			} else {
-				fmt.Printf("-----\n"+bold("Zone:")+" %s\n-----\n", blue(zone))
-				fmt.Println(string(body))
+				exported = append(exported, exportedZone{Zone: zone, Content: string(body)})
+				if !structuredOutput() {
+					fmt.Printf("-----\n"+bold("Zone:")+" %s\n-----\n", blue(zone))
+					fmt.Println(string(body))
+				}
 			}
 		}
+		if exported == nil {
+			exported = []exportedZone{}
+		}
+		renderList(exported, exported, func() {})
 	},
 }
 
 func init() {
-	exportCmd.Flags().StringVarP(&exportOutputDir, "output-dir", "o", "", "Directory to save exported zone files")
+	exportCmd.Flags().StringVar(&exportOutputDir, "output-dir", "", "Directory to save exported zone files")
 	rootCmd.AddCommand(exportCmd)
 }
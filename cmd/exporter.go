@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"tdns/internal/api"
+	"tdns/internal/exporter"
+)
+
+var (
+	exporterListen   string
+	exporterPath     string
+	exporterInterval time.Duration
+)
+
+var exporterCmd = &cobra.Command{
+	Use:   "exporter",
+	Short: "Serve the server's statistics and zone health as Prometheus metrics",
+	Long: `Run a Prometheus exporter: poll the DNS server every --interval for its
+dashboard statistics of the last hour, its zones (with their expired, sync
+failed and notify failed flags) and its version, and serve them as metrics at
+--listen and --path until interrupted.
+
+Scrapes are answered from the latest poll. technitium_up is 0 when the last
+poll failed, and the metrics of the failed API calls are left out.
+
+  tdns exporter --listen :9153 --interval 30s`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if exporterInterval < time.Second {
+			fmt.Fprintln(os.Stderr, "❌ --interval must be at least 1s")
+			os.Exit(1)
+		}
+		ctx := cmd.Context()
+		exp := exporter.New(api.New())
+		if err := exp.Poll(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+		}
+
+		mux := http.NewServeMux()
+		mux.Handle(exporterPath, exp)
+		if exporterPath != "/" {
+			mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/" {
+					http.NotFound(w, r)
+					return
+				}
+				fmt.Fprintf(w, "<html><body><a href=%q>Metrics</a></body></html>\n", exporterPath)
+			})
+		}
+		ln, err := net.Listen("tcp", exporterListen)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+		go exp.Run(ctx, exporterInterval, func(err error) {
+			fmt.Fprintf(os.Stderr, "⚠️  %s: %v\n", time.Now().Format(time.TimeOnly), err)
+		})
+		go func() {
+			<-ctx.Done()
+			shutdown, cancel := context.WithTimeout(context.Background(), interruptGrace)
+			defer cancel()
+			srv.Shutdown(shutdown)
+		}()
+
+		fmt.Printf("📈 Serving metrics on http://%s%s (polling every %s)\n", ln.Addr(), exporterPath, exporterInterval)
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	exporterCmd.Flags().StringVar(&exporterListen, "listen", ":9153", "Address to serve metrics on")
+	exporterCmd.Flags().StringVar(&exporterPath, "path", "/metrics", "URL path of the metrics")
+	exporterCmd.Flags().DurationVar(&exporterInterval, "interval", 30*time.Second, "How often to poll the DNS server")
+	rootCmd.AddCommand(exporterCmd)
+}
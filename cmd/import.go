@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,17 +13,20 @@ import (
 	"github.com/spf13/cobra"
 
 	"tdns/internal/api"
+	"tdns/internal/zonefile"
 )
 
 var (
 	importFile string
-	importJSON bool
 
 	// import options mapped onto /api/zones/import query parameters
 	importOverwrite          bool
 	importOverwriteZone      bool
 	importOverwriteSoaSerial bool
 
+	// check the zone file locally before sending it
+	importValidate bool
+
 	// create the zone before importing into it
 	importCreate     bool
 	importCreateType string
@@ -45,8 +49,8 @@ const minOverwriteZoneVersion = "15.0"
 // the token lacks permission to read settings) only produces a warning, since
 // refusing the import outright would be worse than the silent no-op we are
 // trying to prevent.
-func checkOverwriteZoneSupported(client *api.Client) {
-	ok, version, err := client.ServerAtLeast(minOverwriteZoneVersion)
+func checkOverwriteZoneSupported(ctx context.Context, client *api.Client) {
+	ok, version, err := client.ServerAtLeast(ctx, minOverwriteZoneVersion)
 	switch {
 	case err != nil:
 		fmt.Fprintf(os.Stderr, "⚠️  Could not verify that the server supports --overwrite-zone (needs v%s+): %v\n", minOverwriteZoneVersion, err)
@@ -86,6 +90,28 @@ func readZoneFile(path string) ([]byte, error) {
 	return data, nil
 }
 
+// importData returns the zone file data to post. The server can't read the
+// files a $INCLUDE names on this machine, so a file using $INCLUDE is sent as
+// its records instead, with the includes expanded, one per line.
+func importData(path, zone string, data []byte) ([]byte, error) {
+	if !zonefile.HasInclude(data) {
+		return data, nil
+	}
+	recs, err := parseZoneData(path, zone, data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid zone file: %w", err)
+	}
+	if len(recs) == 0 {
+		return nil, fmt.Errorf("zone file %s has no records", path)
+	}
+	var buf bytes.Buffer
+	for _, r := range recs {
+		buf.WriteString(r.String())
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
 // buildImportQuery maps the import flags onto /api/zones/import query
 // parameters. `overwriteZone` is only sent when enabled so that the request
 // stays identical to previous releases against servers older than v15.0.
@@ -113,7 +139,11 @@ The zone file is named with --file, or read from standard input when --file is
   generate-zone example.com | tdns import example.com --file -
 
 The zone must already exist and be of type Primary or Forwarder; pass --create
-to create it first.
+to create it first. Pass --validate to run the checks of 'tdns zone validate'
+first and abort without contacting the server when they find errors.
+
+The server can't follow $INCLUDE to files on this machine, so a zone file
+using it is expanded here and its records are posted instead of the file.
 
 With --overwrite-zone (Technitium v15.0+) every existing record in the zone is
 deleted before the import, so only the imported records remain. Note that this
@@ -136,19 +166,37 @@ NSEC3PARAM), which the server always manages itself and ignores on import.`,
 			os.Exit(1)
 		}
 
+		if importValidate {
+			issues, err := zoneFileIssues(importFile, zone, data)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+				os.Exit(1)
+			}
+			if errs := printZoneFileIssues(issues); errs > 0 {
+				fmt.Fprintf(os.Stderr, "❌ Zone file has %d error(s), not importing.\n", errs)
+				os.Exit(1)
+			}
+		}
+
+		data, err = importData(importFile, zone, data)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
 		client := api.New()
 
 		if importOverwriteZone {
 			// Verify support before prompting, so the user isn't asked to
 			// confirm something the server would silently ignore.
-			checkOverwriteZoneSupported(client)
+			checkOverwriteZoneSupported(cmd.Context(), client)
 
 			if !assumeYes {
-				fmt.Printf("This deletes all existing records in zone '%s' before importing. Are you sure? (yes/no): ", zone)
+				fmt.Fprintf(messages(), "This deletes all existing records in zone '%s' before importing. Are you sure? (yes/no): ", zone)
 				var confirm string
 				fmt.Scanln(&confirm)
 				if confirm != "yes" {
-					fmt.Println("❌ Aborted.")
+					fmt.Fprintln(messages(), "❌ Aborted.")
 					return
 				}
 			}
@@ -157,22 +205,23 @@ NSEC3PARAM), which the server always manages itself and ignores on import.`,
 		if importCreate {
 			// The zone file supplies the SOA serial, so leave the server's
 			// date-based serial scheme off.
-			if _, err := createZone(client, zone, importCreateType, false, ""); err != nil {
+			if _, err := createZone(cmd.Context(), client, zone, importCreateType, false, ""); err != nil {
 				if !isZoneExistsError(err) {
 					fmt.Fprintf(os.Stderr, "❌ Failed to create zone %s: %v\n", zone, err)
 					os.Exit(1)
 				}
-				if !importJSON {
+				if !structuredOutput() {
 					fmt.Printf("ℹ️  Zone '%s' already exists, importing into it.\n", zone)
 				}
-			} else if !importJSON {
+			} else if !structuredOutput() {
 				fmt.Printf("✅ Zone '%s' created successfully.\n", zone)
 			}
 		}
 
 		q := buildImportQuery(zone, importOverwrite, importOverwriteZone, importOverwriteSoaSerial)
-		resp, err := client.Post("/api/zones/import", q, bytes.NewReader(data), "text/plain")
+		resp, err := client.Post(cmd.Context(), "/api/zones/import", q, bytes.NewReader(data), "text/plain")
 		if err != nil {
+			exitIfInterrupted(cmd.Context(), err, "the server may still have imported some or all records; check the zone.")
 			fmt.Printf("Request failed: %v\n", err)
 			os.Exit(1)
 		}
@@ -180,11 +229,6 @@ NSEC3PARAM), which the server always manages itself and ignores on import.`,
 
 		var result map[string]interface{}
 		if err := json.NewDecoder(resp.Body).Decode(&result); err == nil {
-			if importJSON {
-				raw, _ := json.MarshalIndent(result, "", "  ")
-				fmt.Println(string(raw))
-				return
-			}
 			if status, ok := result["status"].(string); !ok || status != "ok" {
 				if msg, ok := result["errorMessage"].(string); ok {
 					fmt.Fprintf(os.Stderr, "❌ %s\n", msg)
@@ -195,7 +239,10 @@ NSEC3PARAM), which the server always manages itself and ignores on import.`,
 			}
 		}
 
-		fmt.Printf("✅ Zone '%s' imported successfully.\n", zone)
+		response, _ := result["response"].(map[string]interface{})
+		render(response, func() {
+			fmt.Printf("✅ Zone '%s' imported successfully.\n", zone)
+		})
 	},
 }
 
@@ -204,12 +251,12 @@ func init() {
 	if err := importCmd.MarkFlagRequired("file"); err != nil {
 		panic(err)
 	}
-	importCmd.Flags().BoolVar(&importJSON, "json", false, "Print raw JSON response")
 	importCmd.Flags().BoolVar(&importOverwrite, "overwrite", true, "Overwrite existing record sets for the records being imported")
 	importCmd.Flags().BoolVar(&importOverwriteZone, "overwrite-zone", false, "Delete all existing records in the zone before importing (Technitium v15.0+)")
 	importCmd.Flags().BoolVar(&importOverwriteSoaSerial, "overwrite-soa-serial", true, "Take the SOA serial from the imported file. Warning: a serial lower than the current one makes secondary zones fail to sync")
 	importCmd.Flags().BoolVar(&importCreate, "create", false, "Create the zone first if it does not exist")
 	importCmd.Flags().StringVar(&importCreateType, "type", "Primary", "Zone type to use with --create (Primary or Forwarder)")
+	importCmd.Flags().BoolVar(&importValidate, "validate", false, "Check the zone file locally and abort on errors before importing")
 	importCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Assume yes when asking for confirmation")
 	rootCmd.AddCommand(importCmd)
 }
@@ -108,7 +108,7 @@ func execImportCmd(t *testing.T, handler func(r *http.Request) string, args ...s
 	// Execute calls; clear it so tests cannot mask a missing default.
 	importCmd.Flags().Lookup("file").Changed = false
 	importFile = ""
-	importJSON = false
+	resetOutput(t)
 	importOverwrite = true
 	importOverwriteZone = false
 	importOverwriteSoaSerial = true
@@ -373,3 +373,21 @@ func TestImportCmdCreateForwarderType(t *testing.T) {
 		t.Errorf("create type = %v, want Forwarder", got)
 	}
 }
+
+func TestImportCmdExpandsIncludes(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "hosts.inc"), []byte("www A 192.0.2.1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "example.com.zone")
+	if err := os.WriteFile(path, []byte("$TTL 60\n@ NS ns1\n$INCLUDE hosts.inc\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	reqs := runImportCmd(t, okResponse, "example.com", "--file", path)
+	imp := findRequest(t, reqs, "/api/zones/import")
+	want := "example.com. 60 IN NS ns1.example.com.\nwww.example.com. 60 IN A 192.0.2.1\n"
+	if imp.body != want {
+		t.Errorf("body = %q, want the expanded records %q", imp.body, want)
+	}
+}
@@ -1,19 +1,65 @@
 package cmd
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
+// initProfile adds a profile to config.json in the current directory,
+// prompting for its name and settings when interactive is set.
+func initProfile(interactive bool) error {
+	name := viper.GetString("profile")
+	settings := map[string]interface{}{"host": defaultHost, "token": ""}
+	if interactive {
+		in := bufio.NewReader(os.Stdin)
+		if name == "" {
+			name = "default"
+		}
+		var err error
+		if name, err = promptLine(in, "Profile name", name); err != nil {
+			return fmt.Errorf("failed to read profile name: %w", err)
+		}
+		if settings, err = promptProfileSettings(in, defaultHost); err != nil {
+			return fmt.Errorf("failed to read profile settings: %w", err)
+		}
+	}
+	name, err := checkProfileName(name)
+	if err != nil {
+		return err
+	}
+	if err := addProfile(defaultConfigFile, name, settings, false, false); err != nil {
+		return err
+	}
+	absPath, _ := filepath.Abs(defaultConfigFile)
+	fmt.Printf("✅ Profile '%s' saved to %s\n", name, absPath)
+	return nil
+}
+
 var initCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Create an initial config.json file in the current directory",
+	Long: `Create an initial config.json file in the current directory.
+
+With --interactive (-i) the profile name, API endpoint and token are prompted
+for and saved as a profile; with --profile an empty profile of that name is
+added instead. Either way an existing config.json is kept and the profile is
+added to it.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		configFile := "config.json"
+		configFile := defaultConfigFile
+
+		if interactive || viper.GetString("profile") != "" {
+			if err := initProfile(interactive); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
 
 		// Define the structure of the data
 		data := map[string]string{
@@ -49,5 +95,6 @@ var initCmd = &cobra.Command{
 }
 
 func init() {
+	initCmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Prompt for a profile's name, endpoint and token")
 	rootCmd.AddCommand(initCmd)
 }
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// readJSONInput reads the JSON object of a --data-file or --stdin flag, for
+// commands whose JSON keys become API query parameters. It returns an empty
+// map when neither is set. Numbers are kept as json.Number so they reach the
+// API exactly as written.
+func readJSONInput(dataFile string, stdin bool) (map[string]interface{}, error) {
+	base := map[string]interface{}{}
+	if dataFile != "" && stdin {
+		return nil, errors.New("--data-file and --stdin are mutually exclusive")
+	}
+
+	var body []byte
+	var err error
+	source := dataFile
+	switch {
+	case dataFile != "":
+		if body, err = os.ReadFile(dataFile); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", dataFile, err)
+		}
+		if len(bytes.TrimSpace(body)) == 0 {
+			return base, nil
+		}
+		source = "in " + dataFile
+	case stdin:
+		if body, err = io.ReadAll(os.Stdin); err != nil {
+			return nil, fmt.Errorf("failed to read stdin: %w", err)
+		}
+		if len(bytes.TrimSpace(body)) == 0 {
+			return nil, errors.New("no data received on stdin")
+		}
+		source = "on stdin"
+	default:
+		return base, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&base); err != nil {
+		return nil, fmt.Errorf("invalid JSON %s: %w", source, err)
+	}
+	return base, nil
+}
+
+// queryValue formats a JSON value read by readJSONInput as a query
+// parameter: lists are joined with commas.
+func queryValue(v interface{}) string {
+	switch vv := v.(type) {
+	case []interface{}:
+		return joinInterfaceCSV(vv)
+	case []string:
+		return strings.Join(vv, ",")
+	case bool:
+		return boolToStr(vv)
+	default:
+		return fmt.Sprintf("%v", vv)
+	}
+}
@@ -1,7 +1,7 @@
 package cmd
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"net/url"
 	"os"
@@ -17,7 +17,6 @@ import (
 )
 
 var (
-	listJSON       bool
 	listFilterName string
 	listFilterType string
 	listPage       int
@@ -96,8 +95,8 @@ const minServerSideFilterVersion = "15.3"
 // matches on the other pages are invisible, and the totals in the response
 // describe the unfiltered set. A server that will not report its version stays
 // silent rather than warning about not being able to warn.
-func warnIfPaginatedFilterUnsupported(client *api.Client) {
-	ok, version, err := client.ServerAtLeast(minServerSideFilterVersion)
+func warnIfPaginatedFilterUnsupported(ctx context.Context, client *api.Client) {
+	ok, version, err := client.ServerAtLeast(ctx, minServerSideFilterVersion)
 	if err != nil || ok {
 		return
 	}
@@ -243,28 +242,32 @@ returned. The command warns when it detects that combination.`,
 		filtering := listFilterName != "" || filterType != ""
 		paginating := listPage > 0 || listPerPage > 0
 		if filtering && paginating {
-			warnIfPaginatedFilterUnsupported(client)
+			warnIfPaginatedFilterUnsupported(cmd.Context(), client)
 		}
 
 		q := buildZonesListQuery(listFilterName, filterType, listPage, listPerPage)
-		result, response, err := client.GetJSON("/api/zones/list", q)
+		_, response, err := client.GetJSON(cmd.Context(), "/api/zones/list", q)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
 			os.Exit(1)
 		}
 
-		if listJSON {
-			raw, _ := json.MarshalIndent(result, "", "  ")
-			fmt.Println(string(raw))
-			return
+		// Structured output gets the same zones the text lists.
+		rawZones, _ := response["zones"].([]interface{})
+		zones := filterZones(rawZones, listFilterName, filterType)
+		filtered := map[string]interface{}{}
+		for k, v := range response {
+			filtered[k] = v
 		}
+		filtered["zones"] = zones
 
-		fmt.Print(formatZonesList(response, listFilterName, filterType, paginating))
+		renderList(filtered, zones, func() {
+			fmt.Print(formatZonesList(response, listFilterName, filterType, paginating))
+		})
 	},
 }
 
 func init() {
-	listCmd.Flags().BoolVar(&listJSON, "json", false, "Output raw JSON response")
 	listCmd.Flags().StringVarP(&listFilterName, "name", "n", "", "Filter zones by name; supports * and ? wildcards")
 	listCmd.Flags().StringVarP(&listFilterType, "type", "y", "", fmt.Sprintf("Filter zones by type (%s)", strings.Join(zoneTypes, ", ")))
 	listCmd.Flags().IntVar(&listPage, "page", 0, "Page number of paginated results (default: all zones)")
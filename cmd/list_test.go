@@ -206,7 +206,7 @@ func runListCmdRequests(t *testing.T, serverVersion string, args ...string) []li
 	defer viper.Set("host", oldHost)
 
 	// Reset flag-bound package vars from any previous invocation.
-	listJSON = false
+	resetOutput(t)
 	listFilterName = ""
 	listFilterType = ""
 	listPage = 0
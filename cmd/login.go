@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"tdns/internal/api"
+	"tdns/pkg/technitium"
+)
+
+var (
+	loginUser     string
+	loginPassword string
+	loginTOTP     string
+)
+
+// saveSessionToken stores token in the config file at path: in the active
+// profile when there is one, else at the top level. An empty token removes
+// the stored one. host, when set, is stored alongside so the token stays
+// paired with the server that issued it.
+func saveSessionToken(path, profile, host, token string) error {
+	cfg, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+	target := cfg
+	if profile != "" {
+		profiles := configProfiles(cfg)
+		p, ok := profiles[profile].(map[string]interface{})
+		if !ok {
+			p = map[string]interface{}{}
+			profiles[profile] = p
+		}
+		target = p
+	}
+	if token == "" {
+		delete(target, "token")
+	} else {
+		target["token"] = token
+	}
+	if host != "" {
+		target["host"] = host
+	}
+	return writeConfigFile(path, cfg)
+}
+
+// tokenLocation describes where saveSessionToken puts the token.
+func tokenLocation(path, profile string) string {
+	if profile != "" {
+		return fmt.Sprintf("profile '%s' in %s", profile, path)
+	}
+	return path
+}
+
+var loginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Log in with a username and password and save the session token",
+	Long: `Log in with a username and password and save the session token in the
+config file: in the active profile when there is one, else at the top level.
+The file is written with owner-only (0600) permissions.
+
+The username and password are prompted for when not given. For accounts with
+two-factor authentication, pass --totp or enter the code when asked.
+
+Session tokens expire after the user's session timeout of inactivity; for
+unattended use create a non-expiring token with 'tdns admin create-token'.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		in := bufio.NewReader(os.Stdin)
+		var err error
+		if loginUser == "" {
+			if loginUser, err = promptLine(in, "Username", "admin"); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Failed to read username: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if loginPassword == "" {
+			if loginPassword, err = promptSecret(in, "Password"); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Failed to read password: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		client := api.New()
+		session, err := client.Login(cmd.Context(), loginUser, loginPassword, loginTOTP)
+		var apiErr *api.APIError
+		if errors.As(err, &apiErr) && apiErr.Status == technitium.ErrTOTPRequired && loginTOTP == "" &&
+			term.IsTerminal(int(os.Stdin.Fd())) {
+			if loginTOTP, err = promptLine(in, "TOTP code", ""); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Failed to read TOTP code: %v\n", err)
+				os.Exit(1)
+			}
+			session, err = client.Login(cmd.Context(), loginUser, loginPassword, loginTOTP)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Login failed: %v\n", err)
+			os.Exit(1)
+		}
+
+		host := ""
+		if cmd.Flags().Changed("endpoint") {
+			host = client.Host
+		}
+		path, profile := configPath(), activeProfile()
+		if err := saveSessionToken(path, profile, host, session.Token); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Logged in, but failed to save the token: %v\n", err)
+			os.Exit(1)
+		}
+		render(map[string]string{"username": session.Username, "config": path, "profile": profile}, func() {
+			fmt.Printf("✅ Logged in as %s; session token saved to %s\n", session.Username, tokenLocation(path, profile))
+		})
+		if os.Getenv("TDNS_TOKEN") != "" || cmd.Flags().Changed("token") {
+			fmt.Fprintln(os.Stderr, yellow("⚠️  A token given by --token or TDNS_TOKEN still takes precedence over the saved one."))
+		}
+	},
+}
+
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "End the current session and remove its saved token",
+	Long: `End the session of the current token on the server and remove the token
+from the config file (the active profile, or the top level).
+
+Note that logging out with an API token made by 'tdns admin create-token'
+deletes that token on the server.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := api.New().Logout(cmd.Context())
+		var apiErr *api.APIError
+		switch {
+		case errors.As(err, &apiErr):
+			// Most likely the session already expired; still forget it.
+			fmt.Fprintf(os.Stderr, "⚠️  Server did not end the session: %v\n", err)
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		path, profile := configPath(), activeProfile()
+		if err := saveSessionToken(path, profile, "", ""); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		render(map[string]string{"config": path, "profile": profile}, func() {
+			fmt.Printf("✅ Logged out; token removed from %s\n", tokenLocation(path, profile))
+		})
+	},
+}
+
+var whoamiCmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Show the user and server behind the current token",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		client := api.New()
+		session, err := client.CurrentSession(cmd.Context())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		session.Token = ""
+
+		render(session, func() {
+			fmt.Printf("%s (%s)\n", bold(session.DisplayName), blue(session.Username))
+			fmt.Printf("  Server: %s %s\n", client.Host, grey("("+session.Info.DNSServerDomain+", v"+session.Info.Version+")"))
+			if p := activeProfile(); p != "" {
+				fmt.Printf("  Profile: %s\n", p)
+			}
+			twoFA := "disabled"
+			if session.TOTPEnabled {
+				twoFA = green("enabled")
+			}
+			fmt.Printf("  2FA: %s\n", twoFA)
+
+			if len(session.Info.Permissions) > 0 {
+				sections := make([]string, 0, len(session.Info.Permissions))
+				for s := range session.Info.Permissions {
+					sections = append(sections, s)
+				}
+				sort.Strings(sections)
+				fmt.Println(bold("Permissions:"))
+				for _, s := range sections {
+					p := session.Info.Permissions[s]
+					fmt.Printf("  %-15s %s\n", s, permissionString(p))
+				}
+			}
+		})
+	},
+}
+
+// permissionString renders p as view/modify/delete flags, e.g. "VM-".
+func permissionString(p technitium.Permission) string {
+	flag := func(ok bool, c string) string {
+		if ok {
+			return green(c)
+		}
+		return grey("-")
+	}
+	return flag(p.CanView, "V") + flag(p.CanModify, "M") + flag(p.CanDelete, "D")
+}
+
+func init() {
+	loginCmd.Flags().StringVarP(&loginUser, "user", "u", "", "Username (prompted for when empty)")
+	loginCmd.Flags().StringVar(&loginPassword, "password", "", "Password (insecure; prompted for when empty)")
+	loginCmd.Flags().StringVar(&loginTOTP, "totp", "", "6-digit TOTP code if 2FA is enabled")
+	rootCmd.AddCommand(loginCmd)
+	rootCmd.AddCommand(logoutCmd)
+	rootCmd.AddCommand(whoamiCmd)
+}
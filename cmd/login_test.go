@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSaveSessionTokenInProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(profilesConfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := saveSessionToken(path, "lab", "", "session-token"); err != nil {
+		t.Fatalf("saveSessionToken: %v", err)
+	}
+	cfg, err := readConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lab := configProfiles(cfg)["lab"].(map[string]interface{})
+	if lab["token"] != "session-token" || lab["host"] != "http://lab:5380" {
+		t.Errorf("lab profile = %v", lab)
+	}
+	if cfg["token"] != "top-token" {
+		t.Errorf("top-level token = %v, want it untouched", cfg["token"])
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	if err := saveSessionToken(path, "lab", "", ""); err != nil {
+		t.Fatalf("saveSessionToken: %v", err)
+	}
+	cfg, _ = readConfigFile(path)
+	if _, ok := configProfiles(cfg)["lab"].(map[string]interface{})["token"]; ok {
+		t.Error("token still in the lab profile after clearing it")
+	}
+}
+
+func TestSaveSessionTokenTopLevel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := saveSessionToken(path, "", "https://dns:53443", "session-token"); err != nil {
+		t.Fatalf("saveSessionToken: %v", err)
+	}
+	cfg, err := readConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg["token"] != "session-token" || cfg["host"] != "https://dns:53443" {
+		t.Errorf("config = %v", cfg)
+	}
+}
+
+func TestWhoami(t *testing.T) {
+	out, err := runWithStub(t, `{"displayName":"Administrator","username":"admin","totpEnabled":false,"token":"secret",
+		"info":{"version":"13.2","dnsServerDomain":"dns1.example.com",
+		"permissions":{"Zones":{"canView":true,"canModify":true,"canDelete":false}}},"status":"ok"}`, "whoami")
+	if err != nil {
+		t.Fatalf("whoami: %v", err)
+	}
+	for _, want := range []string{"Administrator", "admin", "dns1.example.com", "13.2", "Zones"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output is missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "secret") {
+		t.Errorf("output shows the token:\n%s", out)
+	}
+}
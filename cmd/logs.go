@@ -12,7 +12,7 @@ import (
 	"tdns/internal/api"
 )
 
-var outputPath string
+var logFilePath string
 
 var logsCmd = &cobra.Command{
 	Use:     "logs",
@@ -25,26 +25,26 @@ var logsListCmd = &cobra.Command{
 	Aliases: []string{"ls"},
 	Short:   "List available log files",
 	Run: func(cmd *cobra.Command, args []string) {
-		_, response, err := api.New().GetJSON("/api/logs/list", nil)
+		logs, err := api.New().ListLogs(cmd.Context())
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
 			os.Exit(1)
 		}
 
-		logs := response["logFiles"].([]interface{})
-		if len(logs) == 0 {
-			fmt.Println("No log files found.")
-			return
-		}
+		renderList(logs, logs, func() {
+			if len(logs) == 0 {
+				fmt.Println("No log files found.")
+				return
+			}
 
-		bold := color.New(color.Bold).SprintFunc()
-		cyan := color.New(color.FgCyan).SprintFunc()
+			bold := color.New(color.Bold).SprintFunc()
+			cyan := color.New(color.FgCyan).SprintFunc()
 
-		fmt.Println(bold("Available Log Files:"))
-		for _, entry := range logs {
-			log := entry.(map[string]interface{})
-			fmt.Printf("- %s (%s)\n", cyan(log["fileName"]), log["size"])
-		}
+			fmt.Println(bold("Available Log Files:"))
+			for _, log := range logs {
+				fmt.Printf("- %s (%s)\n", cyan(log.FileName), log.Size)
+			}
+		})
 	},
 }
 
@@ -56,35 +56,37 @@ var logsDownloadCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		fileName := args[0]
 
-		resp, err := api.New().Get("/api/logs/download", url.Values{"fileName": {fileName}})
+		resp, err := api.New().Get(cmd.Context(), "/api/logs/download", url.Values{"fileName": {fileName}})
 		if err != nil {
-			fmt.Printf("Request failed: %v\n", err)
+			fmt.Fprintf(os.Stderr, "❌ Request failed: %v\n", err)
 			os.Exit(1)
 		}
 		defer resp.Body.Close()
 
 		if resp.StatusCode != 200 {
-			fmt.Printf("❌ Failed to download file: HTTP %d\n", resp.StatusCode)
+			fmt.Fprintf(os.Stderr, "❌ Failed to download file: HTTP %d\n", resp.StatusCode)
 			os.Exit(1)
 		}
 
 		outputFile := fileName + ".log"
-		if outputPath != "" {
-			outputFile = outputPath
+		if logFilePath != "" {
+			outputFile = logFilePath
 		}
 
 		data, err := io.ReadAll(resp.Body)
 		if err != nil {
-			fmt.Printf("Failed to read file: %v\n", err)
+			fmt.Fprintf(os.Stderr, "❌ Failed to read file: %v\n", err)
 			os.Exit(1)
 		}
 
 		if err := os.WriteFile(outputFile, data, 0644); err != nil {
-			fmt.Printf("Failed to save file: %v\n", err)
+			fmt.Fprintf(os.Stderr, "❌ Failed to save file: %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Printf("✅ Log file saved as %s\n", outputFile)
+		render(map[string]string{"file": outputFile}, func() {
+			fmt.Printf("✅ Log file saved as %s\n", outputFile)
+		})
 	},
 }
 
@@ -96,12 +98,14 @@ var logsDeleteCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		fileName := args[0]
 
-		if _, _, err := api.New().GetJSON("/api/logs/delete", url.Values{"log": {fileName}}); err != nil {
+		if err := api.New().DeleteLog(cmd.Context(), fileName); err != nil {
 			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Printf("✅ Log '%s' deleted successfully.\n", fileName)
+		render(map[string]string{"fileName": fileName}, func() {
+			fmt.Printf("✅ Log '%s' deleted successfully.\n", fileName)
+		})
 	},
 }
 
@@ -110,25 +114,27 @@ var logsDeleteAllCmd = &cobra.Command{
 	Aliases: []string{"da"},
 	Short:   "Delete all log files",
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Print("Are you sure you want to delete ALL logs? (yes/no): ")
+		fmt.Fprint(messages(), "Are you sure you want to delete ALL logs? (yes/no): ")
 		var confirm string
 		fmt.Scanln(&confirm)
 		if confirm != "yes" {
-			fmt.Println("❌ Aborted.")
+			fmt.Fprintln(messages(), "❌ Aborted.")
 			return
 		}
 
-		if _, _, err := api.New().GetJSON("/api/logs/deleteAll", nil); err != nil {
+		if err := api.New().DeleteAllLogs(cmd.Context()); err != nil {
 			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Println("✅ All logs deleted successfully.")
+		render(map[string]string{}, func() {
+			fmt.Println("✅ All logs deleted successfully.")
+		})
 	},
 }
 
 func init() {
-	logsDownloadCmd.Flags().StringVarP(&outputPath, "output", "o", "", "Optional path to save the downloaded log file")
+	logsDownloadCmd.Flags().StringVarP(&logFilePath, "file", "f", "", "Path to save the downloaded log file (default <fileName>.log)")
 	logsCmd.AddCommand(logsListCmd)
 	logsCmd.AddCommand(logsDownloadCmd)
 	logsCmd.AddCommand(logsDeleteCmd)
@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"tdns/internal/api"
+	"tdns/pkg/technitium"
+)
+
+var (
+	logQueryApp          string
+	logQueryClass        string
+	logQuerySince        time.Duration
+	logQueryStart        string
+	logQueryEnd          string
+	logQueryClient       string
+	logQueryProtocol     string
+	logQueryResponseType string
+	logQueryRCode        string
+	logQueryQName        string
+	logQueryQType        string
+	logQueryPage         int
+	logQueryPerPage      int
+	logQueryOldest       bool
+)
+
+// queryLogger picks the query logger class to search: the one matching
+// --app and --class, which may be left out when only one is installed.
+func queryLogger(apps []technitium.DNSApp, app, class string) (string, string, error) {
+	var names, classes []string
+	for _, a := range apps {
+		if app != "" && !strings.EqualFold(a.Name, app) {
+			continue
+		}
+		for _, c := range a.Classes {
+			if c.IsQueryLogger && (class == "" || strings.EqualFold(c.ClassPath, class)) {
+				names, classes = append(names, a.Name), append(classes, c.ClassPath)
+			}
+		}
+	}
+	switch len(names) {
+	case 0:
+		if app != "" || class != "" {
+			return "", "", fmt.Errorf("no installed query logger matches --app %q --class %q", app, class)
+		}
+		return "", "", fmt.Errorf("no query logger app is installed; install one such as Query Logs (Sqlite) with 'tdns apps install'")
+	case 1:
+		return names[0], classes[0], nil
+	}
+	found := make([]string, len(names))
+	for i := range names {
+		found[i] = names[i] + " (" + classes[i] + ")"
+	}
+	return "", "", fmt.Errorf("several query loggers are installed: %s; choose one with --app and --class", strings.Join(found, ", "))
+}
+
+// logQueryOptions builds the search from the flags, looking up the query
+// logger app unless both --app and --class are given.
+func logQueryOptions(ctx context.Context, client *api.Client) (technitium.QueryLogOptions, error) {
+	opts := technitium.QueryLogOptions{
+		App:            logQueryApp,
+		ClassPath:      logQueryClass,
+		PageNumber:     logQueryPage,
+		EntriesPerPage: logQueryPerPage,
+		Ascending:      logQueryOldest,
+		ClientIP:       logQueryClient,
+		Protocol:       logQueryProtocol,
+		ResponseType:   logQueryResponseType,
+		RCode:          logQueryRCode,
+		QName:          strings.TrimSuffix(logQueryQName, "."),
+		QType:          strings.ToUpper(logQueryQType),
+	}
+
+	var err error
+	switch {
+	case logQuerySince > 0 && logQueryStart != "":
+		return opts, fmt.Errorf("--since and --start are mutually exclusive")
+	case logQuerySince > 0:
+		opts.Start = time.Now().Add(-logQuerySince)
+	case logQueryStart != "":
+		if opts.Start, err = parseStatsTime("start", logQueryStart); err != nil {
+			return opts, err
+		}
+	}
+	if logQueryEnd != "" {
+		if opts.End, err = parseStatsTime("end", logQueryEnd); err != nil {
+			return opts, err
+		}
+		if !opts.Start.IsZero() && !opts.End.After(opts.Start) {
+			return opts, fmt.Errorf("--end must be after the start")
+		}
+	}
+
+	if opts.App == "" || opts.ClassPath == "" {
+		apps, err := client.ListApps(ctx)
+		if err != nil {
+			return opts, err
+		}
+		if opts.App, opts.ClassPath, err = queryLogger(apps, opts.App, opts.ClassPath); err != nil {
+			return opts, err
+		}
+	}
+	return opts, nil
+}
+
+// logTime formats a log timestamp in local time, or returns it as sent when
+// it can't be parsed.
+func logTime(s string) string {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return s
+	}
+	return t.Local().Format(time.DateTime)
+}
+
+// printQueryLog prints the entries one per line, in aligned columns.
+func printQueryLog(entries []technitium.QueryLogEntry) {
+	header := []string{"TIME", "CLIENT", "PROTOCOL", "RESPONSE", "RCODE", "QNAME", "QTYPE", "ANSWER"}
+	rows := make([][]string, len(entries))
+	for i, e := range entries {
+		rows[i] = []string{logTime(e.Timestamp), e.ClientIPAddress, e.Protocol, e.ResponseType, e.RCode, e.QName, e.QType, e.Answer}
+	}
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for i, v := range row {
+			widths[i] = max(widths[i], len(v))
+		}
+	}
+	pad := func(row []string) []string {
+		out := make([]string, len(row))
+		for i, v := range row {
+			if i < len(row)-1 {
+				v = fmt.Sprintf("%-*s", widths[i], v)
+			}
+			out[i] = v
+		}
+		return out
+	}
+
+	fmt.Println(bold(strings.Join(pad(header), "  ")))
+	for i, row := range rows {
+		cells := pad(row)
+		cells[0] = grey(cells[0])
+		if strings.HasSuffix(entries[i].ResponseType, "Blocked") {
+			cells[3] = yellow(cells[3])
+		}
+		if entries[i].RCode == "NoError" {
+			cells[4] = green(cells[4])
+		} else {
+			cells[4] = yellow(cells[4])
+		}
+		cells[5] = cyan(cells[5])
+		fmt.Println(strings.Join(cells, "  "))
+	}
+}
+
+var logsQueryCmd = &cobra.Command{
+	Use:     "query",
+	Aliases: []string{"q"},
+	Short:   "Search the query log of a query logger app",
+	Long: `Search the DNS queries logged by a query logger app, such as Query Logs
+(Sqlite), newest first. --app and --class choose the app; they can be left
+out when only one query logger is installed. Results come a page at a time;
+--page moves through them.
+
+  tdns logs query --qname example.com --since 1h
+  tdns logs query --client 10.0.0.9 --rcode NxDomain --page 2`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		client := api.New()
+		opts, err := logQueryOptions(cmd.Context(), client)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		page, err := client.QueryLogs(cmd.Context(), opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		renderList(page, page.Entries, func() {
+			if len(page.Entries) == 0 {
+				fmt.Println("No matching queries logged.")
+				return
+			}
+			printQueryLog(page.Entries)
+			footer := fmt.Sprintf("\nPage %d of %d, %d entries.", page.PageNumber, page.TotalPages, page.TotalEntries)
+			if page.PageNumber < page.TotalPages {
+				footer += fmt.Sprintf(" Next: --page %d", page.PageNumber+1)
+			}
+			fmt.Println(grey(footer))
+		})
+	},
+}
+
+func init() {
+	f := logsQueryCmd.Flags()
+	f.StringVar(&logQueryApp, "app", "", "Query logger app to search")
+	f.StringVar(&logQueryClass, "class", "", "Class path of the app's query logger")
+	f.DurationVar(&logQuerySince, "since", 0, "Only queries of this long ago or later, for example 1h")
+	f.StringVar(&logQueryStart, "start", "", "Only queries from this time (YYYY-MM-DD, YYYY-MM-DD HH:MM or RFC 3339)")
+	f.StringVar(&logQueryEnd, "end", "", "Only queries until this time")
+	f.StringVar(&logQueryClient, "client", "", "Only queries from this client IP address")
+	f.StringVar(&logQueryProtocol, "protocol", "", "Only queries over this protocol (Udp, Tcp, Tls, Https, Quic)")
+	f.StringVar(&logQueryResponseType, "response-type", "", "Only answers of this type (Authoritative, Recursive, Cached, Blocked, ...)")
+	f.StringVar(&logQueryRCode, "rcode", "", "Only answers with this response code (NoError, NxDomain, ServerFailure, ...)")
+	f.StringVar(&logQueryQName, "qname", "", "Only queries for this name")
+	f.StringVar(&logQueryQType, "qtype", "", "Only queries of this record type")
+	f.IntVar(&logQueryPage, "page", 1, "Page of results to show")
+	f.IntVar(&logQueryPerPage, "per-page", 25, "Entries per page")
+	f.BoolVar(&logQueryOldest, "oldest", false, "Show the oldest entries first")
+	logsCmd.AddCommand(logsQueryCmd)
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"tdns/pkg/technitium"
+)
+
+func TestQueryLogger(t *testing.T) {
+	apps := []technitium.DNSApp{
+		{Name: "Split Horizon", Classes: []technitium.AppClass{{ClassPath: "SplitHorizon.SimpleAddress", IsAppRecordRequestHandler: true}}},
+		{Name: "Query Logs (Sqlite)", Classes: []technitium.AppClass{{ClassPath: "QueryLogsSqlite.App", IsQueryLogger: true}}},
+	}
+	if app, class, err := queryLogger(apps, "", ""); err != nil || app != "Query Logs (Sqlite)" || class != "QueryLogsSqlite.App" {
+		t.Errorf("only logger = %q %q, %v", app, class, err)
+	}
+	if _, _, err := queryLogger(apps, "Split Horizon", ""); err == nil {
+		t.Error("an app without a query logger was picked")
+	}
+
+	apps = append(apps, technitium.DNSApp{Name: "Log Exporter", Classes: []technitium.AppClass{{ClassPath: "LogExporter.App", IsQueryLogger: true}}})
+	if _, _, err := queryLogger(apps, "", ""); err == nil || !strings.Contains(err.Error(), "several") {
+		t.Errorf("two loggers: err = %v", err)
+	}
+	if app, _, err := queryLogger(apps, "log exporter", ""); err != nil || app != "Log Exporter" {
+		t.Errorf("by --app = %q, %v", app, err)
+	}
+}
+
+func TestLogsQuery(t *testing.T) {
+	var got url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/apps/list":
+			fmt.Fprint(w, `{"status":"ok","response":{"apps":[{"name":"Query Logs (Sqlite)","dnsApps":[{"classPath":"QueryLogsSqlite.App","isQueryLogger":true}]}]}}`)
+		case "/api/logs/query":
+			got = r.URL.Query()
+			fmt.Fprint(w, `{"status":"ok","response":{"pageNumber":2,"totalPages":2,"totalEntries":3,"entries":[
+				{"rowNumber":3,"timestamp":"2026-10-17T09:30:00Z","clientIpAddress":"10.0.0.9","protocol":"Udp","responseType":"Blocked",
+				"rcode":"NxDomain","qname":"ads.example","qtype":"A","qclass":"IN","answer":""}]}}`)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	oldHost := viper.GetString("host")
+	viper.Set("host", srv.URL)
+	defer viper.Set("host", oldHost)
+	resetOutput(t)
+	t.Cleanup(func() { logQueryQName, logQueryQType, logQueryPage, logQueryPerPage = "", "", 1, 25 })
+
+	oldStdout := os.Stdout
+	rp, wp, _ := os.Pipe()
+	os.Stdout = wp
+	defer func() { os.Stdout = oldStdout }()
+	var out bytes.Buffer
+	done := make(chan struct{})
+	go func() { _, _ = io.Copy(&out, rp); close(done) }()
+
+	rootCmd.SetArgs([]string{"logs", "query", "--qname", "ads.example.", "--qtype", "a", "--page", "2", "--per-page", "2", "-o", "json"})
+	defer rootCmd.SetArgs(nil)
+	err := rootCmd.Execute()
+	wp.Close()
+	<-done
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Get("name") != "Query Logs (Sqlite)" || got.Get("classPath") != "QueryLogsSqlite.App" || got.Get("qname") != "ads.example" ||
+		got.Get("qtype") != "A" || got.Get("pageNumber") != "2" || got.Get("entriesPerPage") != "2" {
+		t.Errorf("query = %v", got)
+	}
+	var page technitium.QueryLogPage
+	if err := json.Unmarshal(out.Bytes(), &page); err != nil {
+		t.Fatalf("%v: %s", err, out.String())
+	}
+	if page.TotalEntries != 3 || len(page.Entries) != 1 || page.Entries[0].ClientIPAddress != "10.0.0.9" {
+		t.Errorf("page = %+v", page)
+	}
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"tdns/internal/api"
+)
+
+var (
+	logTailLines    int
+	logTailFollow   bool
+	logTailInterval time.Duration
+)
+
+// lastLines returns the last n lines of data. A final line without a
+// newline counts as a line.
+func lastLines(data []byte, n int) []byte {
+	if n <= 0 {
+		return nil
+	}
+	end := len(data)
+	if end > 0 && data[end-1] == '\n' {
+		end--
+	}
+	start := end
+	for ; n > 0 && start > 0; n-- {
+		start = bytes.LastIndexByte(data[:start], '\n')
+		if start < 0 {
+			return data
+		}
+	}
+	if n > 0 {
+		return data
+	}
+	return data[start+1:]
+}
+
+// logTail follows a log file as it grows. With rollover it moves on to the
+// newer file the server starts at midnight.
+type logTail struct {
+	client   *api.Client
+	out      io.Writer
+	file     string
+	offset   int64
+	rollover bool
+	midLine  bool // the output so far ends without a newline
+}
+
+func (t *logTail) write(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	t.out.Write(data)
+	t.midLine = data[len(data)-1] != '\n'
+}
+
+// poll prints what was added to the log since the last poll.
+func (t *logTail) poll(ctx context.Context) error {
+	data, size, err := t.client.ReadLog(ctx, t.file, t.offset)
+	if err != nil {
+		return err
+	}
+	if size < t.offset {
+		fmt.Fprintf(os.Stderr, "⚠️  %s shrank; printing it again from the start.\n", t.file)
+	}
+	t.offset = size
+	t.write(data)
+	if len(data) > 0 || !t.rollover {
+		return nil
+	}
+
+	logs, err := t.client.ListLogs(ctx)
+	if err != nil || len(logs) == 0 || logs[0].FileName == t.file {
+		return err
+	}
+	// Finish the old file before moving on, in case lines were added to it
+	// since the read above.
+	if data, _, err = t.client.ReadLog(ctx, t.file, t.offset); err != nil {
+		return err
+	}
+	t.write(data)
+	if t.midLine {
+		t.write([]byte("\n"))
+	}
+	fmt.Fprintln(t.out, grey(fmt.Sprintf("==> %s <==", logs[0].FileName)))
+	t.file, t.offset = logs[0].FileName, 0
+	return t.poll(ctx)
+}
+
+var logsTailCmd = &cobra.Command{
+	Use:   "tail [fileName]",
+	Short: "Print the end of a log file, and follow it with -f",
+	Long: `Print the last lines of a log file, the newest one unless fileName is
+given. With -f the file is checked for new lines every --interval until
+interrupted. When following the newest file, tail moves on to the next file
+when the server starts one at midnight.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if logTailFollow && logTailInterval <= 0 {
+			fmt.Fprintln(os.Stderr, "❌ --interval must be positive")
+			os.Exit(1)
+		}
+		ctx := cmd.Context()
+		client := api.New()
+		t := &logTail{client: client, out: os.Stdout}
+		if len(args) == 1 {
+			t.file = args[0]
+		}
+		if t.file == "" || logTailFollow {
+			logs, err := client.ListLogs(ctx)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+				os.Exit(1)
+			}
+			if len(logs) == 0 {
+				fmt.Fprintln(os.Stderr, "❌ no log files found")
+				os.Exit(1)
+			}
+			if t.file == "" {
+				t.file = logs[0].FileName
+			}
+			t.rollover = t.file == logs[0].FileName
+		}
+
+		data, size, err := client.ReadLog(ctx, t.file, 0)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		t.offset = size
+		t.write(lastLines(data, logTailLines))
+		if !logTailFollow {
+			return
+		}
+
+		tick := time.NewTicker(logTailInterval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick.C:
+			}
+			if err := t.poll(ctx); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+			}
+		}
+	},
+}
+
+func init() {
+	logsTailCmd.Flags().IntVarP(&logTailLines, "lines", "n", 10, "Number of lines to print")
+	logsTailCmd.Flags().BoolVarP(&logTailFollow, "follow", "f", false, "Keep printing lines as they are logged")
+	logsTailCmd.Flags().DurationVar(&logTailInterval, "interval", 2*time.Second, "How often to check for new lines with -f")
+	logsCmd.AddCommand(logsTailCmd)
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"tdns/pkg/technitium"
+)
+
+func TestLastLines(t *testing.T) {
+	for _, tt := range []struct {
+		data string
+		n    int
+		want string
+	}{
+		{"a\nb\nc\n", 2, "b\nc\n"},
+		{"a\nb\nc", 2, "b\nc"},
+		{"a\nb\n", 5, "a\nb\n"},
+		{"a\nb\n", 0, ""},
+		{"", 3, ""},
+	} {
+		if got := string(lastLines([]byte(tt.data), tt.n)); got != tt.want {
+			t.Errorf("lastLines(%q, %d) = %q, want %q", tt.data, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestLogTailRollover(t *testing.T) {
+	var mu sync.Mutex
+	files := map[string]string{"2026-10-16": "first\n"}
+	newest := "2026-10-16"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/api/logs/list":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"status":"ok","response":{"logFiles":[{"fileName":%q,"size":"1 KB"}]}}`, newest)
+		case "/api/logs/download":
+			w.Header().Set("Content-Type", "text/plain")
+			http.ServeContent(w, r, "", time.Time{}, strings.NewReader(files[r.URL.Query().Get("fileName")]))
+		}
+	}))
+	defer srv.Close()
+
+	var out bytes.Buffer
+	tail := &logTail{client: &technitium.Client{Host: srv.URL, HTTP: srv.Client()}, out: &out, file: "2026-10-16", offset: 6, rollover: true}
+	ctx := context.Background()
+	update := func(f func()) {
+		mu.Lock()
+		defer mu.Unlock()
+		f()
+	}
+
+	update(func() { files["2026-10-16"] += "second\nthi" })
+	if err := tail.poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	update(func() {
+		files["2026-10-16"] += "rd"
+		files["2026-10-17"] = "fourth\n"
+		newest = "2026-10-17"
+	})
+	if err := tail.poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// The rest of the old file comes out before moving on.
+	if err := tail.poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	update(func() { files["2026-10-17"] += "fifth\n" })
+	if err := tail.poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := io.ReadAll(&out)
+	if want := "second\nthird\n==> 2026-10-17 <==\nfourth\nfifth\n"; string(got) != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+	if tail.file != "2026-10-17" {
+		t.Errorf("file = %s", tail.file)
+	}
+}
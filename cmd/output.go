@@ -0,0 +1,374 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
+)
+
+// Output formats of the --output flag.
+const (
+	outputText     = "text"
+	outputJSON     = "json"
+	outputYAML     = "yaml"
+	outputTable    = "table"
+	outputCSV      = "csv"
+	outputTemplate = "template"
+)
+
+// outputFormats lists the formats for help and error messages.
+const outputFormats = "text, json, yaml, table, csv or template=<Go template>"
+
+// outputSpec is a parsed --output value.
+type outputSpec struct {
+	format string
+	tmpl   *template.Template
+}
+
+// output is the format every command renders its result in, set from the
+// --output flag (or the `output` config key) before the command runs.
+var output = outputSpec{format: outputText}
+
+// parseOutput parses an --output value such as "json" or
+// "template={{.name}}".
+func parseOutput(s string) (outputSpec, error) {
+	if text, ok := strings.CutPrefix(s, outputTemplate+"="); ok {
+		tmpl, err := template.New("output").Option("missingkey=zero").Parse(text)
+		if err != nil {
+			return outputSpec{}, fmt.Errorf("invalid output template: %w", err)
+		}
+		return outputSpec{format: outputTemplate, tmpl: tmpl}, nil
+	}
+	switch f := strings.ToLower(s); f {
+	case "", outputText:
+		return outputSpec{format: outputText}, nil
+	case outputJSON, outputYAML, outputTable, outputCSV:
+		return outputSpec{format: f}, nil
+	}
+	return outputSpec{}, fmt.Errorf("unknown output format %q (use %s)", s, outputFormats)
+}
+
+// setOutput parses the output format of cmd. The deprecated --json flag
+// still selects JSON.
+func setOutput(cmd *cobra.Command) error {
+	spec, err := parseOutput(viper.GetString("output"))
+	if err != nil {
+		return err
+	}
+	if f := cmd.Flags().Lookup("json"); f != nil && f.Changed && f.Value.String() == "true" {
+		spec = outputSpec{format: outputJSON}
+	}
+	output = spec
+	return nil
+}
+
+// structuredOutput reports whether a machine-readable format was chosen, for
+// commands that must keep anything else off stdout.
+func structuredOutput() bool {
+	return output.format != outputText
+}
+
+// messages is where prompts and other messages that aren't the result go:
+// stdout for text output, else stderr so stdout stays parseable.
+func messages() io.Writer {
+	if structuredOutput() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// render prints v, the result of a command, in the chosen format. text
+// prints the human-readable form used for the text format. In the table and
+// csv formats v is shown as key/value pairs.
+func render(v interface{}, text func()) {
+	renderList(v, nil, text)
+}
+
+// renderList is render for results that hold a list: the table and csv
+// formats show rows, one line per element, while the other formats show all
+// of v.
+func renderList(v, rows interface{}, text func()) {
+	if output.format == outputText {
+		text()
+		return
+	}
+	if err := writeOutput(os.Stdout, output, v, rows); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// writeOutput writes v (or rows, for table and csv) to w in spec's format.
+func writeOutput(w io.Writer, spec outputSpec, v, rows interface{}) error {
+	switch spec.format {
+	case outputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case outputYAML:
+		generic, err := toGeneric(v)
+		if err != nil {
+			return err
+		}
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(generic); err != nil {
+			return err
+		}
+		return enc.Close()
+	case outputTemplate:
+		generic, err := toGeneric(v)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := spec.tmpl.Execute(&buf, generic); err != nil {
+			return fmt.Errorf("output template: %w", err)
+		}
+		if buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+		_, err = w.Write(buf.Bytes())
+		return err
+	case outputTable, outputCSV:
+		header, cells, err := tabulate(v, rows)
+		if err != nil {
+			return err
+		}
+		if spec.format == outputCSV {
+			cw := csv.NewWriter(w)
+			cw.Write(header)
+			cw.WriteAll(cells)
+			return cw.Error()
+		}
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		upper := make([]string, len(header))
+		for i, h := range header {
+			upper[i] = strings.ToUpper(h)
+		}
+		fmt.Fprintln(tw, strings.Join(upper, "\t"))
+		for _, row := range cells {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	}
+	return fmt.Errorf("unknown output format %q", spec.format)
+}
+
+// toGeneric converts v to plain maps, slices and scalars keyed by the JSON
+// field names, which is what templates and YAML see.
+func toGeneric(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&out); err != nil {
+		return nil, err
+	}
+	return plainNumbers(out), nil
+}
+
+// plainNumbers replaces the json.Numbers in v with int64 or float64, so YAML
+// writes them as numbers and templates print integers without an exponent.
+func plainNumbers(v interface{}) interface{} {
+	switch x := v.(type) {
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			return i
+		}
+		f, _ := x.Float64()
+		return f
+	case map[string]interface{}:
+		for k, e := range x {
+			x[k] = plainNumbers(e)
+		}
+	case []interface{}:
+		for i, e := range x {
+			x[i] = plainNumbers(e)
+		}
+	}
+	return v
+}
+
+// tabulate turns rows, or v as key/value pairs when rows is nil, into a
+// header and cells. Columns follow the order fields first appear in.
+func tabulate(v, rows interface{}) ([]string, [][]string, error) {
+	if rows == nil {
+		obj, err := toOrdered(v)
+		if err != nil {
+			return nil, nil, err
+		}
+		o, ok := obj.(*orderedObject)
+		if !ok {
+			return []string{"value"}, [][]string{{cell(obj)}}, nil
+		}
+		cells := make([][]string, 0, len(o.keys))
+		for _, k := range o.keys {
+			cells = append(cells, []string{k, cell(o.values[k])})
+		}
+		return []string{"key", "value"}, cells, nil
+	}
+
+	list, err := toOrdered(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	items, _ := list.([]interface{})
+	var header []string
+	seen := map[string]bool{}
+	for _, item := range items {
+		if o, ok := item.(*orderedObject); ok {
+			for _, k := range o.keys {
+				if !seen[k] {
+					seen[k] = true
+					header = append(header, k)
+				}
+			}
+		}
+	}
+	if header == nil {
+		header = []string{"value"}
+	}
+	cells := make([][]string, 0, len(items))
+	for _, item := range items {
+		o, ok := item.(*orderedObject)
+		if !ok {
+			cells = append(cells, []string{cell(item)})
+			continue
+		}
+		row := make([]string, len(header))
+		for i, k := range header {
+			row[i] = cell(o.values[k])
+		}
+		cells = append(cells, row)
+	}
+	return header, cells, nil
+}
+
+// cell formats one table value: scalars as-is, lists of scalars joined with
+// commas and anything else as compact JSON.
+func cell(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case json.Number:
+		return x.String()
+	case bool:
+		return fmt.Sprint(x)
+	case []interface{}:
+		parts := make([]string, 0, len(x))
+		for _, e := range x {
+			switch e.(type) {
+			case *orderedObject, []interface{}:
+				return compactJSON(x)
+			}
+			parts = append(parts, cell(e))
+		}
+		return strings.Join(parts, ",")
+	}
+	return compactJSON(v)
+}
+
+func compactJSON(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// orderedObject is a JSON object that remembers the order of its keys.
+type orderedObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (o *orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(k)
+		val, err := json.Marshal(o.values[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// toOrdered converts v like toGeneric, but with objects as *orderedObject.
+func toOrdered(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return decodeOrdered(dec)
+}
+
+func decodeOrdered(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		o := &orderedObject{values: map[string]interface{}{}}
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, _ := keyTok.(string)
+			val, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			if _, dup := o.values[key]; !dup {
+				o.keys = append(o.keys, key)
+			}
+			o.values[key] = val
+		}
+		_, err := dec.Token() // '}'
+		return o, err
+	case json.Delim('['):
+		list := []interface{}{}
+		for dec.More() {
+			val, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, val)
+		}
+		_, err := dec.Token() // ']'
+		return list, err
+	}
+	return tok, nil
+}
+
+// zoneAction is the structured output of the commands that act on zones,
+// such as enable and delete: one entry per zone done.
+type zoneAction struct {
+	Zone   string `json:"zone"`
+	Result string `json:"result"`
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseOutput(t *testing.T) {
+	for _, s := range []string{"", "text", "json", "JSON", "yaml", "table", "csv", "template={{.name}}"} {
+		if _, err := parseOutput(s); err != nil {
+			t.Errorf("parseOutput(%q): %v", s, err)
+		}
+	}
+	for _, s := range []string{"xml", "template={{.name", "template"} {
+		if _, err := parseOutput(s); err == nil {
+			t.Errorf("parseOutput(%q) succeeded", s)
+		}
+	}
+}
+
+func TestWriteOutput(t *testing.T) {
+	type row struct {
+		Name    string   `json:"name"`
+		TTL     int      `json:"ttl"`
+		Tags    []string `json:"tags,omitempty"`
+		Enabled bool     `json:"enabled"`
+	}
+	rows := []row{{Name: "b.example", TTL: 300, Tags: []string{"x", "y"}}, {Name: "a.example", TTL: 60, Enabled: true}}
+	v := map[string]interface{}{"zones": rows}
+
+	tests := []struct {
+		format string
+		v      interface{}
+		rows   interface{}
+		want   string
+	}{
+		{"json", rows[1], nil, "{\n  \"name\": \"a.example\",\n  \"ttl\": 60,\n  \"enabled\": true\n}\n"},
+		{"yaml", rows[1], nil, "enabled: true\nname: a.example\nttl: 60\n"},
+		{"csv", v, rows, "name,ttl,tags,enabled\nb.example,300,\"x,y\",false\na.example,60,,true\n"},
+		{"table", v, rows, "NAME       TTL  TAGS  ENABLED\nb.example  300  x,y   false\na.example  60         true\n"},
+		{"table", rows[1], nil, "KEY      VALUE\nname     a.example\nttl      60\nenabled  true\n"},
+		{"template={{range .zones}}{{.name}} {{end}}", v, rows, "b.example a.example \n"},
+	}
+	for _, tt := range tests {
+		spec, err := parseOutput(tt.format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := writeOutput(&buf, spec, tt.v, tt.rows); err != nil {
+			t.Errorf("%s: %v", tt.format, err)
+			continue
+		}
+		if buf.String() != tt.want {
+			t.Errorf("%s: got\n%q\nwant\n%q", tt.format, buf.String(), tt.want)
+		}
+	}
+}
+
+func TestOutputFlag(t *testing.T) {
+	body := `{"status":"ok","response":{"sessions":[
+		{"username":"admin","isCurrentSession":true,"partialToken":"abc","type":"Standard"}]}}`
+
+	out, err := runWithStub(t, body, "admin", "list-sessions", "-o", "csv")
+	if err != nil {
+		t.Fatalf("admin list-sessions -o csv: %v", err)
+	}
+	if !strings.HasPrefix(out, "username,isCurrentSession,partialToken,type,") ||
+		!strings.Contains(out, "\nadmin,true,abc,Standard,") {
+		t.Errorf("unexpected csv output:\n%s", out)
+	}
+
+	out, err = runWithStub(t, body, "admin", "list-sessions", "--json")
+	if err != nil {
+		t.Fatalf("admin list-sessions --json: %v", err)
+	}
+	if !strings.Contains(out, `"partialToken": "abc"`) {
+		t.Errorf("--json did not select JSON:\n%s", out)
+	}
+}
+
+// TestDeprecatedJSONFlagPrintsResult pins what --json prints since it became
+// --output json: the result alone, not the {"status", "response"} envelope.
+func TestDeprecatedJSONFlagPrintsResult(t *testing.T) {
+	tests := []struct {
+		args []string
+		body string
+		key  string
+	}{
+		{[]string{"list", "--json"}, `{"status":"ok","response":{"zones":[{"name":"example.com","type":"Primary"}]}}`, "zones"},
+		{[]string{"records", "get", "example.com", "--json"}, `{"status":"ok","response":{"zone":{"name":"example.com"},"records":[]}}`, "records"},
+		{[]string{"settings", "get", "--json"}, `{"status":"ok","response":{"version":"13.6","dnsServerDomain":"dns1"}}`, "dnsServerDomain"},
+	}
+	for _, tt := range tests {
+		out, err := runWithStub(t, tt.body, tt.args...)
+		if err != nil {
+			t.Fatalf("%v: %v", tt.args, err)
+		}
+		var got map[string]interface{}
+		if err := json.Unmarshal([]byte(out), &got); err != nil {
+			t.Fatalf("%v: %v\n%s", tt.args, err, out)
+		}
+		if _, ok := got["status"]; ok {
+			t.Errorf("%v printed the envelope:\n%s", tt.args, out)
+		}
+		if _, ok := got[tt.key]; !ok {
+			t.Errorf("%v: no %q at the top level:\n%s", tt.args, tt.key, out)
+		}
+	}
+}
@@ -0,0 +1,586 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"tdns/internal/api"
+	"tdns/internal/zonefile"
+)
+
+var planFile string
+
+// serverManagedTypes are maintained by the server itself: the SOA exists on
+// every zone and DNSSEC records are generated when signing. plan ignores them
+// on both sides, as the server does on import.
+var serverManagedTypes = map[string]bool{
+	"SOA":        true,
+	"DNSKEY":     true,
+	"RRSIG":      true,
+	"NSEC":       true,
+	"NSEC3":      true,
+	"NSEC3PARAM": true,
+}
+
+// planRecord is a record from either the desired zone file or the server,
+// reduced to what the records API needs to add, update or delete it.
+type planRecord struct {
+	domain string // lower case, without the trailing dot
+	rtype  string
+	ttl    int
+	// values holds the identity parameters as the API expects them.
+	values map[string]string
+	// data is the record data in canonical form, so that the same record
+	// read from a zone file and from the API compare equal.
+	data string
+	// display is the record data for printing.
+	display string
+}
+
+func (r planRecord) key() string {
+	return r.domain + "\x00" + r.rtype + "\x00" + r.data
+}
+
+type planOp int
+
+const (
+	planDelete planOp = iota
+	planUpdateTTL
+	planAdd
+)
+
+// planChange is one step of a plan. For planUpdateTTL, rec is the live record
+// and newTTL its desired TTL.
+type planChange struct {
+	op     planOp
+	rec    planRecord
+	newTTL int
+}
+
+// newPlanRecord builds a planRecord from identity parameter values.
+func newPlanRecord(spec recordTypeSpec, domain string, ttl int, values map[string]string) planRecord {
+	rec := planRecord{
+		domain: strings.ToLower(strings.TrimSuffix(domain, ".")),
+		rtype:  spec.name,
+		ttl:    ttl,
+		values: values,
+	}
+	var data, display []string
+	for _, p := range spec.params {
+		if p.setting {
+			continue
+		}
+		v, ok := values[p.name]
+		if !ok {
+			continue
+		}
+		data = append(data, canonicalParam(spec, p, v))
+		if p.kind == kindText {
+			v = strconv.Quote(v)
+		}
+		display = append(display, v)
+	}
+	rec.data = strings.Join(data, " ")
+	rec.display = strings.Join(display, " ")
+	return rec
+}
+
+// canonicalParam normalizes v so that equal record data compares equal
+// regardless of case, trailing dots, mnemonic vs numeric values or
+// formatting.
+func canonicalParam(spec recordTypeSpec, p recordParam, v string) string {
+	switch p.kind {
+	case kindDomain:
+		return strings.ToLower(strings.TrimSuffix(v, "."))
+	case kindIPv4, kindIPv6, kindIP:
+		if ip := net.ParseIP(v); ip != nil {
+			return ip.String()
+		}
+	case kindUint8, kindUint16, kindUint32:
+		if n, err := strconv.ParseUint(v, 10, 32); err == nil {
+			return strconv.FormatUint(n, 10)
+		}
+	case kindHex:
+		return strings.ToUpper(strings.Join(strings.Fields(v), ""))
+	case kindEnum:
+		if _, err := strconv.ParseUint(v, 10, 8); err == nil {
+			return v
+		}
+		if n, ok := p.enum[normalizeMnemonic(v)]; ok {
+			return strconv.Itoa(n)
+		}
+	case kindSvcParams:
+		parts := strings.Split(v, "|")
+		pairs := make([]string, 0, len(parts)/2)
+		for i := 0; i+1 < len(parts); i += 2 {
+			pairs = append(pairs, strings.ToLower(parts[i])+"="+parts[i+1])
+		}
+		sort.Strings(pairs)
+		return strings.Join(pairs, " ")
+	case kindText:
+		if spec.name == "TXT" {
+			// Split character-strings are joined with new lines by the API
+			// but are one string on the wire.
+			return strings.ReplaceAll(v, "\n", "")
+		}
+	}
+	return v
+}
+
+// desiredRecords converts parsed zone file records into plan records. Every
+// record must be inside zone and of a type the records API can manage.
+func desiredRecords(zone string, recs []zonefile.Record) ([]planRecord, error) {
+	origin := zonefile.Fqdn(zone)
+	out := make([]planRecord, 0, len(recs))
+	for _, r := range recs {
+		if serverManagedTypes[r.Type] {
+			continue
+		}
+		if !zonefile.InZone(r.Name, origin) {
+			return nil, fmt.Errorf("line %d: %s is outside zone %s", r.Line, r.Name, zone)
+		}
+		spec, ok := lookupRecordType(r.Type)
+		if !ok || spec.updateOnly {
+			return nil, fmt.Errorf("line %d: %s records cannot be managed through the records API", r.Line, r.Type)
+		}
+		values, err := zoneRecordValues(spec, r.Data)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", r.Line, err)
+		}
+		out = append(out, newPlanRecord(spec, r.Name, int(r.TTL), values))
+	}
+	return out, nil
+}
+
+// zoneRecordValues maps zone file data fields onto the identity parameters of
+// spec, in presentation order. The last parameter takes any remaining fields.
+func zoneRecordValues(spec recordTypeSpec, data []string) (map[string]string, error) {
+	var params []recordParam
+	for _, p := range spec.params {
+		if !p.setting {
+			params = append(params, p)
+		}
+	}
+
+	values := map[string]string{}
+	switch spec.name {
+	case "TXT":
+		// Multiple character-strings are kept apart with splitText, which
+		// the API represents as new-line separated text.
+		values["text"] = strings.Join(data, "\n")
+		if len(data) > 1 {
+			values["splitText"] = "true"
+		}
+		return values, nil
+	case "SVCB", "HTTPS":
+		if len(data) < 2 {
+			return nil, fmt.Errorf("%s record needs a priority and a target", spec.name)
+		}
+		values["svcPriority"] = data[0]
+		values["svcTargetName"] = apiDomain(data[1])
+		if svc := svcParamsFromZone(data[2:]); svc != "" {
+			values["svcParams"] = svc
+		}
+		return values, nil
+	}
+
+	for i, p := range params {
+		if i >= len(data) {
+			if p.required {
+				return nil, fmt.Errorf("%s record is missing %s", spec.name, p.name)
+			}
+			break
+		}
+		v := data[i]
+		if i == len(params)-1 && len(data) > len(params) {
+			sep := " "
+			if p.kind == kindHex {
+				sep = ""
+			}
+			v = strings.Join(data[i:], sep)
+		}
+		if p.kind == kindDomain {
+			v = apiDomain(v)
+		}
+		values[p.name] = v
+	}
+	for name, v := range values {
+		for _, p := range params {
+			if p.name == name {
+				if err := validateParam(p, v); err != nil {
+					return nil, fmt.Errorf("%s: %w", name, err)
+				}
+			}
+		}
+	}
+	return values, nil
+}
+
+// apiDomain drops the trailing dot of an absolute zone file name, as the API
+// reports names without it. The root stays ".".
+func apiDomain(name string) string {
+	if name == "." {
+		return name
+	}
+	return strings.TrimSuffix(name, ".")
+}
+
+// svcParamsFromZone converts SVCB key=value fields into the API's
+// pipe-separated form. A quoted value arrives as a separate field after
+// "key=".
+func svcParamsFromZone(fields []string) string {
+	var parts []string
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		if strings.HasSuffix(f, "=") && i+1 < len(fields) {
+			f += fields[i+1]
+			i++
+		}
+		k, v, _ := strings.Cut(f, "=")
+		parts = append(parts, k, v)
+	}
+	return strings.Join(parts, "|")
+}
+
+// liveRecords converts the records of a records/get response into plan
+// records. Server-managed and unsupported types are skipped; the latter are
+// returned so the caller can mention them.
+func liveRecords(records []interface{}) ([]planRecord, []string) {
+	var (
+		out     []planRecord
+		skipped []string
+	)
+	for _, r := range records {
+		rec, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		rtype, _ := rec["type"].(string)
+		name, _ := rec["name"].(string)
+		if serverManagedTypes[rtype] {
+			continue
+		}
+		spec, ok := lookupRecordType(rtype)
+		if !ok {
+			skipped = append(skipped, fmt.Sprintf("%s %s", name, rtype))
+			continue
+		}
+		rdata, _ := rec["rData"].(map[string]interface{})
+		values := map[string]string{}
+		for _, p := range spec.params {
+			if p.setting {
+				continue
+			}
+			if v, ok := rdata[p.rdataKey()]; ok && v != nil {
+				values[p.name] = rdataString(v)
+			}
+		}
+		ttl, _ := rec["ttl"].(float64)
+		out = append(out, newPlanRecord(spec, name, int(ttl), values))
+	}
+	return out, skipped
+}
+
+// rdataString renders an rData value the way the API takes it as a
+// parameter.
+func rdataString(v interface{}) string {
+	switch vv := v.(type) {
+	case string:
+		return vv
+	case float64:
+		return strconv.FormatFloat(vv, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(vv)
+	case map[string]interface{}:
+		// SVCB params: {"alpn":"h2,h3","port":"443"} -> alpn|h2,h3|port|443
+		keys := make([]string, 0, len(vv))
+		for k := range vv {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts := make([]string, 0, 2*len(keys))
+		for _, k := range keys {
+			parts = append(parts, k, rdataString(vv[k]))
+		}
+		return strings.Join(parts, "|")
+	case []interface{}:
+		return joinInterfaceCSV(vv)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// diffRecords computes the changes that turn live into desired, sorted by
+// name and type.
+func diffRecords(desired, live []planRecord) []planChange {
+	liveByKey := map[string]planRecord{}
+	for _, r := range live {
+		liveByKey[r.key()] = r
+	}
+	wanted := map[string]bool{}
+
+	var changes []planChange
+	for _, d := range desired {
+		k := d.key()
+		if wanted[k] {
+			continue
+		}
+		wanted[k] = true
+		l, ok := liveByKey[k]
+		switch {
+		case !ok:
+			changes = append(changes, planChange{op: planAdd, rec: d})
+		case l.ttl != d.ttl:
+			changes = append(changes, planChange{op: planUpdateTTL, rec: l, newTTL: d.ttl})
+		}
+	}
+	for _, l := range live {
+		if !wanted[l.key()] {
+			changes = append(changes, planChange{op: planDelete, rec: l})
+			wanted[l.key()] = true // drop duplicates reported by the server
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+		if a.rec.domain != b.rec.domain {
+			return a.rec.domain < b.rec.domain
+		}
+		if a.rec.rtype != b.rec.rtype {
+			return a.rec.rtype < b.rec.rtype
+		}
+		return a.op < b.op
+	})
+	return changes
+}
+
+// formatPlan renders the changes the way `plan` and `apply` print them.
+func formatPlan(zone string, changes []planChange) string {
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	amber := color.New(color.FgYellow).SprintFunc()
+
+	var sb strings.Builder
+	if len(changes) == 0 {
+		fmt.Fprintf(&sb, "✅ Zone '%s' is up to date, no changes.\n", zone)
+		return sb.String()
+	}
+
+	fmt.Fprintf(&sb, "%s %s\n\n", bold("Plan for zone:"), cyan(zone))
+	var adds, updates, deletes int
+	for _, c := range changes {
+		r := c.rec
+		switch c.op {
+		case planAdd:
+			adds++
+			fmt.Fprintf(&sb, "  %s %s %d %s %s\n", green("+"), r.domain, r.ttl, r.rtype, r.display)
+		case planDelete:
+			deletes++
+			fmt.Fprintf(&sb, "  %s %s %d %s %s\n", red("-"), r.domain, r.ttl, r.rtype, r.display)
+		case planUpdateTTL:
+			updates++
+			fmt.Fprintf(&sb, "  %s %s %s %s (ttl %d -> %d)\n", amber("~"), r.domain, r.rtype, r.display, r.ttl, c.newTTL)
+		}
+	}
+	fmt.Fprintf(&sb, "\nPlan: %s to add, %s to change, %s to delete.\n",
+		green(strconv.Itoa(adds)), amber(strconv.Itoa(updates)), red(strconv.Itoa(deletes)))
+	return sb.String()
+}
+
+// planEntry is a planChange in the structured output of `plan` and `apply`.
+type planEntry struct {
+	Action string `json:"action"`
+	Domain string `json:"domain"`
+	Type   string `json:"type"`
+	TTL    int    `json:"ttl"`
+	NewTTL int    `json:"newTtl,omitempty"`
+	Data   string `json:"data"`
+}
+
+// planEntries converts changes for structured output.
+func planEntries(changes []planChange) []planEntry {
+	entries := make([]planEntry, 0, len(changes))
+	for _, c := range changes {
+		e := planEntry{Domain: c.rec.domain, Type: c.rec.rtype, TTL: c.rec.ttl, Data: c.rec.display}
+		switch c.op {
+		case planAdd:
+			e.Action = "add"
+		case planDelete:
+			e.Action = "delete"
+		case planUpdateTTL:
+			e.Action, e.NewTTL = "update-ttl", c.newTTL
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// computePlan parses the desired zone file and diffs it against the live
+// zone.
+func computePlan(ctx context.Context, client *api.Client, zone, file string) ([]planChange, error) {
+	parsed, err := parseZoneFile(file, zone)
+	if err != nil {
+		return nil, err
+	}
+	desired, err := desiredRecords(zone, parsed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid zone file: %w", err)
+	}
+
+	_, response, err := getZoneRecords(ctx, client, zone)
+	if err != nil {
+		return nil, err
+	}
+	records, _ := response["records"].([]interface{})
+	live, skipped := liveRecords(records)
+	for _, s := range skipped {
+		fmt.Fprintf(os.Stderr, "⚠️  Ignoring unsupported record %s\n", s)
+	}
+	return diffRecords(desired, live), nil
+}
+
+// changeQuery builds the records API call for one change.
+func changeQuery(zone string, c planChange) (string, url.Values) {
+	q := url.Values{
+		"zone":   {zone},
+		"domain": {c.rec.domain},
+		"type":   {c.rec.rtype},
+	}
+	for k, v := range c.rec.values {
+		q.Set(k, v)
+	}
+	switch c.op {
+	case planAdd:
+		q.Set("ttl", strconv.Itoa(c.rec.ttl))
+		q.Set("overwrite", "false")
+		return "/api/zones/records/add", q
+	case planUpdateTTL:
+		// Without new* values the record data stays as it is.
+		q.Set("ttl", strconv.Itoa(c.newTTL))
+		return "/api/zones/records/update", q
+	default:
+		q.Del("splitText")
+		return "/api/zones/records/delete", q
+	}
+}
+
+// applyPlan executes the changes one record at a time. Deletes run first so a
+// name moving between a CNAME and other data never conflicts, then TTL
+// updates, then adds. It stops at the first failure.
+func applyPlan(ctx context.Context, client *api.Client, zone string, changes []planChange) (int, error) {
+	ordered := append([]planChange(nil), changes...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].op < ordered[j].op })
+
+	for i, c := range ordered {
+		path, q := changeQuery(zone, c)
+		if _, _, err := client.GetJSON(ctx, path, q); err != nil {
+			return i, fmt.Errorf("%s %s %s: %w", c.rec.domain, c.rec.rtype, c.rec.display, err)
+		}
+	}
+	return len(ordered), nil
+}
+
+var planCmd = &cobra.Command{
+	Use:   "plan [zone]",
+	Short: "Show the record changes needed to make a zone match a zone file",
+	Long: `Compare a zone file with the live zone and print the record changes needed to
+make the zone match it: records to add, records to delete and TTL changes.
+Nothing is changed on the server; run ` + "`tdns apply`" + ` with the same file to
+make the changes.
+
+SOA and DNSSEC records are managed by the server and are ignored on both sides.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		zone := args[0]
+		changes, err := computePlan(cmd.Context(), api.New(), zone, planFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		entries := planEntries(changes)
+		renderList(map[string]interface{}{"zone": zone, "changes": entries}, entries, func() {
+			fmt.Print(formatPlan(zone, changes))
+		})
+	},
+}
+
+// checkApplyStdin rejects reading the zone file from standard input without
+// --yes: stdin is used up by the zone file, so the prompt would read nothing
+// and always abort.
+func checkApplyStdin(file string, yes bool) error {
+	if file == "-" && !yes {
+		return fmt.Errorf("--file - needs --yes, as the confirmation prompt can't be answered on standard input")
+	}
+	return nil
+}
+
+var applyCmd = &cobra.Command{
+	Use:   "apply [zone]",
+	Short: "Make a zone match a zone file, one record at a time",
+	Long: `Compute the same plan as ` + "`tdns plan`" + `, print it, and after confirmation
+execute exactly those changes with individual record API calls.
+
+Unlike ` + "`tdns import --overwrite-zone`" + `, the zone is never wiped: records that
+already match are left alone, and records not in the zone file are deleted one
+by one. SOA and DNSSEC records are never touched.
+
+With --file - the zone file is read from standard input, which then can't
+answer the confirmation prompt, so --yes is required.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := checkApplyStdin(planFile, assumeYes); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		zone := args[0]
+		client := api.New()
+		changes, err := computePlan(cmd.Context(), client, zone, planFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		entries := planEntries(changes)
+		result := map[string]interface{}{"zone": zone, "changes": entries, "applied": 0}
+		if !structuredOutput() {
+			fmt.Print(formatPlan(zone, changes))
+		}
+		if len(changes) == 0 {
+			renderList(result, entries, func() {})
+			return
+		}
+
+		if !confirmed(fmt.Sprintf("\nApply these changes to zone '%s'?", zone)) {
+			return
+		}
+
+		done, err := applyPlan(cmd.Context(), client, zone, changes)
+		if err != nil {
+			exitIfInterrupted(cmd.Context(), err, fmt.Sprintf("applied %d of %d changes; run 'tdns plan' to see what is left.", done, len(changes)))
+			fmt.Fprintf(os.Stderr, "❌ Applied %d of %d changes, then failed: %v\n", done, len(changes), err)
+			os.Exit(1)
+		}
+		result["applied"] = done
+		renderList(result, entries, func() {
+			fmt.Printf("✅ Applied %d changes to zone '%s'.\n", done, zone)
+		})
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{planCmd, applyCmd} {
+		c.Flags().StringVarP(&planFile, "file", "f", "", "Desired zone file, or - to read it from stdin (required)")
+		if err := c.MarkFlagRequired("file"); err != nil {
+			panic(err)
+		}
+		rootCmd.AddCommand(c)
+	}
+	applyCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Assume yes when asking for confirmation")
+}
@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"tdns/internal/api"
+	"tdns/internal/zonefile"
+)
+
+const liveRecordsResponse = `{"status":"ok","response":{"zone":{"name":"example.com"},"records":[
+	{"name":"example.com","type":"SOA","ttl":900,"rData":{"primaryNameServer":"ns1.example.com"}},
+	{"name":"example.com","type":"NS","ttl":3600,"rData":{"nameServer":"ns1.example.com"}},
+	{"name":"example.com","type":"MX","ttl":3600,"rData":{"preference":10,"exchange":"mx1.example.com"}},
+	{"name":"www.example.com","type":"A","ttl":3600,"rData":{"ipAddress":"192.0.2.1"}},
+	{"name":"old.example.com","type":"A","ttl":3600,"rData":{"ipAddress":"192.0.2.9"}},
+	{"name":"example.com","type":"DS","ttl":3600,"rData":{"keyTag":2371,"algorithm":"RSASHA256","digestType":"SHA256","digest":"ABCD"}},
+	{"name":"example.com","type":"HINFO","ttl":3600,"rData":{}}
+]}}`
+
+const desiredZone = `$TTL 3600
+@     NS    ns1.example.com.
+@     MX    10 MX1.example.com.
+@     DS    2371 8 2 ab cd
+www   300 A 192.0.2.1
+new   A     192.0.2.5
+`
+
+func planFromStrings(t *testing.T, zone string) []planChange {
+	t.Helper()
+	parsed, err := zonefile.Parse(strings.NewReader(zone), "example.com", "")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	desired, err := desiredRecords("example.com", parsed)
+	if err != nil {
+		t.Fatalf("desiredRecords: %v", err)
+	}
+	live, skipped := liveRecords(liveTestRecords(t))
+	if len(skipped) != 1 || !strings.Contains(skipped[0], "HINFO") {
+		t.Errorf("skipped = %v, want the HINFO record", skipped)
+	}
+	return diffRecords(desired, live)
+}
+
+// liveTestRecords decodes the records of liveRecordsResponse.
+func liveTestRecords(t *testing.T) []interface{} {
+	t.Helper()
+	var envelope struct {
+		Response struct {
+			Records []interface{} `json:"records"`
+		} `json:"response"`
+	}
+	if err := json.Unmarshal([]byte(liveRecordsResponse), &envelope); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return envelope.Response.Records
+}
+
+func TestDiffRecords(t *testing.T) {
+	changes := planFromStrings(t, desiredZone)
+
+	// MX and DS match despite case, trailing dots, mnemonics and digest
+	// spacing; www only changes TTL; old is deleted and new added. SOA is
+	// never part of a plan.
+	var got []string
+	for _, c := range changes {
+		got = append(got, fmt.Sprintf("%d %s %s", c.op, c.rec.domain, c.rec.rtype))
+	}
+	want := []string{
+		fmt.Sprintf("%d new.example.com A", planAdd),
+		fmt.Sprintf("%d old.example.com A", planDelete),
+		fmt.Sprintf("%d www.example.com A", planUpdateTTL),
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("changes:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	for _, c := range changes {
+		if c.op == planUpdateTTL && (c.rec.ttl != 3600 || c.newTTL != 300) {
+			t.Errorf("ttl change = %d -> %d, want 3600 -> 300", c.rec.ttl, c.newTTL)
+		}
+	}
+
+	out := formatPlan("example.com", changes)
+	if !strings.Contains(out, "1 to add") || !strings.Contains(out, "1 to change") || !strings.Contains(out, "1 to delete") {
+		t.Errorf("summary missing from plan:\n%s", out)
+	}
+}
+
+func TestDesiredRecordsRejectsOutOfZoneNames(t *testing.T) {
+	parsed, err := zonefile.Parse(strings.NewReader("$TTL 60\nhost.example.org. A 192.0.2.1\n"), "example.com", "")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if _, err := desiredRecords("example.com", parsed); err == nil || !strings.Contains(err.Error(), "outside zone") {
+		t.Errorf("err = %v, want an outside-zone error", err)
+	}
+}
+
+func TestZoneRecordValues(t *testing.T) {
+	spec, _ := lookupRecordType("HTTPS")
+	values, err := zoneRecordValues(spec, []string{"1", ".", "alpn=", "h2,h3", "port=443"})
+	if err != nil {
+		t.Fatalf("zoneRecordValues: %v", err)
+	}
+	if values["svcParams"] != "alpn|h2,h3|port|443" || values["svcTargetName"] != "." {
+		t.Errorf("values = %v", values)
+	}
+
+	spec, _ = lookupRecordType("TXT")
+	values, _ = zoneRecordValues(spec, []string{"a", "b"})
+	if values["text"] != "a\nb" || values["splitText"] != "true" {
+		t.Errorf("TXT values = %v", values)
+	}
+}
+
+// applyRequest records one call the stub server received.
+type applyRequest struct {
+	path  string
+	query map[string][]string
+}
+
+func TestApplyCmdExecutesPlanInOrder(t *testing.T) {
+	var got []applyRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, applyRequest{path: r.URL.Path, query: r.URL.Query()})
+		if r.URL.Path == "/api/zones/records/get" {
+			fmt.Fprint(w, liveRecordsResponse)
+			return
+		}
+		fmt.Fprint(w, `{"status":"ok","response":{}}`)
+	}))
+	defer srv.Close()
+
+	oldHost := viper.GetString("host")
+	viper.Set("host", srv.URL)
+	defer viper.Set("host", oldHost)
+
+	planCmd.Flags().Lookup("file").Changed = false
+	applyCmd.Flags().Lookup("file").Changed = false
+	assumeYes = false
+
+	oldStdout := os.Stdout
+	rp, wp, _ := os.Pipe()
+	os.Stdout = wp
+	defer func() { os.Stdout = oldStdout }()
+	go func() { _, _ = io.Copy(io.Discard, rp) }()
+
+	rootCmd.SetArgs([]string{"apply", "example.com", "--file", writeTempFile(t, desiredZone), "--yes"})
+	defer rootCmd.SetArgs(nil)
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	wp.Close()
+
+	var paths []string
+	for _, r := range got {
+		paths = append(paths, r.path)
+	}
+	want := []string{
+		"/api/zones/records/get",
+		"/api/zones/records/delete",
+		"/api/zones/records/update",
+		"/api/zones/records/add",
+	}
+	if strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Fatalf("requests = %v, want %v", paths, want)
+	}
+	for k, v := range map[string]string{"domain": "old.example.com", "type": "A", "ipAddress": "192.0.2.9"} {
+		if q := got[1].query[k]; len(q) != 1 || q[0] != v {
+			t.Errorf("delete %s = %v, want %q", k, q, v)
+		}
+	}
+	if q := got[2].query["ttl"]; len(q) != 1 || q[0] != "300" {
+		t.Errorf("update ttl = %v, want 300", q)
+	}
+	for k, v := range map[string]string{"domain": "new.example.com", "ipAddress": "192.0.2.5", "ttl": "3600", "overwrite": "false"} {
+		if q := got[3].query[k]; len(q) != 1 || q[0] != v {
+			t.Errorf("add %s = %v, want %q", k, q, v)
+		}
+	}
+}
+
+func TestApplyPlanStopsWhenCancelled(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"status":"ok","response":{}}`)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	parsed, err := zonefile.Parse(strings.NewReader("www 60 IN A 192.0.2.1\n"), "example.com", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	desired, err := desiredRecords("example.com", parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes := []planChange{{op: planAdd, rec: desired[0]}}
+	done, err := applyPlan(ctx, &api.Client{Host: srv.URL, HTTP: srv.Client()}, "example.com", changes)
+	if done != 0 || !errors.Is(err, context.Canceled) {
+		t.Errorf("applyPlan = %d, %v; want 0 and context.Canceled", done, err)
+	}
+	if requests != 0 {
+		t.Errorf("server got %d requests after cancellation", requests)
+	}
+}
+
+func TestCheckApplyStdin(t *testing.T) {
+	if err := checkApplyStdin("-", false); err == nil || !strings.Contains(err.Error(), "--yes") {
+		t.Errorf("--file - without --yes: err = %v", err)
+	}
+	if err := checkApplyStdin("-", true); err != nil {
+		t.Errorf("--file - with --yes: %v", err)
+	}
+	if err := checkApplyStdin("example.com.zone", false); err != nil {
+		t.Errorf("a file without --yes: %v", err)
+	}
+}
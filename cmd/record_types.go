@@ -0,0 +1,450 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// paramKind says how the value of a record parameter is validated.
+type paramKind int
+
+const (
+	kindText      paramKind = iota // free-form text
+	kindDomain                     // a domain name
+	kindIPv4                       // an IPv4 address
+	kindIPv6                       // an IPv6 address
+	kindIP                         // an IPv4 or IPv6 address
+	kindUint8                      // 0-255
+	kindUint16                     // 0-65535
+	kindUint32                     // 0-4294967295
+	kindHex                        // hex-encoded bytes, whitespace allowed
+	kindBool                       // true/false
+	kindEnum                       // a number 0-255 or one of the parameter's mnemonics
+	kindSvcParams                  // pipe-separated SVCB key|value pairs
+)
+
+// recordParam describes one /api/zones/records/* query parameter.
+//
+// Identity parameters make up the record data, so they select the record to
+// update or delete and take a `new<Name>` counterpart on update. Setting
+// parameters (setting: true) only influence how the server stores the record
+// and are sent as-is on add and update.
+type recordParam struct {
+	name     string // API parameter name, also used as the flag name
+	help     string
+	kind     paramKind
+	required bool
+	setting  bool
+	enum     map[string]int // mnemonics accepted by kindEnum, keyed by normalizeMnemonic
+	rdata    string         // key in the API's rData object, when it differs from name
+}
+
+// rdataKey is the key holding this parameter in the records/get rData object.
+func (p recordParam) rdataKey() string {
+	if p.rdata != "" {
+		return p.rdata
+	}
+	return p.name
+}
+
+// recordTypeSpec lists the parameters the API takes for one record type.
+type recordTypeSpec struct {
+	name    string
+	summary string
+	params  []recordParam
+	// singleton types hold one record per name, so update takes only the new
+	// values and delete needs no record data.
+	singleton bool
+	// updateOnly types exist on every zone and can only be edited.
+	updateOnly bool
+}
+
+// Mnemonics for enum parameters, keyed by normalizeMnemonic. The API accepts
+// either the mnemonic or the number, and so do the flags.
+var (
+	dnssecAlgorithms = map[string]int{
+		"RSAMD5": 1, "DSA": 3, "RSASHA1": 5, "DSANSEC3SHA1": 6, "RSASHA1NSEC3SHA1": 7,
+		"RSASHA256": 8, "RSASHA512": 10, "ECCGOST": 12, "ECDSAP256SHA256": 13,
+		"ECDSAP384SHA384": 14, "ED25519": 15, "ED448": 16,
+	}
+	dsDigestTypes = map[string]int{"SHA1": 1, "SHA256": 2, "GOSTR341194": 3, "SHA384": 4}
+	sshfpAlgs     = map[string]int{"RSA": 1, "DSA": 2, "ECDSA": 3, "ED25519": 4, "ED448": 6}
+	sshfpFpTypes  = map[string]int{"SHA1": 1, "SHA256": 2}
+	tlsaUsages    = map[string]int{"PKIXTA": 0, "PKIXEE": 1, "DANETA": 2, "DANEEE": 3}
+	tlsaSelectors = map[string]int{"CERT": 0, "SPKI": 1}
+	tlsaMatching  = map[string]int{"FULL": 0, "SHA2256": 1, "SHA2512": 2}
+)
+
+// normalizeMnemonic folds case and separators so "PKIX-TA", "pkix_ta" and
+// "PKIXTA" compare equal.
+func normalizeMnemonic(s string) string {
+	return strings.NewReplacer("-", "", "_", "", " ", "").Replace(strings.ToUpper(s))
+}
+
+// recordTypes is every record type the records commands can manage.
+var recordTypes = []recordTypeSpec{
+	{name: "A", summary: "IPv4 address", params: []recordParam{
+		{name: "ipAddress", help: "IP address", kind: kindIPv4, required: true},
+		{name: "ptr", help: "Also add a PTR record for the address", kind: kindBool, setting: true},
+		{name: "createPtrZone", help: "Create the reverse zone for --ptr if missing", kind: kindBool, setting: true},
+	}},
+	{name: "AAAA", summary: "IPv6 address", params: []recordParam{
+		{name: "ipAddress", help: "IP address", kind: kindIPv6, required: true},
+		{name: "ptr", help: "Also add a PTR record for the address", kind: kindBool, setting: true},
+		{name: "createPtrZone", help: "Create the reverse zone for --ptr if missing", kind: kindBool, setting: true},
+	}},
+	{name: "NS", summary: "Name server", params: []recordParam{
+		{name: "nameServer", help: "Name server domain", kind: kindDomain, required: true},
+		{name: "glue", help: "Comma-separated glue addresses", kind: kindText, setting: true},
+	}},
+	{name: "CNAME", summary: "Canonical name (alias)", singleton: true, params: []recordParam{
+		{name: "cname", help: "CNAME target", kind: kindDomain, required: true},
+	}},
+	{name: "DNAME", summary: "Delegation name", singleton: true, params: []recordParam{
+		{name: "dname", help: "DNAME target", kind: kindDomain, required: true},
+	}},
+	{name: "PTR", summary: "Pointer", params: []recordParam{
+		{name: "ptrName", help: "PTR target domain", kind: kindDomain, required: true},
+	}},
+	{name: "MX", summary: "Mail exchanger", params: []recordParam{
+		{name: "preference", help: "MX preference", kind: kindUint16, required: true},
+		{name: "exchange", help: "MX mail server", kind: kindDomain, required: true},
+	}},
+	{name: "TXT", summary: "Text", params: []recordParam{
+		{name: "text", help: "Text data", kind: kindText, required: true},
+		{name: "splitText", help: "Split the text on new lines into separate character-strings", kind: kindBool, setting: true},
+	}},
+	{name: "RP", summary: "Responsible person", params: []recordParam{
+		{name: "mailbox", help: "RP mailbox domain", kind: kindDomain, required: true},
+		{name: "txtDomain", help: "RP TXT record domain", kind: kindDomain},
+	}},
+	{name: "SRV", summary: "Service locator", params: []recordParam{
+		{name: "priority", help: "SRV priority", kind: kindUint16, required: true},
+		{name: "weight", help: "SRV weight", kind: kindUint16, required: true},
+		{name: "port", help: "SRV port", kind: kindUint16, required: true},
+		{name: "target", help: "SRV target domain", kind: kindDomain, required: true},
+	}},
+	{name: "NAPTR", summary: "Naming authority pointer", params: []recordParam{
+		{name: "naptrOrder", help: "NAPTR order", kind: kindUint16, required: true, rdata: "order"},
+		{name: "naptrPreference", help: "NAPTR preference", kind: kindUint16, required: true, rdata: "preference"},
+		{name: "naptrFlags", help: "NAPTR flags", kind: kindText, rdata: "flags"},
+		{name: "naptrServices", help: "NAPTR services", kind: kindText, rdata: "services"},
+		{name: "naptrRegexp", help: "NAPTR regular expression", kind: kindText, rdata: "regexp"},
+		{name: "naptrReplacement", help: "NAPTR replacement domain", kind: kindDomain, rdata: "replacement"},
+	}},
+	{name: "DS", summary: "Delegation signer", params: []recordParam{
+		{name: "keyTag", help: "DS key tag", kind: kindUint16, required: true},
+		{name: "algorithm", help: "DNSSEC algorithm (e.g. RSASHA256, ECDSAP256SHA256 or its number)", kind: kindEnum, required: true, enum: dnssecAlgorithms},
+		{name: "digestType", help: "DS digest type (SHA1, SHA256, SHA384 or its number)", kind: kindEnum, required: true, enum: dsDigestTypes},
+		{name: "digest", help: "DS digest in hex", kind: kindHex, required: true},
+	}},
+	{name: "SSHFP", summary: "SSH key fingerprint", params: []recordParam{
+		{name: "sshfpAlgorithm", help: "SSHFP algorithm (RSA, DSA, ECDSA, Ed25519, Ed448 or its number)", kind: kindEnum, required: true, enum: sshfpAlgs, rdata: "algorithm"},
+		{name: "sshfpFingerprintType", help: "SSHFP fingerprint type (SHA1, SHA256 or its number)", kind: kindEnum, required: true, enum: sshfpFpTypes, rdata: "fingerprintType"},
+		{name: "sshfpFingerprint", help: "SSHFP fingerprint in hex", kind: kindHex, required: true, rdata: "fingerprint"},
+	}},
+	{name: "TLSA", summary: "DANE certificate association", params: []recordParam{
+		{name: "tlsaCertificateUsage", help: "TLSA certificate usage (PKIX-TA, PKIX-EE, DANE-TA, DANE-EE or its number)", kind: kindEnum, required: true, enum: tlsaUsages, rdata: "certificateUsage"},
+		{name: "tlsaSelector", help: "TLSA selector (Cert, SPKI or its number)", kind: kindEnum, required: true, enum: tlsaSelectors, rdata: "selector"},
+		{name: "tlsaMatchingType", help: "TLSA matching type (Full, SHA2-256, SHA2-512 or its number)", kind: kindEnum, required: true, enum: tlsaMatching, rdata: "matchingType"},
+		{name: "tlsaCertificateAssociationData", help: "TLSA certificate association data", kind: kindText, required: true, rdata: "certificateAssociationData"},
+	}},
+	{name: "SVCB", summary: "Service binding", params: svcbParams()},
+	{name: "HTTPS", summary: "HTTPS service binding", params: svcbParams()},
+	{name: "URI", summary: "Uniform resource identifier", params: []recordParam{
+		{name: "uriPriority", help: "URI priority", kind: kindUint16, required: true, rdata: "priority"},
+		{name: "uriWeight", help: "URI weight", kind: kindUint16, required: true, rdata: "weight"},
+		{name: "uri", help: "URI target", kind: kindText, required: true},
+	}},
+	{name: "CAA", summary: "Certification authority authorization", params: []recordParam{
+		{name: "flags", help: "CAA flags", kind: kindUint8, required: true},
+		{name: "tag", help: "CAA tag (issue, issuewild, iodef)", kind: kindText, required: true},
+		{name: "value", help: "CAA value", kind: kindText, required: true},
+	}},
+	{name: "ANAME", summary: "Apex alias (Technitium)", params: []recordParam{
+		{name: "aname", help: "ANAME target", kind: kindDomain, required: true},
+	}},
+	{name: "FWD", summary: "Conditional forwarder (Technitium)", params: []recordParam{
+		{name: "protocol", help: "Forwarder protocol (Udp, Tcp, Tls, Https, Quic)", kind: kindText, required: true},
+		{name: "forwarder", help: "Forwarder address, or this-server", kind: kindText, required: true},
+		{name: "forwarderPriority", help: "Forwarder priority", kind: kindUint8, setting: true},
+		{name: "dnssecValidation", help: "Validate DNSSEC for forwarded queries", kind: kindBool, setting: true},
+		{name: "proxyType", help: "Proxy type (NoProxy, DefaultProxy, Http, Socks5)", kind: kindText, setting: true},
+		{name: "proxyAddress", help: "Proxy address", kind: kindText, setting: true},
+		{name: "proxyPort", help: "Proxy port", kind: kindUint16, setting: true},
+		{name: "proxyUsername", help: "Proxy username", kind: kindText, setting: true},
+		{name: "proxyPassword", help: "Proxy password", kind: kindText, setting: true},
+	}},
+	{name: "APP", summary: "DNS App record (Technitium)", singleton: true, params: []recordParam{
+		{name: "appName", help: "Installed DNS App name", kind: kindText, required: true},
+		{name: "classPath", help: "DNS App class path", kind: kindText, required: true},
+		{name: "recordData", help: "DNS App record data (usually JSON)", kind: kindText, rdata: "data"},
+	}},
+	{name: "SOA", summary: "Start of authority", singleton: true, updateOnly: true, params: []recordParam{
+		{name: "primaryNameServer", help: "SOA primary name server", kind: kindDomain},
+		{name: "responsiblePerson", help: "SOA responsible person mailbox", kind: kindDomain},
+		{name: "serial", help: "SOA serial", kind: kindUint32},
+		{name: "refresh", help: "SOA refresh interval in seconds", kind: kindUint32},
+		{name: "retry", help: "SOA retry interval in seconds", kind: kindUint32},
+		{name: "expire", help: "SOA expire interval in seconds", kind: kindUint32},
+		{name: "minimum", help: "SOA minimum (negative caching) TTL in seconds", kind: kindUint32},
+		{name: "useSerialDateScheme", help: "Use a date-based SOA serial", kind: kindBool, setting: true},
+	}},
+}
+
+func svcbParams() []recordParam {
+	return []recordParam{
+		{name: "svcPriority", help: "SVCB priority (0 for alias mode)", kind: kindUint16, required: true},
+		{name: "svcTargetName", help: "SVCB target name", kind: kindDomain, required: true},
+		{name: "svcParams", help: "SVCB params as pipe-separated key|value pairs (e.g. alpn|h2,h3|port|443)", kind: kindSvcParams},
+		{name: "autoIpv4Hint", help: "Fill ipv4hint from the target's A records", kind: kindBool, setting: true},
+		{name: "autoIpv6Hint", help: "Fill ipv6hint from the target's AAAA records", kind: kindBool, setting: true},
+	}
+}
+
+// lookupRecordType returns the spec for t, matched case-insensitively.
+func lookupRecordType(t string) (recordTypeSpec, bool) {
+	for _, spec := range recordTypes {
+		if strings.EqualFold(spec.name, t) {
+			return spec, true
+		}
+	}
+	return recordTypeSpec{}, false
+}
+
+// recordTypeNames lists the supported types in declaration order.
+func recordTypeNames() []string {
+	names := make([]string, 0, len(recordTypes))
+	for _, spec := range recordTypes {
+		names = append(names, spec.name)
+	}
+	return names
+}
+
+// newParamName is the update parameter carrying the new value of an identity
+// parameter, e.g. ipAddress -> newIpAddress. The API spells the ANAME one
+// newAName, and puts "New" after the prefix of the NAPTR ones, e.g.
+// naptrOrder -> naptrNewOrder.
+func newParamName(name string) string {
+	if name == "aname" {
+		return "newAName"
+	}
+	if rest, ok := strings.CutPrefix(name, "naptr"); ok {
+		return "naptrNew" + rest
+	}
+	return "new" + strings.ToUpper(name[:1]) + name[1:]
+}
+
+// recordFlag is one per-type flag, with the help text of every type using it
+// merged so `--help` lists them once.
+type recordFlag struct {
+	name  string
+	help  string
+	kind  paramKind
+	types []string
+}
+
+// recordFlags returns the per-type flags for add/delete, plus the `new*` ones
+// when forUpdate is set, sorted by name.
+func recordFlags(forUpdate bool) []recordFlag {
+	byName := map[string]*recordFlag{}
+	add := func(name, help string, kind paramKind, typ string) {
+		f, ok := byName[name]
+		if !ok {
+			f = &recordFlag{name: name, help: help, kind: kind}
+			byName[name] = f
+		}
+		f.types = append(f.types, typ)
+	}
+	for _, spec := range recordTypes {
+		for _, p := range spec.params {
+			add(p.name, p.help, p.kind, spec.name)
+			if forUpdate && !p.setting && !spec.singleton {
+				add(newParamName(p.name), "New "+strings.ToLower(p.help[:1])+p.help[1:], p.kind, spec.name)
+			}
+		}
+	}
+	out := make([]recordFlag, 0, len(byName))
+	for _, f := range byName {
+		out = append(out, *f)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].name < out[j].name })
+	return out
+}
+
+// recordOp selects which records endpoint a parameter set is built for.
+type recordOp int
+
+const (
+	recordAdd recordOp = iota
+	recordUpdate
+	recordDelete
+)
+
+// buildRecordParams validates the per-type values for op and returns them as
+// query parameters. vals holds only the flags the user actually set.
+func buildRecordParams(spec recordTypeSpec, vals map[string]string, op recordOp) (url.Values, error) {
+	if spec.updateOnly && op != recordUpdate {
+		return nil, fmt.Errorf("%s records can only be updated", spec.name)
+	}
+
+	known := map[string]recordParam{}
+	for _, p := range spec.params {
+		known[p.name] = p
+		if op == recordUpdate && !p.setting && !spec.singleton {
+			known[newParamName(p.name)] = p
+		}
+	}
+	for name := range vals {
+		if _, ok := known[name]; !ok {
+			return nil, fmt.Errorf("--%s does not apply to %s records", name, spec.name)
+		}
+	}
+
+	q := url.Values{}
+	for _, p := range spec.params {
+		v, set := vals[p.name]
+		if p.setting {
+			if op == recordDelete || !set {
+				continue
+			}
+		} else if !set {
+			// Delete identifies the record by its data, so required identity
+			// values are needed there too; singletons only need the name.
+			needed := p.required && (op == recordAdd || !spec.singleton)
+			if needed {
+				return nil, fmt.Errorf("--%s is required for %s records", p.name, spec.name)
+			}
+		}
+		if set {
+			if spec.singleton && op == recordDelete {
+				continue
+			}
+			if err := validateParam(p, v); err != nil {
+				return nil, fmt.Errorf("--%s: %w", p.name, err)
+			}
+			q.Set(p.name, v)
+		}
+		if op == recordUpdate && !p.setting && !spec.singleton {
+			nn := newParamName(p.name)
+			if nv, ok := vals[nn]; ok {
+				if err := validateParam(p, nv); err != nil {
+					return nil, fmt.Errorf("--%s: %w", nn, err)
+				}
+				q.Set(nn, nv)
+			}
+		}
+	}
+	return q, nil
+}
+
+// validateParam checks v against the parameter's kind.
+func validateParam(p recordParam, v string) error {
+	if strings.TrimSpace(v) == "" && p.kind != kindText {
+		return fmt.Errorf("value must not be empty")
+	}
+	switch p.kind {
+	case kindDomain:
+		return validateDomainName(v)
+	case kindIPv4:
+		if ip := net.ParseIP(v); ip == nil || ip.To4() == nil {
+			return fmt.Errorf("%q is not an IPv4 address", v)
+		}
+	case kindIPv6:
+		if ip := net.ParseIP(v); ip == nil || ip.To4() != nil {
+			return fmt.Errorf("%q is not an IPv6 address", v)
+		}
+	case kindIP:
+		if net.ParseIP(v) == nil {
+			return fmt.Errorf("%q is not an IP address", v)
+		}
+	case kindUint8, kindUint16, kindUint32:
+		bits := map[paramKind]int{kindUint8: 8, kindUint16: 16, kindUint32: 32}[p.kind]
+		if _, err := strconv.ParseUint(v, 10, bits); err != nil {
+			return fmt.Errorf("%q is not a number between 0 and %d", v, uint64(1)<<bits-1)
+		}
+	case kindHex:
+		if _, err := hex.DecodeString(strings.Join(strings.Fields(v), "")); err != nil {
+			return fmt.Errorf("%q is not valid hex", v)
+		}
+	case kindBool:
+		if _, err := strconv.ParseBool(v); err != nil {
+			return fmt.Errorf("%q is not true or false", v)
+		}
+	case kindEnum:
+		if _, err := strconv.ParseUint(v, 10, 8); err == nil {
+			return nil
+		}
+		if _, ok := p.enum[normalizeMnemonic(v)]; !ok {
+			return fmt.Errorf("unknown value %q", v)
+		}
+	case kindSvcParams:
+		if v == "false" {
+			return nil
+		}
+		if n := len(strings.Split(v, "|")); n%2 != 0 {
+			return fmt.Errorf("%q must be key|value pairs", v)
+		}
+	}
+	return nil
+}
+
+// validateDomainName does the structural checks of RFC 1035: at most 255
+// octets, labels of 1-63 octets and no whitespace. A trailing dot is allowed.
+func validateDomainName(name string) error {
+	n := strings.TrimSuffix(name, ".")
+	if n == "" {
+		if name == "." {
+			return nil
+		}
+		return fmt.Errorf("domain name must not be empty")
+	}
+	if len(n) > 253 {
+		return fmt.Errorf("domain name %q is longer than 255 octets", name)
+	}
+	for _, label := range strings.Split(n, ".") {
+		if label == "" {
+			return fmt.Errorf("domain name %q has an empty label", name)
+		}
+		if len(label) > 63 {
+			return fmt.Errorf("label %q in %q is longer than 63 octets", label, name)
+		}
+		if strings.ContainsAny(label, " \t\r\n") {
+			return fmt.Errorf("domain name %q contains whitespace", name)
+		}
+	}
+	return nil
+}
+
+// formatRecordTypesHelp renders the parameters of each type for
+// `tdns records types`. An empty only lists every type.
+func formatRecordTypesHelp(only string) string {
+	var sb strings.Builder
+	for _, spec := range recordTypes {
+		if only != "" && !strings.EqualFold(only, spec.name) {
+			continue
+		}
+		notes := ""
+		switch {
+		case spec.updateOnly:
+			notes = " (update only)"
+		case spec.singleton:
+			notes = " (one per name; update takes the new values directly)"
+		}
+		fmt.Fprintf(&sb, "%s %s%s\n", bold(spec.name), spec.summary, grey(notes))
+		for _, p := range spec.params {
+			req := ""
+			if p.required {
+				req = yellow(" (required)")
+			}
+			fmt.Fprintf(&sb, "  --%-32s %s%s\n", p.name, p.help, req)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
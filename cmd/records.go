@@ -1,7 +1,7 @@
 package cmd
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"net/url"
 	"os"
@@ -9,22 +9,35 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"tdns/internal/api"
 )
 
 var (
 	recordType string
-	jsonOutput bool
 	overwrite  bool
 	assumeYes  bool
 	zoneName   string
 	recordTTL  int
-	ipAddress  string
-	cnameValue string
 	domainName string
+
+	newDomainName  string
+	recordComments string
+	recordDisable  bool
 )
 
+// getZoneRecords fetches every record in zone as raw JSON, for `plan` and
+// `apply`.
+func getZoneRecords(ctx context.Context, client *api.Client, zone string) (map[string]interface{}, map[string]interface{}, error) {
+	q := url.Values{
+		"domain":   {zone},
+		"zone":     {zone},
+		"listZone": {"true"},
+	}
+	return client.GetJSON(ctx, "/api/zones/records/get", q)
+}
+
 var recordsGetCmd = &cobra.Command{
 	Use:     "get [zone]",
 	Aliases: []string{"ge"},
@@ -32,46 +45,35 @@ var recordsGetCmd = &cobra.Command{
 	Args:    cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		zone := args[0]
-		q := url.Values{
-			"domain":   {zone},
-			"zone":     {zone},
-			"listZone": {"true"},
-		}
-		result, response, err := api.New().GetJSON("/api/zones/records/get", q)
+		recs, err := api.New().GetRecords(cmd.Context(), zone, zone, true)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
 			os.Exit(1)
 		}
-
-		if jsonOutput {
-			raw, _ := json.MarshalIndent(result, "", "  ")
-			fmt.Println(string(raw))
-			return
-		}
-
-		records, ok := response["records"].([]interface{})
-		if !ok || len(records) == 0 {
+		if len(recs.Records) == 0 && !structuredOutput() {
 			fmt.Printf("No records found for %s.\n", zone)
 			return
 		}
 
-		fmt.Printf("%s %s\n\n", bold("Records for zone:"), cyan(zone))
-		for _, r := range records {
-			rec := r.(map[string]interface{})
-			rtype := rec["type"].(string)
-			if recordType != "" && strings.ToUpper(recordType) != rtype {
-				continue
+		filtered := recs.Records[:0:0]
+		for _, rec := range recs.Records {
+			if recordType == "" || strings.EqualFold(recordType, rec.Type) {
+				filtered = append(filtered, rec)
 			}
+		}
+		recs.Records = filtered
 
-			name := rec["name"]
-			ttl := rec["ttl"]
-			recordValue := "None"
-			if rVal, ok := rec["rData"]; ok && rVal != nil {
-				recordValue = FormatMap(rVal)
-			}
+		renderList(recs, recs.Records, func() {
+			fmt.Printf("%s %s\n\n", bold("Records for zone:"), cyan(zone))
+			for _, rec := range recs.Records {
+				recordValue := "None"
+				if rec.RData != nil {
+					recordValue = FormatMap(rec.RData)
+				}
 
-			fmt.Printf("%s  %s  %g  %s\n", greenL(name), rtype, ttl, recordValue)
-		}
+				fmt.Printf("%s  %s  %d  %s\n", greenL(rec.Name), rec.Type, rec.TTL, recordValue)
+			}
+		})
 	},
 }
 
@@ -81,48 +83,137 @@ var recordsCmd = &cobra.Command{
 	Short:   "Manage zone records",
 }
 
-func recordQuery() url.Values {
-	q := url.Values{
-		"domain": {domainName},
-		"zone":   {zoneName},
-		"type":   {recordType},
+// recordQuery builds the parameters shared by add, update and delete, plus
+// the per-type values set on cmd, validated for op.
+func recordQuery(cmd *cobra.Command, op recordOp) (url.Values, error) {
+	if zoneName == "" || recordType == "" {
+		return nil, fmt.Errorf("--zone and --type are required")
 	}
-	if recordTTL >= 0 {
+	spec, ok := lookupRecordType(recordType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported record type %q (see `tdns records types`)", recordType)
+	}
+
+	vals := map[string]string{}
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if _, ok := recordFlagNames[f.Name]; ok {
+			vals[f.Name] = f.Value.String()
+		}
+	})
+	q, err := buildRecordParams(spec, vals, op)
+	if err != nil {
+		return nil, err
+	}
+
+	domain := domainName
+	if domain == "" {
+		domain = zoneName
+	}
+	q.Set("domain", domain)
+	q.Set("zone", zoneName)
+	q.Set("type", spec.name)
+	if recordTTL >= 0 && op != recordDelete {
 		q.Set("ttl", strconv.Itoa(recordTTL))
 	}
-	if ipAddress != "" {
-		q.Set("ipAddress", ipAddress)
+	if recordComments != "" && op != recordDelete {
+		q.Set("comments", recordComments)
 	}
-	if cnameValue != "" {
-		q.Set("cname", cnameValue)
+	return q, nil
+}
+
+// recordFlagNames holds every per-type flag name, so recordQuery can tell
+// them apart from the common flags.
+var recordFlagNames = map[string]struct{}{}
+
+// addRecordFlags registers the flags shared by add, update and delete, and
+// one flag per API parameter of the supported record types.
+func addRecordFlags(cmd *cobra.Command, forUpdate bool) {
+	cmd.Flags().StringVarP(&zoneName, "zone", "z", "", "Zone name")
+	cmd.Flags().StringVarP(&domainName, "domain", "n", "", "Domain name (default: the zone apex)")
+	cmd.Flags().StringVarP(&recordType, "type", "r", "", "Record type ("+strings.Join(recordTypeNames(), ", ")+")")
+	for _, f := range recordFlags(forUpdate) {
+		recordFlagNames[f.name] = struct{}{}
+		help := fmt.Sprintf("%s (%s)", f.help, strings.Join(f.types, ", "))
+		if f.kind == kindBool {
+			cmd.Flags().Bool(f.name, false, help)
+		} else {
+			cmd.Flags().String(f.name, "", help)
+		}
 	}
-	return q
 }
 
+const recordTypesHint = `Each record type takes its own parameters, e.g. --ipAddress for A/AAAA,
+--preference and --exchange for MX or --priority, --weight, --port and --target
+for SRV. Run ` + "`tdns records types [type]`" + ` to list them.`
+
 var recordsAddCmd = &cobra.Command{
 	Use:     "add",
 	Aliases: []string{"a"},
 	Short:   "Add a new record to a zone",
+	Long: `Add a new record to a zone.
+
+` + recordTypesHint + `
+
+  tdns records add -z example.com -n example.com -r MX --preference 10 --exchange mx1.example.com
+  tdns records add -z example.com -n _sip._tcp.example.com -r SRV --priority 10 --weight 5 --port 5060 --target sip.example.com`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if zoneName == "" || recordType == "" {
-			fmt.Fprintln(os.Stderr, "❌ --zone and --type are required")
+		q, err := recordQuery(cmd, recordAdd)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
 			os.Exit(1)
 		}
-
-		q := recordQuery()
 		q.Set("overwrite", strconv.FormatBool(overwrite))
 
-		result, _, err := api.New().GetJSON("/api/zones/records/add", q)
+		_, response, err := api.New().GetJSON(cmd.Context(), "/api/zones/records/add", q)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
 			os.Exit(1)
 		}
 
-		if jsonOutput {
-			raw, _ := json.MarshalIndent(result["response"], "", "  ")
-			fmt.Println(string(raw))
-			return
+		render(response, func() {
+			fmt.Println("✅ Record added successfully.")
+		})
+	},
+}
+
+var recordsUpdateCmd = &cobra.Command{
+	Use:     "update",
+	Aliases: []string{"u", "up"},
+	Short:   "Update an existing record in place",
+	Long: `Update an existing record in place.
+
+The record to change is selected by its current values, passed with the same
+flags as add. The new values go in the matching --new* flags; any not given keep
+their current value. Types holding one record per name (CNAME, DNAME, APP, SOA)
+take the new values in the plain flags instead.
+
+` + recordTypesHint + `
+
+  tdns records update -z example.com -n www.example.com -r A --ipAddress 192.0.2.1 --newIpAddress 192.0.2.2
+  tdns records update -z example.com -r MX --preference 10 --exchange mx1.example.com --newPreference 20
+  tdns records update -z example.com -n www.example.com -r CNAME --cname web.example.net --ttl 300`,
+	Run: func(cmd *cobra.Command, args []string) {
+		q, err := recordQuery(cmd, recordUpdate)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		if newDomainName != "" {
+			q.Set("newDomain", newDomainName)
 		}
+		if cmd.Flags().Changed("disable") {
+			q.Set("disable", strconv.FormatBool(recordDisable))
+		}
+
+		_, response, err := api.New().GetJSON(cmd.Context(), "/api/zones/records/update", q)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		render(response, func() {
+			fmt.Println("✅ Record updated successfully.")
+		})
 	},
 }
 
@@ -130,51 +221,103 @@ var recordsDeleteCmd = &cobra.Command{
 	Use:     "delete",
 	Aliases: []string{"d", "del"},
 	Short:   "Delete a record from a zone",
+	Long: `Delete a record from a zone.
+
+The record is selected by its values, passed with the same flags as add.
+
+` + recordTypesHint,
 	Run: func(cmd *cobra.Command, args []string) {
-		if zoneName == "" || recordType == "" {
-			fmt.Fprintln(os.Stderr, "❌ --zone and --type are required")
+		q, err := recordQuery(cmd, recordDelete)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
 			os.Exit(1)
 		}
 
 		if !assumeYes {
-			fmt.Printf("Are you sure you want to delete this record (%s)? (yes/no): ", domainName)
+			fmt.Fprintf(messages(), "Are you sure you want to delete this record (%s)? (yes/no): ", q.Get("domain"))
 			var confirm string
 			fmt.Scanln(&confirm)
 			if confirm != "yes" {
-				fmt.Println("❌ Aborted.")
+				fmt.Fprintln(messages(), "❌ Aborted.")
 				return
 			}
 		}
 
-		if _, _, err := api.New().GetJSON("/api/zones/records/delete", recordQuery()); err != nil {
+		_, response, err := api.New().GetJSON(cmd.Context(), "/api/zones/records/delete", q)
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Println("✅ Record deleted successfully.")
+		render(response, func() {
+			fmt.Println("✅ Record deleted successfully.")
+		})
+	},
+}
+
+var recordsTypesCmd = &cobra.Command{
+	Use:     "types [type]",
+	Aliases: []string{"ty"},
+	Short:   "List the supported record types and their parameters",
+	Args:    cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		only := ""
+		if len(args) == 1 {
+			spec, ok := lookupRecordType(args[0])
+			if !ok {
+				fmt.Fprintf(os.Stderr, "❌ unsupported record type %q (valid: %s)\n", args[0], strings.Join(recordTypeNames(), ", "))
+				os.Exit(1)
+			}
+			only = spec.name
+		}
+
+		type paramEntry struct {
+			Name     string `json:"name"`
+			Help     string `json:"help"`
+			Required bool   `json:"required"`
+		}
+		type typeEntry struct {
+			Type       string       `json:"type"`
+			Summary    string       `json:"summary"`
+			Parameters []paramEntry `json:"parameters"`
+		}
+		var types []typeEntry
+		for _, name := range recordTypeNames() {
+			if only != "" && name != only {
+				continue
+			}
+			spec, _ := lookupRecordType(name)
+			e := typeEntry{Type: spec.name, Summary: spec.summary, Parameters: []paramEntry{}}
+			for _, p := range spec.params {
+				e.Parameters = append(e.Parameters, paramEntry{Name: p.name, Help: p.help, Required: p.required})
+			}
+			types = append(types, e)
+		}
+		renderList(types, types, func() {
+			fmt.Print(formatRecordTypesHelp(only))
+		})
 	},
 }
 
 func init() {
-	recordsDeleteCmd.Flags().StringVarP(&zoneName, "zone", "z", "", "Zone name")
+	addRecordFlags(recordsDeleteCmd, false)
 	recordsDeleteCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Assume yes when asking for confirmation")
-	recordsDeleteCmd.Flags().StringVarP(&domainName, "domain", "n", "", "Domain name")
-	recordsDeleteCmd.Flags().StringVarP(&recordType, "type", "r", "", "Record type")
 	recordsDeleteCmd.Flags().IntVarP(&recordTTL, "ttl", "", -1, "Time to live")
-	recordsDeleteCmd.Flags().StringVar(&ipAddress, "ipAddress", "", "IP address for A/AAAA records")
-	recordsDeleteCmd.Flags().StringVar(&cnameValue, "cname", "", "CNAME target")
+	recordsDeleteCmd.Flags().MarkDeprecated("ttl", "it is ignored, as records are matched by their data")
 	recordsCmd.AddCommand(recordsDeleteCmd)
-	recordsAddCmd.Flags().StringVarP(&zoneName, "zone", "z", "", "Zone name")
-	recordsAddCmd.Flags().BoolVarP(&overwrite, "overwrite", "o", false, "Overwrite existing record if present")
-	recordsAddCmd.Flags().StringVarP(&domainName, "domain", "n", "", "Domain name")
-	recordsAddCmd.Flags().StringVarP(&recordType, "type", "r", "", "Record type")
+	addRecordFlags(recordsAddCmd, false)
+	recordsAddCmd.Flags().BoolVar(&overwrite, "overwrite", false, "Overwrite existing record if present")
 	recordsAddCmd.Flags().IntVarP(&recordTTL, "ttl", "", -1, "Time to live")
-	recordsAddCmd.Flags().StringVar(&ipAddress, "ipAddress", "", "IP address for A/AAAA records")
-	recordsAddCmd.Flags().StringVar(&cnameValue, "cname", "", "CNAME target")
-	recordsAddCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output raw JSON of response")
+	recordsAddCmd.Flags().StringVar(&recordComments, "comments", "", "Comments to store with the record")
 	recordsCmd.AddCommand(recordsAddCmd)
+	addRecordFlags(recordsUpdateCmd, true)
+	recordsUpdateCmd.Flags().StringVar(&newDomainName, "newDomain", "", "Rename the record to this domain")
+	recordsUpdateCmd.Flags().IntVarP(&recordTTL, "ttl", "", -1, "New time to live")
+	recordsUpdateCmd.Flags().StringVar(&recordComments, "comments", "", "New comments for the record")
+	recordsUpdateCmd.Flags().BoolVar(&recordDisable, "disable", false, "Disable (true) or enable (false) the record")
+	recordsCmd.AddCommand(recordsUpdateCmd)
+	recordsCmd.AddCommand(recordsTypesCmd)
 	recordsGetCmd.Flags().StringVarP(&recordType, "filter", "f", "", "Filter by record type (e.g. A, MX, TXT)")
-	recordsGetCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output raw JSON instead of formatted text")
 	recordsCmd.AddCommand(recordsGetCmd)
 	rootCmd.AddCommand(recordsCmd)
 }
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+func TestBuildRecordParamsAdd(t *testing.T) {
+	spec, _ := lookupRecordType("mx")
+	q, err := buildRecordParams(spec, map[string]string{"preference": "10", "exchange": "mx1.example.com"}, recordAdd)
+	if err != nil {
+		t.Fatalf("buildRecordParams: %v", err)
+	}
+	if q.Get("preference") != "10" || q.Get("exchange") != "mx1.example.com" {
+		t.Errorf("query = %v", q)
+	}
+
+	if _, err := buildRecordParams(spec, map[string]string{"preference": "10"}, recordAdd); err == nil || !strings.Contains(err.Error(), "--exchange") {
+		t.Errorf("missing exchange should name the flag, got %v", err)
+	}
+	if _, err := buildRecordParams(spec, map[string]string{"preference": "70000", "exchange": "mx"}, recordAdd); err == nil {
+		t.Error("preference out of uint16 range should be rejected")
+	}
+	if _, err := buildRecordParams(spec, map[string]string{"preference": "10", "exchange": "mx", "ipAddress": "192.0.2.1"}, recordAdd); err == nil || !strings.Contains(err.Error(), "does not apply to MX") {
+		t.Errorf("a parameter of another type should be rejected, got %v", err)
+	}
+}
+
+func TestBuildRecordParamsValidation(t *testing.T) {
+	for _, tt := range []struct {
+		typ  string
+		vals map[string]string
+		ok   bool
+	}{
+		{"A", map[string]string{"ipAddress": "192.0.2.1"}, true},
+		{"A", map[string]string{"ipAddress": "2001:db8::1"}, false},
+		{"AAAA", map[string]string{"ipAddress": "2001:db8::1"}, true},
+		{"AAAA", map[string]string{"ipAddress": "192.0.2.1"}, false},
+		{"A", map[string]string{"ipAddress": "192.0.2.1", "ptr": "true"}, true},
+		{"A", map[string]string{"ipAddress": "192.0.2.1", "ptr": "maybe"}, false},
+		{"TXT", map[string]string{"text": "v=spf1 -all"}, true},
+		{"SRV", map[string]string{"priority": "10", "weight": "5", "port": "5060", "target": "sip.example.com"}, true},
+		{"SRV", map[string]string{"priority": "10", "weight": "5", "target": "sip.example.com"}, false},
+		{"CAA", map[string]string{"flags": "0", "tag": "issue", "value": "letsencrypt.org"}, true},
+		{"CAA", map[string]string{"flags": "256", "tag": "issue", "value": "letsencrypt.org"}, false},
+		{"DS", map[string]string{"keyTag": "2371", "algorithm": "RSASHA256", "digestType": "SHA256", "digest": "AB12 CD34"}, true},
+		{"DS", map[string]string{"keyTag": "2371", "algorithm": "8", "digestType": "2", "digest": "ab12"}, true},
+		{"DS", map[string]string{"keyTag": "2371", "algorithm": "NOPE", "digestType": "2", "digest": "ab12"}, false},
+		{"DS", map[string]string{"keyTag": "2371", "algorithm": "8", "digestType": "2", "digest": "xyz"}, false},
+		{"TLSA", map[string]string{"tlsaCertificateUsage": "dane-ee", "tlsaSelector": "SPKI", "tlsaMatchingType": "SHA2-256", "tlsaCertificateAssociationData": "ab"}, true},
+		{"HTTPS", map[string]string{"svcPriority": "1", "svcTargetName": ".", "svcParams": "alpn|h2,h3|port|443"}, true},
+		{"HTTPS", map[string]string{"svcPriority": "1", "svcTargetName": ".", "svcParams": "alpn"}, false},
+		{"CNAME", map[string]string{"cname": "bad name.example.com"}, false},
+		{"CNAME", map[string]string{"cname": strings.Repeat("a", 64) + ".example.com"}, false},
+		{"SOA", map[string]string{"serial": "1"}, false}, // update only
+	} {
+		spec, ok := lookupRecordType(tt.typ)
+		if !ok {
+			t.Fatalf("unknown type %s", tt.typ)
+		}
+		_, err := buildRecordParams(spec, tt.vals, recordAdd)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s %v: err = %v, want ok=%v", tt.typ, tt.vals, err, tt.ok)
+		}
+	}
+}
+
+func TestBuildRecordParamsUpdate(t *testing.T) {
+	spec, _ := lookupRecordType("A")
+	q, err := buildRecordParams(spec, map[string]string{"ipAddress": "192.0.2.1", "newIpAddress": "192.0.2.2"}, recordUpdate)
+	if err != nil {
+		t.Fatalf("buildRecordParams: %v", err)
+	}
+	if q.Get("ipAddress") != "192.0.2.1" || q.Get("newIpAddress") != "192.0.2.2" {
+		t.Errorf("query = %v", q)
+	}
+	if _, err := buildRecordParams(spec, map[string]string{"newIpAddress": "192.0.2.2"}, recordUpdate); err == nil {
+		t.Error("update must require the current value to select the record")
+	}
+	if _, err := buildRecordParams(spec, map[string]string{"ipAddress": "192.0.2.1", "newIpAddress": "nope"}, recordUpdate); err == nil {
+		t.Error("new values must be validated too")
+	}
+
+	// Singletons take the new value in the plain flag and need no old value.
+	spec, _ = lookupRecordType("CNAME")
+	if q, err := buildRecordParams(spec, map[string]string{"cname": "web.example.net"}, recordUpdate); err != nil || q.Get("cname") != "web.example.net" {
+		t.Errorf("CNAME update: q = %v, err = %v", q, err)
+	}
+	if _, err := buildRecordParams(spec, map[string]string{"newCname": "web.example.net"}, recordUpdate); err == nil {
+		t.Error("singletons have no new* parameters")
+	}
+
+	spec, _ = lookupRecordType("ANAME")
+	if q, err := buildRecordParams(spec, map[string]string{"aname": "a.example.net", "newAName": "b.example.net"}, recordUpdate); err != nil || q.Get("newAName") != "b.example.net" {
+		t.Errorf("ANAME update: q = %v, err = %v", q, err)
+	}
+
+	spec, _ = lookupRecordType("SOA")
+	if _, err := buildRecordParams(spec, map[string]string{"serial": "2026101701"}, recordUpdate); err != nil {
+		t.Errorf("SOA update: %v", err)
+	}
+}
+
+func TestBuildRecordParamsDeleteSkipsSettings(t *testing.T) {
+	spec, _ := lookupRecordType("A")
+	q, err := buildRecordParams(spec, map[string]string{"ipAddress": "192.0.2.1", "ptr": "true"}, recordDelete)
+	if err != nil {
+		t.Fatalf("buildRecordParams: %v", err)
+	}
+	if _, ok := q["ptr"]; ok {
+		t.Errorf("settings must not be sent on delete: %v", q)
+	}
+}
+
+// runRecordsCmd executes `tdns records <args>` against a stub server and
+// returns the path and query of the request it received.
+func runRecordsCmd(t *testing.T, args ...string) (string, map[string][]string, error) {
+	t.Helper()
+
+	var (
+		gotPath  string
+		gotQuery map[string][]string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotQuery = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"status":"ok","response":{}}`)
+	}))
+	defer srv.Close()
+
+	oldHost := viper.GetString("host")
+	viper.Set("host", srv.URL)
+	defer viper.Set("host", oldHost)
+
+	// Per-type flags keep their Changed state across Execute calls.
+	for _, c := range []*cobra.Command{recordsAddCmd, recordsUpdateCmd, recordsDeleteCmd} {
+		c.Flags().VisitAll(func(f *pflag.Flag) {
+			if _, ok := recordFlagNames[f.Name]; ok {
+				_ = f.Value.Set(f.DefValue)
+				f.Changed = false
+			}
+		})
+	}
+	zoneName, domainName, recordType, newDomainName, recordComments = "", "", "", "", ""
+	recordTTL = -1
+	assumeYes = false
+
+	oldStdout := os.Stdout
+	rp, wp, _ := os.Pipe()
+	os.Stdout = wp
+	defer func() { os.Stdout = oldStdout }()
+	go func() { _, _ = io.Copy(io.Discard, rp) }()
+
+	rootCmd.SetArgs(append([]string{"records"}, args...))
+	defer rootCmd.SetArgs(nil)
+	err := rootCmd.Execute()
+	wp.Close()
+	return gotPath, gotQuery, err
+}
+
+func TestRecordsUpdateCmd(t *testing.T) {
+	path, q, err := runRecordsCmd(t, "update", "-z", "example.com", "-n", "www.example.com", "-r", "a",
+		"--ipAddress", "192.0.2.1", "--newIpAddress", "192.0.2.2", "--ttl", "300")
+	if err != nil {
+		t.Fatalf("records update: %v", err)
+	}
+	if path != "/api/zones/records/update" {
+		t.Errorf("path = %q, want /api/zones/records/update", path)
+	}
+	for k, want := range map[string]string{
+		"zone":         "example.com",
+		"domain":       "www.example.com",
+		"type":         "A", // canonicalized
+		"ipAddress":    "192.0.2.1",
+		"newIpAddress": "192.0.2.2",
+		"ttl":          "300",
+	} {
+		if got := q[k]; len(got) != 1 || got[0] != want {
+			t.Errorf("query %s = %v, want %q", k, got, want)
+		}
+	}
+}
+
+func TestRecordsAddCmdDefaultsDomainToZone(t *testing.T) {
+	_, q, err := runRecordsCmd(t, "add", "-z", "example.com", "-r", "MX", "--preference", "10", "--exchange", "mx1.example.com")
+	if err != nil {
+		t.Fatalf("records add: %v", err)
+	}
+	if got := q["domain"]; len(got) != 1 || got[0] != "example.com" {
+		t.Errorf("domain = %v, want the zone apex", got)
+	}
+	if _, ok := q["ttl"]; ok {
+		t.Error("ttl must not be sent unless set")
+	}
+}
+
+func TestRecordsDeleteCmdIgnoresTTL(t *testing.T) {
+	path, q, err := runRecordsCmd(t, "delete", "-z", "example.com", "-n", "www.example.com", "-r", "A",
+		"--ipAddress", "192.0.2.1", "--ttl", "300", "--yes")
+	if err != nil {
+		t.Fatalf("records delete: %v", err)
+	}
+	if path != "/api/zones/records/delete" || q["ipAddress"][0] != "192.0.2.1" {
+		t.Errorf("request = %s %v", path, q)
+	}
+	if _, ok := q["ttl"]; ok {
+		t.Error("ttl must not be sent on delete")
+	}
+}
+
+func TestNewParamName(t *testing.T) {
+	for name, want := range map[string]string{
+		"ipAddress":        "newIpAddress",
+		"aname":            "newAName",
+		"naptrOrder":       "naptrNewOrder",
+		"naptrPreference":  "naptrNewPreference",
+		"naptrFlags":       "naptrNewFlags",
+		"naptrServices":    "naptrNewServices",
+		"naptrRegexp":      "naptrNewRegexp",
+		"naptrReplacement": "naptrNewReplacement",
+	} {
+		if got := newParamName(name); got != want {
+			t.Errorf("newParamName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -20,14 +20,19 @@ var resyncZoneCmd = &cobra.Command{
 		amber := color.New(color.FgYellow).SprintFunc()
 
 		client := api.New()
+		var done []zoneAction
 		for _, zone := range args {
-			_, _, err := client.GetJSON("/api/zones/resync", url.Values{"zone": {zone}})
+			_, _, err := client.GetJSON(cmd.Context(), "/api/zones/resync", url.Values{"zone": {zone}})
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "❌ Failed to resync zone %s: %v\n", amber(zone), err)
 				os.Exit(1)
 			}
-			fmt.Printf("✅ Zone %v resynced successfully.\n", bold(zone))
+			done = append(done, zoneAction{Zone: zone, Result: "resynced"})
+			if !structuredOutput() {
+				fmt.Printf("✅ Zone %v resynced successfully.\n", bold(zone))
+			}
 		}
+		renderList(done, done, func() {})
 	},
 }
 
@@ -2,9 +2,13 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -18,10 +22,8 @@ var (
 	Version string
 )
 
-var (
-	debugFlag bool
-	ctx       context.Context
-)
+var debugFlag bool
+
 var defaultHost = "http://localhost:5380"
 var defaultToken = ""
 
@@ -32,6 +34,20 @@ var rootCmd = &cobra.Command{
 	SilenceErrors: true,
 	Short:         fmt.Sprintf("%s is a CLI tool for managing DNS zones", Name),
 	Long:          fmt.Sprintf("%s is a CLI tool to manage Technitium DNS server via API endpoint", Name),
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if profileErr != nil && !managesProfiles(cmd) {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", profileErr)
+			os.Exit(1)
+		}
+		if err := setOutput(cmd); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		if viper.GetBool("insecure_skip_verify") {
+			fmt.Fprintln(os.Stderr, yellow("⚠️  WARNING: TLS certificate verification is DISABLED (insecure_skip_verify)."))
+			fmt.Fprintln(os.Stderr, yellow("⚠️  Anyone on the network path can read and alter API traffic, including the token."))
+		}
+	},
 	//Run: func(cmd *cobra.Command, args []string) {
 	//	_ = cmd.Help()
 	//},
@@ -42,29 +58,43 @@ func Execute(version string) {
 
 	rootCmd.Version = version
 
-	cobra.CheckErr(rootCmd.Execute())
-
-	if debugFlag {
-		slog.SetLogLoggerLevel(slog.LevelDebug)
-	}
-	slog.Debug(fmt.Sprintf("App version: %s", Version))
+	cobra.CheckErr(rootCmd.ExecuteContext(signalContext()))
 }
 
 func init() {
 	cobra.OnInitialize(initConfig)
 
-	// start with empty Context
-	ctx = context.Background()
-
 	rootCmd.PersistentFlags().BoolVarP(&debugFlag, "debug", "d", false, "Enable debugging logging")
 	rootCmd.PersistentFlags().StringP("token", "t", "", "API token (overrides config/TDNS_API_TOKEN env)")
 	rootCmd.PersistentFlags().StringP("endpoint", "e", "", "API endpoint (overrides config)")
 	rootCmd.PersistentFlags().BoolP("legacy-token", "L", false, "Also send token as a query parameter (for older servers/endpoints that don't honor Authorization: Bearer)")
 	rootCmd.PersistentFlags().DurationP("timeout", "T", api.DefaultTimeout, "API request timeout (e.g. 5s, 1m)")
+	rootCmd.PersistentFlags().Int("retries", api.DefaultRetries, "Times to retry a read-only API call that failed transiently (0 disables)")
+	rootCmd.PersistentFlags().Duration("retry-wait", api.DefaultRetryWait, "Delay before the first retry, doubled for each further one")
+	rootCmd.PersistentFlags().Bool("retry-writes", false, "Also retry API calls that change the server (a timed-out write may already have been applied)")
+	rootCmd.PersistentFlags().String("ca-file", "", "PEM bundle of CA certificates to trust for the API endpoint")
+	rootCmd.PersistentFlags().String("client-cert", "", "PEM client certificate for mutual TLS")
+	rootCmd.PersistentFlags().String("client-key", "", "PEM key of the --client-cert certificate")
+	rootCmd.PersistentFlags().Bool("insecure-skip-verify", false, "Don't verify the API endpoint's TLS certificate (INSECURE, for testing only)")
+	rootCmd.PersistentFlags().String("tls-min-version", "", "Lowest TLS version to accept: 1.0, 1.1, 1.2 or 1.3")
+	rootCmd.PersistentFlags().StringP("output", "o", outputText, "Output format: "+outputFormats)
+	rootCmd.PersistentFlags().Bool("json", false, "Output JSON")
+	rootCmd.PersistentFlags().MarkDeprecated("json", "use --output json instead")
+	rootCmd.PersistentFlags().StringP("profile", "P", "", "Config profile to use (overrides TDNS_PROFILE env and the config's current profile)")
 	viper.BindPFlag("token", rootCmd.PersistentFlags().Lookup("token"))
 	viper.BindPFlag("host", rootCmd.PersistentFlags().Lookup("endpoint"))
 	viper.BindPFlag("legacy_token", rootCmd.PersistentFlags().Lookup("legacy-token"))
 	viper.BindPFlag("timeout", rootCmd.PersistentFlags().Lookup("timeout"))
+	viper.BindPFlag("retries", rootCmd.PersistentFlags().Lookup("retries"))
+	viper.BindPFlag("retry_wait", rootCmd.PersistentFlags().Lookup("retry-wait"))
+	viper.BindPFlag("retry_writes", rootCmd.PersistentFlags().Lookup("retry-writes"))
+	viper.BindPFlag("tls_ca_file", rootCmd.PersistentFlags().Lookup("ca-file"))
+	viper.BindPFlag("tls_cert_file", rootCmd.PersistentFlags().Lookup("client-cert"))
+	viper.BindPFlag("tls_key_file", rootCmd.PersistentFlags().Lookup("client-key"))
+	viper.BindPFlag("insecure_skip_verify", rootCmd.PersistentFlags().Lookup("insecure-skip-verify"))
+	viper.BindPFlag("tls_min_version", rootCmd.PersistentFlags().Lookup("tls-min-version"))
+	viper.BindPFlag("output", rootCmd.PersistentFlags().Lookup("output"))
+	viper.BindPFlag("profile", rootCmd.PersistentFlags().Lookup("profile"))
 
 	viper.SetEnvPrefix("TDNS")
 	viper.AutomaticEnv()
@@ -80,7 +110,53 @@ func init() {
 }
 
 func initConfig() {
+	// Set up logging first so the config lookup itself can be debugged.
+	if debugFlag {
+		slog.SetLogLoggerLevel(slog.LevelDebug)
+	}
+	slog.Debug(fmt.Sprintf("App version: %s", Version))
+
 	if err := viper.ReadInConfig(); err != nil {
 		fmt.Fprintf(os.Stderr, "⚠️  Warning: config not found/loaded: %v\n", err)
 	}
+	profileErr = applyProfile(viper.GetViper())
+}
+
+// interruptGrace is how long a command gets to wind down after a signal
+// before the process exits anyway.
+const interruptGrace = 2 * time.Second
+
+// signalContext returns a context that is cancelled on Ctrl-C or SIGTERM,
+// which aborts any API request in flight so the command can report what it
+// got done. A command blocked on something else, such as a confirmation
+// prompt, is stopped after interruptGrace, and a second signal kills the
+// process straight away.
+func signalContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		cancel()
+		signal.Stop(sigs)
+		time.Sleep(interruptGrace)
+		fmt.Fprintln(os.Stderr, "\n❌ Interrupted.")
+		os.Exit(exitInterrupted)
+	}()
+	return ctx
+}
+
+// exitInterrupted is the exit status of a command stopped by SIGINT or
+// SIGTERM, following the shell convention of 128+SIGINT.
+const exitInterrupted = 130
+
+// exitIfInterrupted exits with exitInterrupted when err is the result of ctx
+// being cancelled, so scripts can tell an interrupted run from a failed one.
+// what describes the state the interruption left things in.
+func exitIfInterrupted(ctx context.Context, err error, what string) {
+	if err == nil || (ctx.Err() == nil && !errors.Is(err, context.Canceled)) {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\n❌ Interrupted: %s\n", what)
+	os.Exit(exitInterrupted)
 }
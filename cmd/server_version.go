@@ -14,12 +14,14 @@ var serverVersionCmd = &cobra.Command{
 	Aliases: []string{"sv"},
 	Short:   "Print the version of the connected DNS server",
 	Run: func(cmd *cobra.Command, args []string) {
-		v, err := api.New().ServerVersion()
+		v, err := api.New().ServerVersion(cmd.Context())
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
 			os.Exit(1)
 		}
-		fmt.Println(v)
+		render(map[string]string{"version": v}, func() {
+			fmt.Println(v)
+		})
 	},
 }
 
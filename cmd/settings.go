@@ -1,40 +1,42 @@
 package cmd
 
 import (
-	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"mime/multipart"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
 	"tdns/internal/api"
+	"tdns/internal/backup"
 )
 
-var getJSON bool
-
-var backupOutputPath string
-
-// fullBackupQuery is the query-string set used for both backup and restore.
-func fullBackupQuery(extra map[string]string) url.Values {
-	q := url.Values{
-		"blockLists":   {"true"},
-		"logs":         {"true"},
-		"scopes":       {"true"},
-		"stats":        {"true"},
-		"zones":        {"true"},
-		"allowedZones": {"true"},
-		"blockedZones": {"true"},
-		"dnsSettings":  {"true"},
-		"logSettings":  {"true"},
-		"authConfig":   {"true"},
+var (
+	backupFilePath    string
+	backupDir         string
+	backupRetention   backup.Retention
+	backupInclude     []string
+	backupExclude     []string
+	keepExistingFiles bool
+)
+
+// backupQuery is the query-string set used for both backup and restore: every
+// category flag, true for those in categories.
+func backupQuery(categories []string, extra map[string]string) url.Values {
+	q := url.Values{}
+	for _, c := range backup.Categories {
+		q.Set(c, "false")
+	}
+	for _, c := range categories {
+		q.Set(c, "true")
 	}
 	for k, v := range extra {
 		q.Set(k, v)
@@ -52,50 +54,183 @@ var settingsBackupCmd = &cobra.Command{
 	Use:     "backup",
 	Aliases: []string{"ba"},
 	Short:   "Download a backup zip file of selected server settings",
+	Long: `Download a backup zip file of the server's data and settings. Every category
+is included unless --include names the ones to back up; --exclude leaves
+categories out. The categories are: ` + strings.Join(backup.Categories, ", ") + `.
+
+The backup is saved as tdns-backup-<time>.zip in the current directory, or in
+the directory given with --dir, or as --file. Every backup gets a SHA-256
+checksum file next to it (.sha256) that 'settings restore' verifies. A
+timestamped backup never replaces an earlier one taken in the same second;
+it is numbered instead (tdns-backup-<time>-2.zip).
+
+With --dir older backups there are pruned by the --keep-* flags: the newest
+backup of each of the last N days, ISO weeks and months is kept. For a nightly
+cron job:
+
+  tdns settings backup --dir /var/backups/tdns --keep-daily 7 --keep-weekly 4 --keep-monthly 12
+
+The file is written under a temporary name and renamed when complete, so an
+interrupted backup never leaves a partial archive behind.`,
+	Args: cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		resp, err := api.New().Get("/api/settings/backup", fullBackupQuery(nil))
+		categories, err := backup.Select(backupInclude, backupExclude)
 		if err != nil {
-			fmt.Printf("Request failed: %v\n", err)
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		if len(categories) == 0 {
+			fmt.Fprintln(os.Stderr, "❌ no categories left to back up")
+			os.Exit(1)
+		}
+		if backupDir != "" && backupFilePath != "" {
+			fmt.Fprintln(os.Stderr, "❌ --dir and --file are mutually exclusive")
+			os.Exit(1)
+		}
+		if backupDir == "" && !backupRetention.IsZero() {
+			fmt.Fprintln(os.Stderr, "❌ --keep-daily, --keep-weekly and --keep-monthly need --dir")
+			os.Exit(1)
+		}
+
+		ctx := cmd.Context()
+		resp, err := api.New().Get(ctx, "/api/settings/backup", backupQuery(categories, nil))
+		if err != nil {
+			exitIfInterrupted(ctx, err, "no backup was saved.")
+			fmt.Fprintf(os.Stderr, "❌ Request failed: %v\n", err)
 			os.Exit(1)
 		}
 		defer resp.Body.Close()
 
 		if resp.StatusCode != http.StatusOK {
-			fmt.Printf("❌ Failed to download backup: HTTP %d\n", resp.StatusCode)
+			fmt.Fprintf(os.Stderr, "❌ Failed to download backup: HTTP %d\n", resp.StatusCode)
 			os.Exit(1)
 		}
 
-		outFile := backupOutputPath
-		if outFile == "" {
-			timestamp := time.Now().Format("20060102-150405")
-			outFile = fmt.Sprintf("tdns-backup-%s.zip", timestamp)
+		var outPath, sum string
+		if backupFilePath != "" {
+			outPath = filepath.Clean(backupFilePath)
+			sum, err = backup.WriteFile(outPath, resp.Body)
+		} else {
+			dir := backupDir
+			if dir == "" {
+				dir = "."
+			} else if err := os.MkdirAll(dir, 0o700); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Could not create directory: %v\n", err)
+				os.Exit(1)
+			}
+			outPath, sum, err = backup.WriteNew(dir, time.Now(), resp.Body)
 		}
-
-		outPath := filepath.Clean(outFile)
-		out, err := os.Create(outPath)
 		if err != nil {
-			fmt.Printf("❌ Could not create file: %v\n", err)
+			exitIfInterrupted(ctx, err, "no backup was saved.")
+			fmt.Fprintf(os.Stderr, "❌ Failed to write file: %v\n", err)
 			os.Exit(1)
 		}
-		defer out.Close()
+		if err := backup.WriteChecksum(outPath, sum); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Backup saved as %s, but failed to write its checksum: %v\n", outPath, err)
+			os.Exit(1)
+		}
+
+		result := map[string]interface{}{"file": outPath, "sha256": sum}
+		var pruned []string
+		if backupDir != "" {
+			if pruned, err = backup.Prune(backupDir, backupRetention); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ Backup saved as %s, but pruning old backups failed: %v\n", outPath, err)
+				os.Exit(1)
+			}
+			result["pruned"] = append([]string{}, pruned...)
+		}
 
-		_, err = io.Copy(out, resp.Body)
+		render(result, func() {
+			fmt.Printf("✅ Backup saved as %s\n", outPath)
+			for _, p := range pruned {
+				fmt.Printf("🗑  Pruned %s\n", p)
+			}
+		})
+	},
+}
+
+var settingsBackupInspectCmd = &cobra.Command{
+	Use:     "inspect [zip]",
+	Aliases: []string{"in"},
+	Short:   "List the contents of a backup zip file and check that it is a backup",
+	Long: `List the files of a backup zip file by category and check that it is a
+Technitium DNS Server backup, without contacting the server. The command exits
+with status 1 when it is not, which is what 'settings restore' checks too.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := args[0]
+		archive, err := backup.Inspect(path)
 		if err != nil {
-			fmt.Printf("❌ Failed to write file: %v\n", err)
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
 			os.Exit(1)
 		}
+		invalid := archive.Validate()
+		checksum := "ok"
+		switch err := backup.VerifyChecksum(path); {
+		case errors.Is(err, backup.ErrNoChecksum):
+			checksum = "none"
+		case err != nil:
+			checksum = "mismatch"
+			if invalid == nil {
+				invalid = err
+			}
+		}
 
-		fmt.Printf("✅ Backup saved as %s\n", outPath)
+		summaries := archive.Summaries()
+		result := map[string]interface{}{
+			"file":       path,
+			"valid":      invalid == nil,
+			"checksum":   checksum,
+			"categories": summaries,
+			"entries":    archive.Entries,
+		}
+		if invalid != nil {
+			result["error"] = invalid.Error()
+		}
+		renderList(result, archive.Entries, func() {
+			fmt.Printf("%s %s\n", bold("Backup:"), cyan(path))
+			for _, sum := range summaries {
+				fmt.Printf("%s %s\n", bold(sum.Category+":"), grey(fmt.Sprintf("%d files, %s", sum.Files, formatSize(sum.Size))))
+				for _, e := range archive.Entries {
+					if e.Category == sum.Category {
+						fmt.Printf("  - %s %s\n", e.Name, grey("("+formatSize(e.Size)+")"))
+					}
+				}
+			}
+			if checksum == "ok" {
+				fmt.Println("✅ Checksum matches.")
+			}
+			if invalid == nil {
+				fmt.Println("✅ Valid Technitium DNS Server backup.")
+			}
+		})
+		if checksum == "mismatch" {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", invalid)
+			os.Exit(1)
+		}
+		if invalid != nil {
+			fmt.Fprintf(os.Stderr, "❌ Not a Technitium DNS Server backup: %v\n", invalid)
+			os.Exit(1)
+		}
 	},
 }
 
 func init() {
-	settingsBackupCmd.Flags().StringVarP(&backupOutputPath, "output", "o", "", "Optional path to save the backup zip file")
+	settingsBackupCmd.Flags().StringVarP(&backupFilePath, "file", "f", "", "Path to save the backup zip file (default a timestamped name in the current directory)")
+	settingsBackupCmd.Flags().StringVar(&backupDir, "dir", "", "Save a timestamped backup with a checksum file in this directory, pruning old ones by the --keep-* flags")
+	settingsBackupCmd.Flags().IntVar(&backupRetention.Daily, "keep-daily", 0, "With --dir, keep the newest backup of each of the last N days")
+	settingsBackupCmd.Flags().IntVar(&backupRetention.Weekly, "keep-weekly", 0, "With --dir, keep the newest backup of each of the last N weeks")
+	settingsBackupCmd.Flags().IntVar(&backupRetention.Monthly, "keep-monthly", 0, "With --dir, keep the newest backup of each of the last N months")
 	settingsRestoreCmd.Flags().StringVarP(&restoreInputPath, "input", "i", "", "Path to backup zip file to restore")
+	for _, c := range []*cobra.Command{settingsBackupCmd, settingsRestoreCmd} {
+		c.Flags().StringSliceVar(&backupInclude, "include", nil, "Only these categories (comma-separated)")
+		c.Flags().StringSliceVar(&backupExclude, "exclude", nil, "Leave out these categories (comma-separated)")
+	}
+	settingsRestoreCmd.Flags().BoolVar(&keepExistingFiles, "keep-existing-files", false, "Keep the server's files that the backup doesn't replace, instead of deleting them first")
+	settingsBackupCmd.AddCommand(settingsBackupInspectCmd)
 	settingsCmd.AddCommand(settingsBackupCmd)
 	settingsCmd.AddCommand(settingsRestoreCmd)
 	settingsCmd.AddCommand(settingsGetCmd)
-	settingsGetCmd.Flags().BoolVar(&getJSON, "json", false, "Output raw JSON response")
 	rootCmd.AddCommand(settingsCmd)
 }
 
@@ -105,36 +240,65 @@ var settingsRestoreCmd = &cobra.Command{
 	Use:     "restore",
 	Aliases: []string{"re"},
 	Short:   "Restore server settings from a backup zip file",
+	Long: `Restore server data and settings from a backup zip file. The file is checked
+to be a Technitium DNS Server backup before it is uploaded, and checked
+against its .sha256 checksum file when there is one.
+
+Only the categories the backup holds are restored, unless --include names
+them; --exclude leaves categories out. The server deletes its existing files
+of each restored category first, unless --keep-existing-files is given.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if restoreInputPath == "" {
 			fmt.Fprintln(os.Stderr, "❌ --input is required")
 			os.Exit(1)
 		}
 
-		file, err := os.Open(restoreInputPath)
+		if err := backup.VerifyChecksum(restoreInputPath); err != nil && !errors.Is(err, backup.ErrNoChecksum) {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		archive, err := backup.Inspect(restoreInputPath)
+		if err == nil {
+			err = archive.Validate()
+		}
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "❌ Could not open file: %v\n", err)
+			fmt.Fprintf(os.Stderr, "❌ Not a Technitium DNS Server backup: %v\n", err)
 			os.Exit(1)
 		}
-		defer file.Close()
-
-		body := &bytes.Buffer{}
-		writer := multipart.NewWriter(body)
-		part, err := writer.CreateFormFile("file", filepath.Base(restoreInputPath))
+		categories, err := backup.Select(backupInclude, backupExclude)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "❌ Failed to create form file: %v\n", err)
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
 			os.Exit(1)
 		}
-		_, err = io.Copy(part, file)
+		var restore []string
+		for _, c := range categories {
+			switch {
+			case archive.Has(c):
+				restore = append(restore, c)
+			case len(backupInclude) > 0:
+				// Asked for by name: restore it anyway, the server may have
+				// its own idea of an empty category.
+				fmt.Fprintf(os.Stderr, "⚠️  The backup has no %s files.\n", c)
+				restore = append(restore, c)
+			}
+		}
+		if len(restore) == 0 {
+			fmt.Fprintln(os.Stderr, "❌ no categories left to restore")
+			os.Exit(1)
+		}
+		fmt.Fprintf(messages(), "Restoring %s from %s\n", strings.Join(restore, ", "), restoreInputPath)
+
+		file, err := os.Open(restoreInputPath)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "❌ Failed to copy file data: %v\n", err)
+			fmt.Fprintf(os.Stderr, "❌ Could not open file: %v\n", err)
 			os.Exit(1)
 		}
-		writer.Close()
+		defer file.Close()
 
-		q := fullBackupQuery(map[string]string{"deleteExistingFiles": "true"})
-		resp, err := api.New().Post("/api/settings/restore", q, body, writer.FormDataContentType())
+		q := backupQuery(restore, map[string]string{"deleteExistingFiles": strconv.FormatBool(!keepExistingFiles)})
+		resp, err := api.New().PostFile(cmd.Context(), "/api/settings/restore", q, filepath.Base(restoreInputPath), file)
 		if err != nil {
+			exitIfInterrupted(cmd.Context(), err, "the server may have restored some or all of the backup.")
 			fmt.Fprintf(os.Stderr, "❌ Request failed: %v\n", err)
 			os.Exit(1)
 		}
@@ -151,12 +315,6 @@ var settingsRestoreCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		if getJSON {
-			raw, _ := json.MarshalIndent(result, "", "  ")
-			fmt.Println(string(raw))
-			return
-		}
-
 		status, ok := result["status"].(string)
 		if !ok || status != "ok" {
 			if msg, ok := result["errorMessage"].(string); ok {
@@ -167,40 +325,41 @@ var settingsRestoreCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		response := result["response"].(map[string]interface{})
-
-		bold := color.New(color.Bold).SprintFunc()
-		cyan := color.New(color.FgCyan).SprintFunc()
-		green := color.New(color.FgGreen).SprintFunc()
-
-		fmt.Println(bold("DNS Server Info:"))
-		fmt.Printf("  Domain: %s\n", cyan(response["dnsServerDomain"]))
-		fmt.Printf("  Version: %s\n", green(response["version"]))
-		fmt.Printf("  Started: %s\n", response["uptimestamp"])
-		fmt.Println()
-
-		fmt.Println(bold("Network:"))
-		fmt.Printf("  Endpoints: %v\n", response["dnsServerLocalEndPoints"])
-		fmt.Printf("  IPv4 Sources: %v\n", response["dnsServerIPv4SourceAddresses"])
-		fmt.Printf("  IPv6 Sources: %v\n", response["dnsServerIPv6SourceAddresses"])
-		fmt.Println()
-
-		fmt.Println(bold("Cache & Resolver:"))
-		fmt.Printf("  Save Cache: %v\n", response["saveCache"])
-		fmt.Printf("  Serve Stale: %v\n", response["serveStale"])
-		fmt.Printf("  Prefetch Trigger: %v\n", response["cachePrefetchTrigger"])
-		fmt.Println()
-
-		fmt.Println(bold("Blocking:"))
-		fmt.Printf("  Enabled: %v\n", response["enableBlocking"])
-		fmt.Printf("  Custom Addresses: %v\n", response["customBlockingAddresses"])
-		fmt.Println()
-
-		fmt.Println(bold("Web Service:"))
-		fmt.Printf("  HTTP Port: %v\n", response["webServiceHttpPort"])
-		fmt.Printf("  TLS Port: %v\n", response["webServiceTlsPort"])
-		fmt.Printf("  Enable TLS: %v\n", response["webServiceEnableTls"])
-
+		response, _ := result["response"].(map[string]interface{})
+
+		render(response, func() {
+			bold := color.New(color.Bold).SprintFunc()
+			cyan := color.New(color.FgCyan).SprintFunc()
+			green := color.New(color.FgGreen).SprintFunc()
+
+			fmt.Println(bold("DNS Server Info:"))
+			fmt.Printf("  Domain: %s\n", cyan(response["dnsServerDomain"]))
+			fmt.Printf("  Version: %s\n", green(response["version"]))
+			fmt.Printf("  Started: %s\n", response["uptimestamp"])
+			fmt.Println()
+
+			fmt.Println(bold("Network:"))
+			fmt.Printf("  Endpoints: %v\n", response["dnsServerLocalEndPoints"])
+			fmt.Printf("  IPv4 Sources: %v\n", response["dnsServerIPv4SourceAddresses"])
+			fmt.Printf("  IPv6 Sources: %v\n", response["dnsServerIPv6SourceAddresses"])
+			fmt.Println()
+
+			fmt.Println(bold("Cache & Resolver:"))
+			fmt.Printf("  Save Cache: %v\n", response["saveCache"])
+			fmt.Printf("  Serve Stale: %v\n", response["serveStale"])
+			fmt.Printf("  Prefetch Trigger: %v\n", response["cachePrefetchTrigger"])
+			fmt.Println()
+
+			fmt.Println(bold("Blocking:"))
+			fmt.Printf("  Enabled: %v\n", response["enableBlocking"])
+			fmt.Printf("  Custom Addresses: %v\n", response["customBlockingAddresses"])
+			fmt.Println()
+
+			fmt.Println(bold("Web Service:"))
+			fmt.Printf("  HTTP Port: %v\n", response["webServiceHttpPort"])
+			fmt.Printf("  TLS Port: %v\n", response["webServiceTlsPort"])
+			fmt.Printf("  Enable TLS: %v\n", response["webServiceEnableTls"])
+		})
 	},
 }
 
@@ -209,74 +368,68 @@ var settingsGetCmd = &cobra.Command{
 	Aliases: []string{"ge"},
 	Short:   "Retrieve current server settings",
 	Run: func(cmd *cobra.Command, args []string) {
-		result, response, err := api.New().GetJSON("/api/settings/get", nil)
+		settings, err := api.New().GetSettings(cmd.Context())
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
 			os.Exit(1)
 		}
 
-		if getJSON {
-			raw, _ := json.MarshalIndent(result, "", "  ")
-			fmt.Println(string(raw))
-			return
-		}
-
-		bold := color.New(color.Bold).SprintFunc()
-		cyan := color.New(color.FgCyan).SprintFunc()
-		green := color.New(color.FgGreen).SprintFunc()
-		yellow := color.New(color.FgYellow).SprintFunc()
-
-		fmt.Println(bold("General Settings:"))
-		fmt.Printf("  Version: %s\n", green(response["version"]))
-		fmt.Printf("  Start Time: %s\n", response["uptimestamp"])
-		fmt.Printf("  Domain: %s\n", cyan(response["dnsServerDomain"]))
-		fmt.Println()
-
-		fmt.Println(bold("DNS Endpoints:"))
-		fmt.Printf("  Local: %v\n", response["dnsServerLocalEndPoints"])
-		fmt.Printf("  IPv4: %v\n", response["dnsServerIPv4SourceAddresses"])
-		fmt.Printf("  IPv6: %v\n", response["dnsServerIPv6SourceAddresses"])
-		fmt.Println()
-
-		fmt.Println(bold("Blocking:"))
-		fmt.Printf("  Enabled: %v\n", green(response["enableBlocking"]))
-		fmt.Printf("  Type: %s\n", response["blockingType"])
-		fmt.Printf("  TTL: %v\n", response["blockingAnswerTtl"])
-		fmt.Printf("  Custom Addresses: %v\n", response["customBlockingAddresses"])
-		fmt.Println()
-
-		fmt.Println(bold("DNSSEC & Cache:"))
-		fmt.Printf("  DNSSEC: %v\n", response["dnssecValidation"])
-		fmt.Printf("  Save Cache: %v\n", response["saveCache"])
-		fmt.Printf("  Serve Stale: %v\n", response["serveStale"])
-		fmt.Printf("  Max Entries: %v\n", response["cacheMaximumEntries"])
-		fmt.Printf("  Failure TTL: %v\n", response["cacheFailureRecordTtl"])
-		fmt.Println()
-
-		fmt.Println(bold("Forwarders:"))
-		fmt.Printf("  Enabled: %v\n", response["concurrentForwarding"])
-		fmt.Printf("  Protocol: %v\n", response["forwarderProtocol"])
-		fmt.Printf("  Timeout: %vms\n", response["forwarderTimeout"])
-		fmt.Println()
-
-		fmt.Println(bold("Web Service:"))
-		fmt.Printf("  HTTP Port: %v\n", response["webServiceHttpPort"])
-		fmt.Printf("  TLS Port: %v\n", response["webServiceTlsPort"])
-		fmt.Printf("  TLS Enabled: %v\n", response["webServiceEnableTls"])
-		fmt.Println()
-
-		fmt.Println(bold("Stats & Logging:"))
-		fmt.Printf("  Enable Logging: %v\n", response["enableLogging"])
-		fmt.Printf("  Log Folder: %v\n", response["logFolder"])
-		fmt.Printf("  In-Memory Stats: %v\n", response["enableInMemoryStats"])
-		fmt.Printf("  Max Log Days: %v\n", response["maxLogFileDays"])
-		fmt.Println()
-
-		fmt.Println(bold("TSIG Keys:"))
-		tsigKeys := response["tsigKeys"].([]interface{})
-		for _, entry := range tsigKeys {
-			key := entry.(map[string]interface{})
-			fmt.Printf("  - %s (%s)\n", cyan(key["keyName"]), yellow(key["algorithmName"]))
-		}
+		render(settings.Raw, func() {
+			bold := color.New(color.Bold).SprintFunc()
+			cyan := color.New(color.FgCyan).SprintFunc()
+			green := color.New(color.FgGreen).SprintFunc()
+			yellow := color.New(color.FgYellow).SprintFunc()
+
+			fmt.Println(bold("General Settings:"))
+			fmt.Printf("  Version: %s\n", green(settings.Version))
+			fmt.Printf("  Start Time: %s\n", settings.Uptimestamp)
+			fmt.Printf("  Domain: %s\n", cyan(settings.DNSServerDomain))
+			fmt.Println()
+
+			fmt.Println(bold("DNS Endpoints:"))
+			fmt.Printf("  Local: %v\n", settings.DNSServerLocalEndPoints)
+			fmt.Printf("  IPv4: %v\n", settings.DNSServerIPv4SourceAddresses)
+			fmt.Printf("  IPv6: %v\n", settings.DNSServerIPv6SourceAddresses)
+			fmt.Println()
+
+			fmt.Println(bold("Blocking:"))
+			fmt.Printf("  Enabled: %v\n", green(settings.EnableBlocking))
+			fmt.Printf("  Type: %s\n", settings.BlockingType)
+			fmt.Printf("  TTL: %v\n", settings.BlockingAnswerTTL)
+			fmt.Printf("  Custom Addresses: %v\n", settings.CustomBlockingAddresses)
+			fmt.Println()
+
+			fmt.Println(bold("DNSSEC & Cache:"))
+			fmt.Printf("  DNSSEC: %v\n", settings.DNSSECValidation)
+			fmt.Printf("  Save Cache: %v\n", settings.SaveCache)
+			fmt.Printf("  Serve Stale: %v\n", settings.ServeStale)
+			fmt.Printf("  Max Entries: %v\n", settings.CacheMaximumEntries)
+			fmt.Printf("  Failure TTL: %v\n", settings.CacheFailureRecordTTL)
+			fmt.Println()
+
+			fmt.Println(bold("Forwarders:"))
+			fmt.Printf("  Enabled: %v\n", settings.ConcurrentForwarding)
+			fmt.Printf("  Protocol: %v\n", settings.ForwarderProtocol)
+			fmt.Printf("  Timeout: %vms\n", settings.ForwarderTimeout)
+			fmt.Println()
+
+			fmt.Println(bold("Web Service:"))
+			fmt.Printf("  HTTP Port: %v\n", settings.WebServiceHTTPPort)
+			fmt.Printf("  TLS Port: %v\n", settings.WebServiceTLSPort)
+			fmt.Printf("  TLS Enabled: %v\n", settings.WebServiceEnableTLS)
+			fmt.Println()
+
+			fmt.Println(bold("Stats & Logging:"))
+			fmt.Printf("  Enable Logging: %v\n", settings.EnableLogging)
+			fmt.Printf("  Log Folder: %v\n", settings.LogFolder)
+			fmt.Printf("  In-Memory Stats: %v\n", settings.EnableInMemoryStats)
+			fmt.Printf("  Max Log Days: %v\n", settings.MaxLogFileDays)
+			fmt.Println()
+
+			fmt.Println(bold("TSIG Keys:"))
+			for _, key := range settings.TSIGKeys {
+				fmt.Printf("  - %s (%s)\n", cyan(key.KeyName), yellow(key.AlgorithmName))
+			}
+		})
 	},
 }
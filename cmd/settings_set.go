@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"tdns/internal/api"
+)
+
+var (
+	settingsDataFile string
+	settingsStdin    bool
+	settingsDiffFile string
+	settingsExitCode bool
+)
+
+// volatileSettings change on their own and can't be set.
+var volatileSettings = map[string]bool{"version": true, "uptimestamp": true}
+
+// skippedSetting returns why set and diff leave the setting key of a JSON
+// file out, or "" when they don't. Both skip the same settings, so a full
+// 'settings get' dump that diff compares can be applied with set.
+func skippedSetting(key string, v interface{}) string {
+	if volatileSettings[key] {
+		return "changes on its own"
+	}
+	objects := false
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		objects = true
+	case []interface{}:
+		for _, e := range vv {
+			switch e.(type) {
+			case map[string]interface{}, []interface{}:
+				objects = true
+			}
+		}
+	}
+	switch {
+	case objects && key == "tsigKeys":
+		return "holds objects; use 'tdns tsig' for TSIG keys"
+	case objects:
+		return "holds objects, which can't be set this way"
+	}
+	return ""
+}
+
+// warnSkippedSettings prints a warning for each setting of a JSON file that
+// skippedSetting leaves out.
+func warnSkippedSettings(settings map[string]interface{}) {
+	keys := make([]string, 0, len(settings))
+	for k := range settings {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if reason := skippedSetting(k, settings[k]); reason != "" {
+			fmt.Fprintf(os.Stderr, "⚠️  Skipping %s: %s.\n", k, reason)
+		}
+	}
+}
+
+// settingValue formats a JSON setting value as an /api/settings/set
+// parameter. skip is set for null values, which are not sent. Empty lists
+// become "false", which is how the API clears a list.
+func settingValue(v interface{}) (value string, skip bool) {
+	switch vv := v.(type) {
+	case nil:
+		return "", true
+	case []interface{}:
+		if len(vv) == 0 {
+			return "false", false
+		}
+	}
+	return queryValue(v), false
+}
+
+// settingsQuery builds the parameters of /api/settings/set from the JSON
+// input and key=value arguments, which take precedence. Settings of the JSON
+// that skippedSetting leaves out are not sent.
+func settingsQuery(base map[string]interface{}, args []string) (url.Values, error) {
+	q := url.Values{}
+	for k, v := range base {
+		if skippedSetting(k, v) != "" {
+			continue
+		}
+		if value, skip := settingValue(v); !skip {
+			q.Set(k, value)
+		}
+	}
+	for _, arg := range args {
+		k, v, ok := strings.Cut(arg, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid argument %q, want key=value", arg)
+		}
+		q.Set(k, v)
+	}
+	return q, nil
+}
+
+// settingDiff is a setting whose server value differs from the desired one.
+// Server is nil for settings the server doesn't have.
+type settingDiff struct {
+	Setting string      `json:"setting"`
+	Server  interface{} `json:"server"`
+	Desired interface{} `json:"desired"`
+	Unknown bool        `json:"unknown,omitempty"`
+}
+
+// diffSettings compares the desired settings with the server's. Settings
+// the desired ones don't mention, and those skippedSetting leaves out, are
+// not compared. The result is sorted by name.
+func diffSettings(server, desired map[string]interface{}) []settingDiff {
+	var diffs []settingDiff
+	for k, want := range desired {
+		if skippedSetting(k, want) != "" {
+			continue
+		}
+		have, ok := server[k]
+		if !ok {
+			diffs = append(diffs, settingDiff{Setting: k, Desired: want, Unknown: true})
+			continue
+		}
+		// Both sides are compared as JSON, with numbers in the same form, so
+		// that 1.0 in the file matches the server's 1.
+		want = plainNumbers(want)
+		if compactJSON(have) != compactJSON(want) {
+			diffs = append(diffs, settingDiff{Setting: k, Server: have, Desired: want})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Setting < diffs[j].Setting })
+	return diffs
+}
+
+// formatSettingsDiff formats diffs of the server settings against file.
+func formatSettingsDiff(file string, diffs []settingDiff) string {
+	amber := color.New(color.FgYellow).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+
+	var sb strings.Builder
+	if len(diffs) == 0 {
+		fmt.Fprintf(&sb, "✅ Server settings match %s.\n", file)
+		return sb.String()
+	}
+	fmt.Fprintf(&sb, "%s %s\n\n", bold("Settings that differ from:"), cyan(file))
+	for _, d := range diffs {
+		if d.Unknown {
+			fmt.Fprintf(&sb, "  %s %s: not a server setting\n", red("?"), d.Setting)
+			continue
+		}
+		fmt.Fprintf(&sb, "  %s %s: %s -> %s\n", amber("~"), d.Setting, grey(compactJSON(d.Server)), compactJSON(d.Desired))
+	}
+	fmt.Fprintf(&sb, "\n%d setting(s) differ.\n", len(diffs))
+	return sb.String()
+}
+
+var settingsSetCmd = &cobra.Command{
+	Use:   "set [key=value]...",
+	Short: "Change server settings",
+	Long: `Change DNS server settings with /api/settings/set. Settings are named as in
+'tdns settings get --output json' and given as key=value arguments, or as a
+JSON object with --data-file or --stdin; arguments override the JSON. Lists
+are comma-separated, and an empty JSON list clears a list setting. Settings
+not given are left unchanged.
+
+  tdns settings set forwarders=1.1.1.1,9.9.9.9 forwarderProtocol=Https
+  tdns settings set --data-file desired.json
+
+Settings holding objects, such as tsigKeys, can't be set this way, and
+settings such as version change on their own; both are skipped with a
+warning, so a full 'settings get' dump can be applied. Use 'tdns tsig' for
+TSIG keys. Run 'tdns settings diff' first to see what a file would change.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		base, err := readJSONInput(settingsDataFile, settingsStdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		q, err := settingsQuery(base, args)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		warnSkippedSettings(base)
+		if len(q) == 0 {
+			fmt.Fprintln(os.Stderr, "❌ no settings provided — use key=value arguments and/or --data-file/--stdin")
+			os.Exit(1)
+		}
+
+		// A full settings document is too long for a URL, so it is posted.
+		_, response, err := api.New().PostFormJSON(cmd.Context(), "/api/settings/set", q)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		keys := make([]string, 0, len(q))
+		for k := range q {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		render(response, func() {
+			fmt.Printf("✅ Settings updated: %s\n", strings.Join(keys, ", "))
+		})
+	},
+}
+
+var settingsDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show which server settings differ from a JSON file",
+	Long: `Compare the server settings with a JSON object of desired settings, such as
+a checked-in copy of 'tdns settings get --output json', and list each setting
+whose value differs. Settings the file doesn't mention are not compared, nor
+are those 'tdns settings set' skips, such as tsigKeys and version; nothing is
+changed on the server. With --exit-code the command exits with
+status 1 when there are differences.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		file, stdin := settingsDiffFile, false
+		if file == "-" {
+			file, stdin = "", true
+		}
+		desired, err := readJSONInput(file, stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		settings, err := api.New().GetSettings(cmd.Context())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		warnSkippedSettings(desired)
+		diffs := diffSettings(settings.Raw, desired)
+		renderList(diffs, diffs, func() {
+			fmt.Print(formatSettingsDiff(settingsDiffFile, diffs))
+		})
+		if settingsExitCode && len(diffs) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	settingsSetCmd.Flags().StringVarP(&settingsDataFile, "data-file", "f", "", "Path to a JSON object of settings")
+	settingsSetCmd.Flags().BoolVar(&settingsStdin, "stdin", false, "Read a JSON object of settings from stdin")
+
+	settingsDiffCmd.Flags().StringVarP(&settingsDiffFile, "file", "f", "", "JSON file of desired settings (- for stdin)")
+	settingsDiffCmd.Flags().BoolVar(&settingsExitCode, "exit-code", false, "Exit with status 1 when settings differ")
+	_ = settingsDiffCmd.MarkFlagRequired("file")
+
+	settingsCmd.AddCommand(settingsSetCmd)
+	settingsCmd.AddCommand(settingsDiffCmd)
+}
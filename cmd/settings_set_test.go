@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestSettingsQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	data := `{"forwarders":["1.1.1.1","9.9.9.9"],"enableBlocking":false,"cacheMaximumEntries":10000000,
+		"blockListUrls":[],"proxy":null,"forwarderProtocol":"Udp"}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	base, err := readJSONInput(path, false)
+	if err != nil {
+		t.Fatalf("readJSONInput: %v", err)
+	}
+	q, err := settingsQuery(base, []string{"forwarderProtocol=Https", "dnsServerDomain=dns.example"})
+	if err != nil {
+		t.Fatalf("settingsQuery: %v", err)
+	}
+	want := map[string]string{
+		"forwarders":          "1.1.1.1,9.9.9.9",
+		"enableBlocking":      "false",
+		"cacheMaximumEntries": "10000000",
+		"blockListUrls":       "false",
+		"forwarderProtocol":   "Https",
+		"dnsServerDomain":     "dns.example",
+	}
+	if len(q) != len(want) {
+		t.Errorf("query = %v", q)
+	}
+	for k, v := range want {
+		if q.Get(k) != v {
+			t.Errorf("%s = %q, want %q", k, q.Get(k), v)
+		}
+	}
+
+	if _, err := settingsQuery(nil, []string{"novalue"}); err == nil {
+		t.Error("argument without = accepted")
+	}
+
+	// What set skips, diff skips too, so a full dump can be applied.
+	dump := map[string]interface{}{
+		"version":    "13.6",
+		"tsigKeys":   []interface{}{map[string]interface{}{"keyName": "k"}},
+		"proxy":      map[string]interface{}{"type": "Http"},
+		"serveStale": true,
+	}
+	q, err = settingsQuery(dump, nil)
+	if err != nil {
+		t.Fatalf("dump: %v", err)
+	}
+	if len(q) != 1 || q.Get("serveStale") != "true" {
+		t.Errorf("dump query = %v", q)
+	}
+	if diffs := diffSettings(map[string]interface{}{"version": "13.7", "tsigKeys": []interface{}{}, "serveStale": false}, dump); len(diffs) != 1 || diffs[0].Setting != "serveStale" {
+		t.Errorf("dump diffs = %+v", diffs)
+	}
+	if reason := skippedSetting("tsigKeys", dump["tsigKeys"]); !strings.Contains(reason, "tdns tsig") {
+		t.Errorf("tsigKeys reason = %q", reason)
+	}
+}
+
+func TestDiffSettings(t *testing.T) {
+	var server map[string]interface{}
+	if err := json.Unmarshal([]byte(`{"version":"13.6","forwarders":["1.1.1.1"],"cacheMaximumEntries":10000,
+		"enableBlocking":true,"serveStale":true}`), &server); err != nil {
+		t.Fatal(err)
+	}
+	desired := map[string]interface{}{
+		"version":             "12.0",
+		"forwarders":          []interface{}{"9.9.9.9"},
+		"cacheMaximumEntries": json.Number("10000.0"),
+		"enableBlocking":      "true",
+		"noSuchSetting":       1,
+	}
+
+	diffs := diffSettings(server, desired)
+	var names []string
+	for _, d := range diffs {
+		names = append(names, d.Setting)
+	}
+	// The version is skipped, the numbers match, and the string "true" is
+	// not the boolean true.
+	if got := strings.Join(names, ","); got != "enableBlocking,forwarders,noSuchSetting" {
+		t.Fatalf("diffs = %+v", diffs)
+	}
+	if !diffs[2].Unknown || diffs[0].Unknown {
+		t.Errorf("diffs = %+v", diffs)
+	}
+}
+
+func TestSettingsSetPostsForm(t *testing.T) {
+	var method, rawQuery string
+	var form url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/settings/set" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		r.ParseForm()
+		method, rawQuery, form = r.Method, r.URL.RawQuery, r.PostForm
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"status":"ok","response":{}}`)
+	}))
+	defer srv.Close()
+
+	oldHost := viper.GetString("host")
+	viper.Set("host", srv.URL)
+	defer viper.Set("host", oldHost)
+	resetOutput(t)
+	t.Cleanup(func() { settingsDataFile = "" })
+
+	file := filepath.Join(t.TempDir(), "settings.json")
+	data := `{"version":"13.6","tsigKeys":[{"keyName":"k","sharedSecret":"c2VjcmV0"}],"forwarders":["1.1.1.1"]}`
+	if err := os.WriteFile(file, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	oldStdout := os.Stdout
+	rp, wp, _ := os.Pipe()
+	os.Stdout = wp
+	defer func() { os.Stdout = oldStdout }()
+	go func() { _, _ = io.Copy(io.Discard, rp) }()
+
+	rootCmd.SetArgs([]string{"settings", "set", "--data-file", file, "enableBlocking=true"})
+	defer rootCmd.SetArgs(nil)
+	err := rootCmd.Execute()
+	wp.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method != http.MethodPost || strings.Contains(rawQuery, "forwarders") {
+		t.Errorf("request = %s ?%s", method, rawQuery)
+	}
+	if len(form) != 2 || form.Get("forwarders") != "1.1.1.1" || form.Get("enableBlocking") != "true" {
+		t.Errorf("form = %v", form)
+	}
+}
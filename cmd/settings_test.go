@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"tdns/internal/backup"
+)
+
+func TestBackupQuery(t *testing.T) {
+	q := backupQuery([]string{"zones", "dnsSettings"}, map[string]string{"deleteExistingFiles": "false"})
+	if len(q) != 11 {
+		t.Errorf("query has %d parameters, want every category and deleteExistingFiles: %v", len(q), q)
+	}
+	if q.Get("zones") != "true" || q.Get("dnsSettings") != "true" || q.Get("logs") != "false" || q.Get("deleteExistingFiles") != "false" {
+		t.Errorf("query = %v", q)
+	}
+}
+
+func TestRestoreOnlyCategoriesInBackup(t *testing.T) {
+	zipPath := filepath.Join(t.TempDir(), "backup.zip")
+	f, _ := os.Create(zipPath)
+	w := zip.NewWriter(f)
+	for _, name := range []string{"dns.config", "zones/example.com.zone"} {
+		fw, _ := w.Create(name)
+		fmt.Fprint(fw, name)
+	}
+	w.Close()
+	f.Close()
+
+	var got url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"status":"ok","response":{}}`)
+	}))
+	defer srv.Close()
+
+	oldHost := viper.GetString("host")
+	viper.Set("host", srv.URL)
+	defer viper.Set("host", oldHost)
+	resetOutput(t)
+	backupInclude, backupExclude, keepExistingFiles = nil, nil, false
+	t.Cleanup(func() { backupInclude, backupExclude, keepExistingFiles = nil, nil, false })
+
+	oldStdout := os.Stdout
+	rp, wp, _ := os.Pipe()
+	os.Stdout = wp
+	defer func() { os.Stdout = oldStdout }()
+	go func() { _, _ = io.Copy(io.Discard, rp) }()
+
+	rootCmd.SetArgs([]string{"settings", "restore", "--input", zipPath, "--exclude", "dnsSettings", "--keep-existing-files"})
+	defer rootCmd.SetArgs(nil)
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("settings restore: %v", err)
+	}
+	wp.Close()
+
+	// Only zones is both in the backup and not excluded.
+	if got.Get("zones") != "true" || got.Get("dnsSettings") != "false" || got.Get("logs") != "false" || got.Get("deleteExistingFiles") != "false" {
+		t.Errorf("query = %v", got)
+	}
+}
+
+func TestBackupDirWritesChecksumAndPrunes(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, backup.Name(time.Now().AddDate(0, 0, -3)))
+	os.WriteFile(old, []byte("old"), 0o600)
+	os.WriteFile(old+backup.ChecksumSuffix, []byte("x"), 0o600)
+
+	backupDir, backupRetention = "", backup.Retention{}
+	t.Cleanup(func() { backupDir, backupRetention = "", backup.Retention{} })
+	out, err := runWithStub(t, "PK-zip-data", "settings", "backup", "--dir", dir, "--keep-daily", "1")
+	if err != nil {
+		t.Fatalf("settings backup: %v", err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Fatalf("dir has %v, want the new backup and its checksum\n%s", entries, out)
+	}
+	p := filepath.Join(dir, entries[0].Name())
+	if data, _ := os.ReadFile(p); string(data) != "PK-zip-data" {
+		t.Errorf("%s = %q", p, data)
+	}
+	if err := backup.VerifyChecksum(p); err != nil {
+		t.Errorf("VerifyChecksum: %v", err)
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Errorf("old backup not pruned: %v", err)
+	}
+}
+
+func TestBackupFileWithStructuredOutput(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "backup.zip")
+	t.Cleanup(func() { backupFilePath = "" })
+	out, err := runWithStub(t, "PK-zip-data", "settings", "backup", "--file", file, "-o", "json")
+	if err != nil {
+		t.Fatalf("settings backup: %v", err)
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out)
+	}
+	if result["file"] != file {
+		t.Errorf("file = %v, want %s", result["file"], file)
+	}
+	if data, _ := os.ReadFile(file); string(data) != "PK-zip-data" {
+		t.Errorf("%s = %q", file, data)
+	}
+}
+
+func TestBackupDefaultNameWritesChecksum(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if _, err := runWithStub(t, "PK-zip-data", "settings", "backup"); err != nil {
+		t.Fatalf("settings backup: %v", err)
+	}
+	entries, _ := os.ReadDir(".")
+	if len(entries) != 2 {
+		t.Fatalf("dir has %v, want the backup and its checksum", entries)
+	}
+	if err := backup.VerifyChecksum(entries[0].Name()); err != nil {
+		t.Errorf("VerifyChecksum: %v", err)
+	}
+}
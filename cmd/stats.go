@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"tdns/internal/api"
+	"tdns/pkg/technitium"
+)
+
+var (
+	statsType  string
+	statsStart string
+	statsEnd   string
+	statsLimit int
+)
+
+// statsTypes are the periods --type accepts.
+var statsTypes = []string{
+	technitium.StatsLastHour,
+	technitium.StatsLastDay,
+	technitium.StatsLastWeek,
+	technitium.StatsLastMonth,
+	technitium.StatsLastYear,
+	technitium.StatsCustom,
+}
+
+// topStatsTypes maps the lists of `stats top` to their API stats type.
+var topStatsTypes = map[string]string{
+	"clients": technitium.TopClients,
+	"domains": technitium.TopDomains,
+	"blocked": technitium.TopBlockedDomains,
+}
+
+// statsTimeLayouts are the formats --start and --end accept, in local time
+// unless they carry a zone.
+var statsTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02"}
+
+func parseStatsTime(flag, s string) (time.Time, error) {
+	for _, layout := range statsTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid --%s %q (use YYYY-MM-DD, YYYY-MM-DD HH:MM or RFC 3339)", flag, s)
+}
+
+// statsOptions builds the period from the --type, --start and --end flags.
+// Giving --start or --end without --type implies --type custom.
+func statsOptions(cmd *cobra.Command) (technitium.StatsOptions, error) {
+	var opts technitium.StatsOptions
+	if !cmd.Flags().Changed("type") && (statsStart != "" || statsEnd != "") {
+		statsType = technitium.StatsCustom
+	}
+	for _, t := range statsTypes {
+		if strings.EqualFold(t, statsType) {
+			opts.Type = t
+		}
+	}
+	if opts.Type == "" {
+		return opts, fmt.Errorf("unknown --type %q (use %s)", statsType, strings.Join(statsTypes, ", "))
+	}
+	if opts.Type != technitium.StatsCustom {
+		if statsStart != "" || statsEnd != "" {
+			return opts, fmt.Errorf("--start and --end need --type custom")
+		}
+		return opts, nil
+	}
+
+	if statsStart == "" || statsEnd == "" {
+		return opts, fmt.Errorf("--type custom needs --start and --end")
+	}
+	var err error
+	if opts.Start, err = parseStatsTime("start", statsStart); err != nil {
+		return opts, err
+	}
+	if opts.End, err = parseStatsTime("end", statsEnd); err != nil {
+		return opts, err
+	}
+	if !opts.End.After(opts.Start) {
+		return opts, fmt.Errorf("--end must be after --start")
+	}
+	return opts, nil
+}
+
+// statsTotal is one counter of `tdns stats` in table and csv output.
+type statsTotal struct {
+	Stat  string `json:"stat"`
+	Value int64  `json:"value"`
+}
+
+func statsTotals(s technitium.StatsTotals) []statsTotal {
+	return []statsTotal{
+		{"totalQueries", s.TotalQueries},
+		{"totalNoError", s.TotalNoError},
+		{"totalServerFailure", s.TotalServerFailure},
+		{"totalNxDomain", s.TotalNxDomain},
+		{"totalRefused", s.TotalRefused},
+		{"totalAuthoritative", s.TotalAuthoritative},
+		{"totalRecursive", s.TotalRecursive},
+		{"totalCached", s.TotalCached},
+		{"totalBlocked", s.TotalBlocked},
+		{"totalDropped", s.TotalDropped},
+		{"totalClients", s.TotalClients},
+		{"zones", s.Zones},
+		{"cachedEntries", s.CachedEntries},
+		{"allowedZones", s.AllowedZones},
+		{"blockedZones", s.BlockedZones},
+	}
+}
+
+// percent formats n as a share of total.
+func percent(n, total int64) string {
+	if total == 0 {
+		return ""
+	}
+	return grey(fmt.Sprintf("(%.1f%%)", float64(n)*100/float64(total)))
+}
+
+// printTopList prints a numbered top-N list under title.
+func printTopList(title string, list []technitium.TopEntry) {
+	fmt.Println(bold(title + ":"))
+	if len(list) == 0 {
+		fmt.Println("  (none)")
+		return
+	}
+	width := 0
+	for _, e := range list {
+		width = max(width, len(e.Name))
+	}
+	for i, e := range list {
+		extra := ""
+		if e.Domain != "" {
+			extra = " " + grey(e.Domain)
+		}
+		if e.RateLimited {
+			extra += " " + yellow("rate limited")
+		}
+		fmt.Printf("  %2d. %s %8d%s\n", i+1, cyan(fmt.Sprintf("%-*s", width, e.Name)), e.Hits, extra)
+	}
+}
+
+// printStats prints the dashboard statistics of period.
+func printStats(period string, s *technitium.DashboardStats) {
+	t := s.Stats
+	fmt.Printf("%s %s\n\n", bold("Statistics:"), blue(period))
+	fmt.Printf("  %-15s %10d\n", "Queries", t.TotalQueries)
+	fmt.Printf("  %-15s %10d %s\n", "No error", t.TotalNoError, percent(t.TotalNoError, t.TotalQueries))
+	fmt.Printf("  %-15s %10d %s\n", "NXDOMAIN", t.TotalNxDomain, percent(t.TotalNxDomain, t.TotalQueries))
+	fmt.Printf("  %-15s %10d %s\n", "Server failure", t.TotalServerFailure, percent(t.TotalServerFailure, t.TotalQueries))
+	fmt.Printf("  %-15s %10d %s\n", "Refused", t.TotalRefused, percent(t.TotalRefused, t.TotalQueries))
+	fmt.Println()
+	fmt.Printf("  %-15s %10d %s\n", "Authoritative", t.TotalAuthoritative, percent(t.TotalAuthoritative, t.TotalQueries))
+	fmt.Printf("  %-15s %10d %s\n", "Recursive", t.TotalRecursive, percent(t.TotalRecursive, t.TotalQueries))
+	fmt.Printf("  %-15s %10d %s\n", "Cached", t.TotalCached, percent(t.TotalCached, t.TotalQueries))
+	fmt.Printf("  %-15s %10d %s\n", "Blocked", t.TotalBlocked, percent(t.TotalBlocked, t.TotalQueries))
+	fmt.Printf("  %-15s %10d %s\n", "Dropped", t.TotalDropped, percent(t.TotalDropped, t.TotalQueries))
+	fmt.Println()
+	fmt.Printf("  %-15s %10d\n", "Clients", t.TotalClients)
+	fmt.Printf("  %-15s %10d\n", "Zones", t.Zones)
+	fmt.Printf("  %-15s %10d\n", "Cache entries", t.CachedEntries)
+	fmt.Printf("  %-15s %10d\n", "Allowed zones", t.AllowedZones)
+	fmt.Printf("  %-15s %10d\n", "Blocked zones", t.BlockedZones)
+	fmt.Println()
+	printTopList("Top Clients", s.TopClients)
+	fmt.Println()
+	printTopList("Top Domains", s.TopDomains)
+	fmt.Println()
+	printTopList("Top Blocked Domains", s.TopBlockedDomains)
+}
+
+// statsPeriod describes the period of opts for headings.
+func statsPeriod(opts technitium.StatsOptions) string {
+	if opts.Type != technitium.StatsCustom {
+		return opts.Type
+	}
+	return opts.Start.Format("2006-01-02 15:04") + " – " + opts.End.Format("2006-01-02 15:04")
+}
+
+var statsCmd = &cobra.Command{
+	Use:     "stats",
+	Aliases: []string{"st"},
+	Short:   "Show the dashboard statistics of the server",
+	Long: `Show the query totals of the server for a period, with its top clients, top
+domains and top blocked domains (10 of each; see 'tdns stats top' for more).
+
+The period is --type LastHour (the default), LastDay, LastWeek, LastMonth or
+LastYear, or custom with --start and --end:
+
+  tdns stats --type custom --start 2024-05-01 --end "2024-05-02 12:00"`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		opts, err := statsOptions(cmd)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		s, err := api.New().GetStats(cmd.Context(), opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		renderList(s, statsTotals(s.Stats), func() {
+			printStats(statsPeriod(opts), s)
+		})
+	},
+}
+
+var statsTopCmd = &cobra.Command{
+	Use:       "top [clients|domains|blocked]",
+	Short:     "Show a longer list of top clients, domains or blocked domains",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"clients", "domains", "blocked"},
+	Run: func(cmd *cobra.Command, args []string) {
+		statsKind, ok := topStatsTypes[strings.ToLower(args[0])]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "❌ unknown list %q (use clients, domains or blocked)\n", args[0])
+			os.Exit(1)
+		}
+		opts, err := statsOptions(cmd)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		top, err := api.New().GetTopStats(cmd.Context(), opts, statsKind, statsLimit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		renderList(top, top, func() {
+			title := map[string]string{
+				technitium.TopClients:        "Top Clients",
+				technitium.TopDomains:        "Top Domains",
+				technitium.TopBlockedDomains: "Top Blocked Domains",
+			}[statsKind]
+			printTopList(fmt.Sprintf("%s (%s)", title, statsPeriod(opts)), top)
+		})
+	},
+}
+
+func init() {
+	statsCmd.PersistentFlags().StringVar(&statsType, "type", technitium.StatsLastHour, "Period: LastHour, LastDay, LastWeek, LastMonth, LastYear or custom")
+	statsCmd.PersistentFlags().StringVar(&statsStart, "start", "", "Start of a custom period (YYYY-MM-DD[ HH:MM], local time, or RFC 3339)")
+	statsCmd.PersistentFlags().StringVar(&statsEnd, "end", "", "End of a custom period")
+	statsTopCmd.Flags().IntVarP(&statsLimit, "limit", "n", 100, "Number of entries to show")
+	statsCmd.AddCommand(statsTopCmd)
+	rootCmd.AddCommand(statsCmd)
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"tdns/pkg/technitium"
+)
+
+func TestStatsOptions(t *testing.T) {
+	tests := []struct {
+		typ, start, end string
+		want            string
+		wantErr         bool
+	}{
+		{typ: "LastHour", want: technitium.StatsLastHour},
+		{typ: "lastday", want: technitium.StatsLastDay},
+		{typ: "custom", start: "2024-05-01", end: "2024-05-02 12:00", want: technitium.StatsCustom},
+		{start: "2024-05-01", end: "2024-05-02", want: technitium.StatsCustom},
+		{typ: "custom", start: "2024-05-01", wantErr: true},
+		{typ: "LastDay", start: "2024-05-01", end: "2024-05-02", wantErr: true},
+		{typ: "custom", start: "2024-05-02", end: "2024-05-01", wantErr: true},
+		{typ: "custom", start: "May 1", end: "2024-05-02", wantErr: true},
+		{typ: "Yesterday", wantErr: true},
+	}
+	for _, tt := range tests {
+		cmd := &cobra.Command{}
+		cmd.Flags().StringVar(&statsType, "type", technitium.StatsLastHour, "")
+		if tt.typ != "" {
+			cmd.Flags().Set("type", tt.typ)
+		}
+		statsStart, statsEnd = tt.start, tt.end
+		opts, err := statsOptions(cmd)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%+v: err = %v, wantErr %v", tt, err, tt.wantErr)
+			continue
+		}
+		if err == nil && opts.Type != tt.want {
+			t.Errorf("%+v: type = %q, want %q", tt, opts.Type, tt.want)
+		}
+	}
+	statsType, statsStart, statsEnd = technitium.StatsLastHour, "", ""
+
+	cmd := &cobra.Command{}
+	statsType, statsStart, statsEnd = "custom", "2024-05-01 08:30", "2024-05-01T10:00:00Z"
+	opts, err := statsOptions(cmd)
+	statsType, statsStart, statsEnd = technitium.StatsLastHour, "", ""
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 5, 1, 8, 30, 0, 0, time.Local); !opts.Start.Equal(want) {
+		t.Errorf("start = %v, want %v (local time)", opts.Start, want)
+	}
+	if want := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC); !opts.End.Equal(want) {
+		t.Errorf("end = %v, want %v", opts.End, want)
+	}
+}
+
+const statsResponse = `{"status":"ok","response":{
+	"stats":{"totalQueries":200,"totalNoError":150,"totalNxDomain":30,"totalBlocked":20,"totalCached":80,"totalRecursive":60,"totalClients":4},
+	"topClients":[{"name":"192.0.2.10","domain":"laptop.lan","hits":120}],
+	"topDomains":[{"name":"example.com","hits":90}],
+	"topBlockedDomains":[{"name":"ads.example.net","hits":20}]}}`
+
+func TestStatsText(t *testing.T) {
+	out, err := runWithStub(t, statsResponse, "stats")
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	for _, want := range []string{"LastHour", "200", "(15.0%)", "(10.0%)", "Top Clients:", "192.0.2.10", "laptop.lan", "example.com", "Top Blocked Domains:", "ads.example.net"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output is missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestStatsCSV(t *testing.T) {
+	out, err := runWithStub(t, statsResponse, "stats", "--output", "csv")
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if lines[0] != "stat,value" || lines[1] != "totalQueries,200" {
+		t.Errorf("csv output:\n%s", out)
+	}
+}
+
+func TestStatsTop(t *testing.T) {
+	out, err := runWithStub(t, `{"status":"ok","response":{"topDomains":[{"name":"example.com","hits":90},{"name":"example.org","hits":7}]}}`,
+		"stats", "top", "domains", "--output", "json")
+	if err != nil {
+		t.Fatalf("stats top: %v", err)
+	}
+	if !strings.Contains(out, `"name": "example.org"`) || !strings.Contains(out, `"hits": 7`) {
+		t.Errorf("json output:\n%s", out)
+	}
+}
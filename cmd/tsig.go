@@ -0,0 +1,312 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"tdns/internal/api"
+	"tdns/pkg/technitium"
+)
+
+var (
+	tsigAlgorithm   string
+	tsigSecret      string
+	tsigBind        bool
+	tsigShowSecrets bool
+)
+
+// tsigKeySizes are the TSIG algorithms the server supports with the size in
+// bytes of a generated secret: the length of the hash's output.
+var tsigKeySizes = map[string]int{
+	"hmac-md5.sig-alg.reg.int": 16,
+	"hmac-sha1":                20,
+	"hmac-sha256":              32,
+	"hmac-sha256-128":          32,
+	"hmac-sha384":              48,
+	"hmac-sha384-192":          48,
+	"hmac-sha512":              64,
+	"hmac-sha512-256":          64,
+}
+
+// tsigAlgorithmName returns the server's name of a TSIG algorithm given in
+// any case, accepting BIND's "hmac-md5" too.
+func tsigAlgorithmName(s string) (string, error) {
+	name := strings.ToLower(s)
+	if name == "hmac-md5" {
+		name = "hmac-md5.sig-alg.reg.int"
+	}
+	if _, ok := tsigKeySizes[name]; !ok {
+		names := make([]string, 0, len(tsigKeySizes))
+		for n := range tsigKeySizes {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return "", fmt.Errorf("unknown TSIG algorithm %q (use one of %s)", s, strings.Join(names, ", "))
+	}
+	return name, nil
+}
+
+// tsigKeyName checks that the key name s is a domain name, as TSIG key names
+// are, and returns it without the trailing dot. A "|" is refused as well,
+// since the API joins every key into one "|"-separated list.
+func tsigKeyName(s string) (string, error) {
+	name := strings.TrimSuffix(s, ".")
+	if name == "" {
+		return "", fmt.Errorf("TSIG key name must not be empty")
+	}
+	if err := validateDomainName(name); err != nil {
+		return "", fmt.Errorf("invalid TSIG key name: %w", err)
+	}
+	if strings.Contains(name, "|") {
+		return "", fmt.Errorf("invalid TSIG key name %q: it must not contain \"|\"", s)
+	}
+	return name, nil
+}
+
+// tsigKeySecret returns the --secret flag after checking it is base64, or a
+// new random secret for algorithm.
+func tsigKeySecret(algorithm string) (string, error) {
+	if tsigSecret != "" {
+		if _, err := base64.StdEncoding.DecodeString(tsigSecret); err != nil {
+			return "", fmt.Errorf("--secret is not valid base64: %w", err)
+		}
+		return tsigSecret, nil
+	}
+	b := make([]byte, tsigKeySizes[algorithm])
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// bindKeyConfig formats k as a BIND key statement, for named.conf on a
+// secondary server.
+func bindKeyConfig(k technitium.TSIGKey) string {
+	return fmt.Sprintf("key \"%s\" {\n\talgorithm %s;\n\tsecret \"%s\";\n};\n",
+		k.KeyName, strings.TrimSuffix(k.AlgorithmName, ".sig-alg.reg.int"), k.SharedSecret)
+}
+
+// findTSIGKey returns the index of the key named name in keys, or -1.
+func findTSIGKey(keys []technitium.TSIGKey, name string) int {
+	for i, k := range keys {
+		if strings.EqualFold(k.KeyName, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// printTSIGKey prints a key that was just added or rotated, whose secret
+// has to be copied to the other server.
+func printTSIGKey(k technitium.TSIGKey, action string) {
+	render(k, func() {
+		fmt.Printf("✅ TSIG key '%s' %s.\n", k.KeyName, action)
+		if tsigBind {
+			fmt.Print(bindKeyConfig(k))
+			return
+		}
+		fmt.Printf("  Algorithm: %s\n", k.AlgorithmName)
+		fmt.Printf("  Secret: %s\n", k.SharedSecret)
+	})
+}
+
+var tsigCmd = &cobra.Command{
+	Use:   "tsig",
+	Short: "Manage the server's TSIG keys",
+	Long: `Manage the TSIG keys the server uses to authenticate zone transfers and
+dynamic updates. Keys are referenced by name in zone options such as
+--primaryZoneTransferTsigKeyName.
+
+The keys are stored as one list in the server settings, so each change
+reads the list, edits it and writes it back whole.`,
+}
+
+var tsigListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List TSIG keys",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		settings, err := api.New().GetSettings(cmd.Context())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		keys := settings.TSIGKeys
+		if !tsigShowSecrets && !tsigBind {
+			for i := range keys {
+				keys[i].SharedSecret = ""
+			}
+		}
+
+		renderList(keys, keys, func() {
+			if len(keys) == 0 {
+				fmt.Println("No TSIG keys found.")
+				return
+			}
+			if tsigBind {
+				for _, k := range keys {
+					fmt.Print(bindKeyConfig(k))
+				}
+				return
+			}
+			fmt.Println(bold("TSIG Keys:"))
+			for _, k := range keys {
+				fmt.Printf("- %s (%s)\n", cyan(k.KeyName), yellow(k.AlgorithmName))
+				if tsigShowSecrets {
+					fmt.Printf("  Secret: %s\n", k.SharedSecret)
+				}
+			}
+		})
+	},
+}
+
+var tsigAddCmd = &cobra.Command{
+	Use:   "add [name]",
+	Short: "Add a TSIG key with a new random secret",
+	Long: `Add a TSIG key. The secret is generated locally from a secure random source,
+as long as the algorithm's hash, unless --secret gives one (base64).
+
+The key is printed so it can be configured on the other server; --bind
+prints it as a BIND key statement:
+
+  tdns tsig add xfer.example.com --bind >> /etc/bind/keys.conf`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name, err := tsigKeyName(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		algorithm, err := tsigAlgorithmName(tsigAlgorithm)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		secret, err := tsigKeySecret(algorithm)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		client := api.New()
+		settings, err := client.GetSettings(cmd.Context())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		if findTSIGKey(settings.TSIGKeys, name) >= 0 {
+			fmt.Fprintf(os.Stderr, "❌ TSIG key '%s' already exists; use 'tdns tsig rotate' to change its secret\n", name)
+			os.Exit(1)
+		}
+
+		key := technitium.TSIGKey{KeyName: name, SharedSecret: secret, AlgorithmName: algorithm}
+		if err := client.SetTSIGKeys(cmd.Context(), append(settings.TSIGKeys, key)); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		printTSIGKey(key, "added")
+	},
+}
+
+var tsigRemoveCmd = &cobra.Command{
+	Use:     "remove [name]",
+	Aliases: []string{"rm"},
+	Short:   "Remove a TSIG key",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := strings.TrimSuffix(args[0], ".")
+		client := api.New()
+		settings, err := client.GetSettings(cmd.Context())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		i := findTSIGKey(settings.TSIGKeys, name)
+		if i < 0 {
+			fmt.Fprintf(os.Stderr, "❌ TSIG key '%s' not found\n", name)
+			os.Exit(1)
+		}
+
+		if !confirmed(fmt.Sprintf("Remove TSIG key '%s'? Zone transfers using it will fail.", name)) {
+			return
+		}
+
+		keys := append(settings.TSIGKeys[:i:i], settings.TSIGKeys[i+1:]...)
+		if err := client.SetTSIGKeys(cmd.Context(), keys); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		render(map[string]string{"keyName": name}, func() {
+			fmt.Printf("✅ TSIG key '%s' removed.\n", name)
+		})
+	},
+}
+
+var tsigRotateCmd = &cobra.Command{
+	Use:   "rotate [name]",
+	Short: "Give a TSIG key a new random secret",
+	Long: `Replace the secret of a TSIG key with a new random one (or --secret), keeping
+its name, so zones that reference it need no change. --algorithm changes the
+algorithm too. Update the other server with the printed key right away:
+transfers fail until both sides have the new secret.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := strings.TrimSuffix(args[0], ".")
+		client := api.New()
+		settings, err := client.GetSettings(cmd.Context())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		i := findTSIGKey(settings.TSIGKeys, name)
+		if i < 0 {
+			fmt.Fprintf(os.Stderr, "❌ TSIG key '%s' not found\n", name)
+			os.Exit(1)
+		}
+
+		key := settings.TSIGKeys[i]
+		if cmd.Flags().Changed("algorithm") {
+			if key.AlgorithmName, err = tsigAlgorithmName(tsigAlgorithm); err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if key.SharedSecret, err = tsigKeySecret(key.AlgorithmName); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		settings.TSIGKeys[i] = key
+		if err := client.SetTSIGKeys(cmd.Context(), settings.TSIGKeys); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		printTSIGKey(key, "rotated")
+	},
+}
+
+func init() {
+	tsigListCmd.Flags().BoolVar(&tsigShowSecrets, "show-secrets", false, "Show the keys' secrets")
+	tsigListCmd.Flags().BoolVar(&tsigBind, "bind", false, "Print the keys, with their secrets, as BIND key statements")
+
+	for _, c := range []*cobra.Command{tsigAddCmd, tsigRotateCmd} {
+		c.Flags().StringVar(&tsigAlgorithm, "algorithm", "hmac-sha256", "TSIG algorithm, such as hmac-sha256 or hmac-sha512")
+		c.Flags().StringVar(&tsigSecret, "secret", "", "Base64 secret to use instead of a generated one")
+		c.Flags().BoolVar(&tsigBind, "bind", false, "Print the key as a BIND key statement")
+	}
+
+	tsigRemoveCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Assume yes when asking for confirmation")
+
+	tsigCmd.AddCommand(tsigListCmd)
+	tsigCmd.AddCommand(tsigAddCmd)
+	tsigCmd.AddCommand(tsigRemoveCmd)
+	tsigCmd.AddCommand(tsigRotateCmd)
+	rootCmd.AddCommand(tsigCmd)
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"tdns/pkg/technitium"
+)
+
+func TestTsigKeySecret(t *testing.T) {
+	tsigSecret = ""
+	for alg, size := range tsigKeySizes {
+		s, err := tsigKeySecret(alg)
+		if err != nil {
+			t.Fatalf("%s: %v", alg, err)
+		}
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil || len(b) != size {
+			t.Errorf("%s: secret %q decodes to %d bytes (%v), want %d", alg, s, len(b), err, size)
+		}
+	}
+	if a, _ := tsigKeySecret("hmac-sha256"); a == "" {
+		t.Error("empty secret")
+	} else if b, _ := tsigKeySecret("hmac-sha256"); a == b {
+		t.Error("two generated secrets are equal")
+	}
+}
+
+func TestTsigAlgorithmName(t *testing.T) {
+	for in, want := range map[string]string{"HMAC-SHA512": "hmac-sha512", "hmac-md5": "hmac-md5.sig-alg.reg.int"} {
+		if got, err := tsigAlgorithmName(in); err != nil || got != want {
+			t.Errorf("tsigAlgorithmName(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := tsigAlgorithmName("hmac-sha3"); err == nil {
+		t.Error("unknown algorithm accepted")
+	}
+}
+
+func TestTsigKeyName(t *testing.T) {
+	if got, err := tsigKeyName("xfer.example.com."); err != nil || got != "xfer.example.com" {
+		t.Errorf("tsigKeyName = %q, %v", got, err)
+	}
+	for _, bad := range []string{"a|b", "xfer key", "", ".", "a..b"} {
+		if _, err := tsigKeyName(bad); err == nil {
+			t.Errorf("tsigKeyName(%q) accepted", bad)
+		}
+	}
+}
+
+func TestBindKeyConfig(t *testing.T) {
+	got := bindKeyConfig(technitium.TSIGKey{KeyName: "xfer", SharedSecret: "c2VjcmV0", AlgorithmName: "hmac-md5.sig-alg.reg.int"})
+	want := "key \"xfer\" {\n\talgorithm hmac-md5;\n\tsecret \"c2VjcmV0\";\n};\n"
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTsigRotateKeepsOtherKeys(t *testing.T) {
+	var set url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/settings/set" {
+			r.ParseForm()
+			set = r.PostForm
+			fmt.Fprint(w, `{"status":"ok","response":{}}`)
+			return
+		}
+		fmt.Fprint(w, `{"status":"ok","response":{"tsigKeys":[
+			{"keyName":"a","sharedSecret":"b2xkYQ==","algorithmName":"hmac-sha256"},
+			{"keyName":"b","sharedSecret":"b2xkYg==","algorithmName":"hmac-sha512"}]}}`)
+	}))
+	defer srv.Close()
+
+	oldHost := viper.GetString("host")
+	viper.Set("host", srv.URL)
+	defer viper.Set("host", oldHost)
+	resetOutput(t)
+	tsigSecret, tsigBind = "", false
+
+	oldStdout := os.Stdout
+	rp, wp, _ := os.Pipe()
+	os.Stdout = wp
+	defer func() { os.Stdout = oldStdout }()
+	go func() { _, _ = io.Copy(io.Discard, rp) }()
+
+	rootCmd.SetArgs([]string{"tsig", "rotate", "b"})
+	defer rootCmd.SetArgs(nil)
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("tsig rotate: %v", err)
+	}
+	wp.Close()
+
+	rows := strings.Split(set.Get("tsigKeys"), "|")
+	if len(rows) != 6 || rows[0] != "a" || rows[1] != "b2xkYQ==" || rows[3] != "b" || rows[5] != "hmac-sha512" {
+		t.Fatalf("tsigKeys = %q", set.Get("tsigKeys"))
+	}
+	if rows[4] == "b2xkYg==" {
+		t.Error("the secret of key b was not changed")
+	}
+}
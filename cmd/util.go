@@ -19,6 +19,20 @@ var (
 	cyan   = color.New(color.FgCyan).SprintFunc()
 )
 
+// formatSize formats a number of bytes for people, for example "8.5 KB".
+func formatSize(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
 func FormatMap(input interface{}) string {
 	return formatValue(input)
 }
@@ -65,3 +79,19 @@ func formatValue(val interface{}) string {
 		return fmt.Sprintf("%v", v) // no color fallback
 	}
 }
+
+// confirmed asks question and reports whether the answer was yes, or whether
+// --yes was given.
+func confirmed(question string) bool {
+	if assumeYes {
+		return true
+	}
+	fmt.Fprintf(messages(), "%s (yes/no): ", question)
+	var confirm string
+	fmt.Scanln(&confirm)
+	if confirm != "yes" {
+		fmt.Fprintln(messages(), "❌ Aborted.")
+		return false
+	}
+	return true
+}
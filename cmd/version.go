@@ -11,7 +11,9 @@ var versionCmd = &cobra.Command{
 	Aliases: []string{"ver"},
 	Short:   "Print client version",
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println(Version)
+		render(map[string]string{"version": Version}, func() {
+			fmt.Println(Version)
+		})
 	},
 }
 
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"tdns/internal/zonefile"
+)
+
+var validateFile string
+
+// parseZoneData parses zone file data read by readZoneFile from path. The
+// relative $INCLUDEs of a file on disk resolve against its own directory,
+// and those of standard input against the current one.
+func parseZoneData(path, zone string, data []byte) ([]zonefile.Record, error) {
+	dir := ""
+	if path != "-" {
+		dir = filepath.Dir(path)
+	}
+	return zonefile.Parse(bytes.NewReader(data), zone, dir)
+}
+
+// parseZoneFile reads and parses the zone file at path, or standard input
+// when path is "-".
+func parseZoneFile(path, zone string) ([]zonefile.Record, error) {
+	data, err := readZoneFile(path)
+	if err != nil {
+		return nil, err
+	}
+	recs, err := parseZoneData(path, zone, data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid zone file: %w", err)
+	}
+	return recs, nil
+}
+
+// zoneFileIssues parses the zone file data and runs every check on it. Parse
+// errors are returned as issues too, so that everything is reported at once.
+func zoneFileIssues(path, zone string, data []byte) ([]zonefile.Issue, error) {
+	recs, err := parseZoneData(path, zone, data)
+	var issues []zonefile.Issue
+	if err != nil {
+		var list zonefile.ErrorList
+		if !errors.As(err, &list) {
+			return nil, err
+		}
+		for _, e := range list {
+			issues = append(issues, zonefile.Issue{File: e.File, Line: e.Line, Msg: e.Msg})
+		}
+	}
+	return append(issues, zonefile.Validate(recs, zone)...), nil
+}
+
+// printZoneFileIssues prints issues to stderr and returns the number of
+// errors among them.
+func printZoneFileIssues(issues []zonefile.Issue) int {
+	errs := 0
+	for _, i := range issues {
+		if i.Warning {
+			fmt.Fprintf(os.Stderr, "⚠️  %s\n", i)
+			continue
+		}
+		errs++
+		fmt.Fprintf(os.Stderr, "❌ %s\n", i)
+	}
+	return errs
+}
+
+var zoneCmd = &cobra.Command{
+	Use:     "zone",
+	Aliases: []string{"zo"},
+	Short:   "Work with zone files",
+}
+
+var zoneValidateCmd = &cobra.Command{
+	Use:     "validate [zone]",
+	Aliases: []string{"va"},
+	Short:   "Check a zone file for errors without contacting the server",
+	Long: `Parse an RFC 1035 (BIND style) zone file for the given zone and report every
+problem found, with its line number:
+
+  - syntax errors and malformed record data
+  - names outside the zone
+  - a CNAME record sharing its name with other data, or with another CNAME
+  - no NS records at the zone apex (a warning; importing such a file with
+    --overwrite-zone leaves the zone without them)
+
+$ORIGIN, $TTL and $INCLUDE are supported. The zone file is named with --file,
+or read from standard input when --file is "-". The command exits with status
+1 when there are errors, so it can guard an import:
+
+  tdns zone validate example.com --file example.com.zone && \
+    tdns import example.com --file example.com.zone`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		zone := args[0]
+		data, err := readZoneFile(validateFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		issues, err := zoneFileIssues(validateFile, zone, data)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		if structuredOutput() {
+			if issues == nil {
+				issues = []zonefile.Issue{}
+			}
+			errs := 0
+			for _, i := range issues {
+				if !i.Warning {
+					errs++
+				}
+			}
+			result := struct {
+				Zone   string           `json:"zone"`
+				Valid  bool             `json:"valid"`
+				Issues []zonefile.Issue `json:"issues"`
+			}{zone, errs == 0, issues}
+			renderList(result, issues, nil)
+			if errs > 0 {
+				os.Exit(1)
+			}
+			return
+		}
+		if errs := printZoneFileIssues(issues); errs > 0 {
+			fmt.Fprintf(os.Stderr, "\n%d error(s), %d warning(s).\n", errs, len(issues)-errs)
+			os.Exit(1)
+		}
+		if len(issues) > 0 {
+			fmt.Printf("✅ Zone file is valid for '%s' (%d warning(s)).\n", zone, len(issues))
+			return
+		}
+		fmt.Printf("✅ Zone file is valid for '%s'.\n", zone)
+	},
+}
+
+func init() {
+	zoneValidateCmd.Flags().StringVarP(&validateFile, "file", "f", "", "Zone file to validate, or - to read it from stdin (required)")
+	if err := zoneValidateCmd.MarkFlagRequired("file"); err != nil {
+		panic(err)
+	}
+	zoneCmd.AddCommand(zoneValidateCmd)
+	rootCmd.AddCommand(zoneCmd)
+}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"net/url"
 	"os"
@@ -17,10 +16,7 @@ import (
 
 type sprinter func(a ...interface{}) string
 
-var (
-	includeAvailableKeys bool
-	optionsJSON          bool
-)
+var includeAvailableKeys bool
 
 var getZoneOptionsCmd = &cobra.Command{
 	Use:     "get-options [zone]",
@@ -30,102 +26,100 @@ var getZoneOptionsCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		zone := args[0]
 
-		// Colors & styles to match existing CLI look-and-feel
-		bold := color.New(color.Bold).SprintFunc()
-		blue := color.New(color.FgBlue).SprintFunc()
-		green := color.New(color.FgGreen).SprintFunc()
-		yellow := color.New(color.FgYellow).SprintFunc()
-		red := color.New(color.FgRed).SprintFunc()
-		gray := color.New(color.FgHiBlack).SprintFunc()
-
 		q := url.Values{
 			"zone":                         {zone},
 			"includeAvailableTsigKeyNames": {strconv.FormatBool(includeAvailableKeys)},
 		}
-		_, respObj, err := api.New().GetJSON("/api/zones/options/get", q)
+		_, respObj, err := api.New().GetJSON(cmd.Context(), "/api/zones/options/get", q)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "%s %v\n", red("❌"), err)
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
 			os.Exit(1)
 		}
 
-		if optionsJSON {
-			raw, _ := json.MarshalIndent(respObj, "", "  ")
-			fmt.Println(string(raw))
-			return
-		}
-
-		// Header
-		fmt.Printf("%s %s\n", bold("Zone:"), blue(str(respObj["name"])))
-		fmt.Printf("%s %s\n", bold("Type:"), str(respObj["type"]))
-		fmt.Printf("%s %s\n", bold("DNSSEC:"), str(respObj["dnssecStatus"]))
-		fmt.Printf("%s %s\n", bold("Status:"), onOff(!toBool(respObj["disabled"]), green, red)) // Enabled/Disabled
-		if v, ok := respObj["internal"]; ok {
-			fmt.Printf("%s %s\n", bold("Scope:"), boolWord(v, blue, gray, "Internal", "External"))
-		}
-		if v := strOrEmpty(respObj["catalog"]); v != "" {
-			fmt.Printf("%s %s\n", bold("Catalog:"), v)
-		}
-		fmt.Println()
-
-		// Notify status
-		if v, ok := respObj["notifyFailed"].(bool); ok {
-			fmt.Printf("%s %s\n", bold("Notify Failed:"), boolColor(v, red, green))
-		}
-		printStringSlice("Notify Failed For", respObj["notifyFailedFor"], gray, 2)
-		fmt.Println()
-
-		// Overrides & access
-		printBool("Override Catalog Query Access", respObj["overrideCatalogQueryAccess"], green, yellow)
-		printBool("Override Catalog Zone Transfer", respObj["overrideCatalogZoneTransfer"], green, yellow)
-		printBool("Override Catalog Notify", respObj["overrideCatalogNotify"], green, yellow)
-		fmt.Println()
-
-		// Query access
-		fmt.Printf("%s %s\n", bold("Query Access:"), str(respObj["queryAccess"]))
-		printStringSlice("Query Access ACL", respObj["queryAccessNetworkACL"], gray, 2)
-		fmt.Println()
-
-		// Zone transfer
-		fmt.Printf("%s %s\n", bold("Zone Transfer:"), str(respObj["zoneTransfer"]))
-		printStringSlice("Zone Transfer ACL", respObj["zoneTransferNetworkACL"], gray, 2)
-		printStringSlice("Zone Transfer TSIG Keys", respObj["zoneTransferTsigKeyNames"], gray, 2)
-		fmt.Println()
+		render(respObj, func() { printZoneOptions(respObj) })
+	},
+}
 
-		// Notify
-		fmt.Printf("%s %s\n", bold("Notify:"), str(respObj["notify"]))
-		printStringSlice("Notify Name Servers", respObj["notifyNameServers"], gray, 2)
-		fmt.Println()
+// printZoneOptions prints the response of /api/zones/options/get.
+func printZoneOptions(respObj map[string]interface{}) {
+	// Colors & styles to match existing CLI look-and-feel
+	bold := color.New(color.Bold).SprintFunc()
+	blue := color.New(color.FgBlue).SprintFunc()
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	gray := color.New(color.FgHiBlack).SprintFunc()
+
+	// Header
+	fmt.Printf("%s %s\n", bold("Zone:"), blue(str(respObj["name"])))
+	fmt.Printf("%s %s\n", bold("Type:"), str(respObj["type"]))
+	fmt.Printf("%s %s\n", bold("DNSSEC:"), str(respObj["dnssecStatus"]))
+	fmt.Printf("%s %s\n", bold("Status:"), onOff(!toBool(respObj["disabled"]), green, red)) // Enabled/Disabled
+	if v, ok := respObj["internal"]; ok {
+		fmt.Printf("%s %s\n", bold("Scope:"), boolWord(v, blue, gray, "Internal", "External"))
+	}
+	if v := strOrEmpty(respObj["catalog"]); v != "" {
+		fmt.Printf("%s %s\n", bold("Catalog:"), v)
+	}
+	fmt.Println()
 
-		// Update policy
-		fmt.Printf("%s %s\n", bold("Update Policy:"), str(respObj["update"]))
-		printStringSlice("Update Network ACL", respObj["updateNetworkACL"], gray, 2)
-
-		// Update Security Policies
-		if usp, ok := respObj["updateSecurityPolicies"].([]interface{}); ok {
-			fmt.Println(bold("Update Security Policies:"))
-			if len(usp) == 0 {
-				fmt.Println("  (none)")
-			} else {
-				for _, row := range usp {
-					m, _ := row.(map[string]interface{})
-					tn := str(m["tsigKeyName"])
-					dom := str(m["domain"])
-					types := sliceToString(m["allowedTypes"])
-					fmt.Printf("  TSIG: %s  Domain: %s  Types: %s\n", blue(tn), blue(dom), types)
-				}
+	// Notify status
+	if v, ok := respObj["notifyFailed"].(bool); ok {
+		fmt.Printf("%s %s\n", bold("Notify Failed:"), boolColor(v, red, green))
+	}
+	printStringSlice("Notify Failed For", respObj["notifyFailedFor"], gray, 2)
+	fmt.Println()
+
+	// Overrides & access
+	printBool("Override Catalog Query Access", respObj["overrideCatalogQueryAccess"], green, yellow)
+	printBool("Override Catalog Zone Transfer", respObj["overrideCatalogZoneTransfer"], green, yellow)
+	printBool("Override Catalog Notify", respObj["overrideCatalogNotify"], green, yellow)
+	fmt.Println()
+
+	// Query access
+	fmt.Printf("%s %s\n", bold("Query Access:"), str(respObj["queryAccess"]))
+	printStringSlice("Query Access ACL", respObj["queryAccessNetworkACL"], gray, 2)
+	fmt.Println()
+
+	// Zone transfer
+	fmt.Printf("%s %s\n", bold("Zone Transfer:"), str(respObj["zoneTransfer"]))
+	printStringSlice("Zone Transfer ACL", respObj["zoneTransferNetworkACL"], gray, 2)
+	printStringSlice("Zone Transfer TSIG Keys", respObj["zoneTransferTsigKeyNames"], gray, 2)
+	fmt.Println()
+
+	// Notify
+	fmt.Printf("%s %s\n", bold("Notify:"), str(respObj["notify"]))
+	printStringSlice("Notify Name Servers", respObj["notifyNameServers"], gray, 2)
+	fmt.Println()
+
+	// Update policy
+	fmt.Printf("%s %s\n", bold("Update Policy:"), str(respObj["update"]))
+	printStringSlice("Update Network ACL", respObj["updateNetworkACL"], gray, 2)
+
+	// Update Security Policies
+	if usp, ok := respObj["updateSecurityPolicies"].([]interface{}); ok {
+		fmt.Println(bold("Update Security Policies:"))
+		if len(usp) == 0 {
+			fmt.Println("  (none)")
+		} else {
+			for _, row := range usp {
+				m, _ := row.(map[string]interface{})
+				tn := str(m["tsigKeyName"])
+				dom := str(m["domain"])
+				types := sliceToString(m["allowedTypes"])
+				fmt.Printf("  TSIG: %s  Domain: %s  Types: %s\n", blue(tn), blue(dom), types)
 			}
-			fmt.Println()
 		}
+		fmt.Println()
+	}
 
-		// Available choices (catalogs & TSIG keys)
-		printStringSlice("Available Catalog Zones", respObj["availableCatalogZoneNames"], gray, 0)
-		printStringSlice("Available TSIG Keys", respObj["availableTsigKeyNames"], gray, 0)
-	},
+	// Available choices (catalogs & TSIG keys)
+	printStringSlice("Available Catalog Zones", respObj["availableCatalogZoneNames"], gray, 0)
+	printStringSlice("Available TSIG Keys", respObj["availableTsigKeyNames"], gray, 0)
 }
 
 func init() {
 	getZoneOptionsCmd.Flags().BoolVar(&includeAvailableKeys, "include-available-keys", true, "Include available TSIG key names")
-	getZoneOptionsCmd.Flags().BoolVar(&optionsJSON, "json", false, "Print raw JSON response")
 	rootCmd.AddCommand(getZoneOptionsCmd)
 }
 
@@ -2,10 +2,7 @@
 package cmd
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/url"
 	"os"
 	"strings"
@@ -44,41 +41,12 @@ var setZoneOptionsCmd = &cobra.Command{
 
 		// 1) Start with payload from JSON (file|stdin) if provided.
 		// We'll treat it as a generic map and then fold into query params.
-		var base map[string]interface{} = map[string]interface{}{}
-
-		if optionsDataFile != "" && optionsStdin {
-			fmt.Fprintln(os.Stderr, red("❌ --data-file and --stdin are mutually exclusive"))
+		base, err := readJSONInput(optionsDataFile, optionsStdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s %v\n", red("❌"), err)
 			os.Exit(1)
 		}
 
-		if optionsDataFile != "" {
-			body, err := os.ReadFile(optionsDataFile)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "%s failed to read %s: %v\n", red("❌"), optionsDataFile, err)
-				os.Exit(1)
-			}
-			if len(bytes.TrimSpace(body)) > 0 {
-				if err := json.Unmarshal(body, &base); err != nil {
-					fmt.Fprintf(os.Stderr, "%s invalid JSON in %s: %v\n", red("❌"), optionsDataFile, err)
-					os.Exit(1)
-				}
-			}
-		} else if optionsStdin {
-			body, err := io.ReadAll(os.Stdin)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "%s failed to read stdin: %v\n", red("❌"), err)
-				os.Exit(1)
-			}
-			if len(bytes.TrimSpace(body)) == 0 {
-				fmt.Fprintln(os.Stderr, red("❌ no data received on stdin"))
-				os.Exit(1)
-			}
-			if err := json.Unmarshal(body, &base); err != nil {
-				fmt.Fprintf(os.Stderr, "%s invalid JSON on stdin: %v\n", red("❌"), err)
-				os.Exit(1)
-			}
-		}
-
 		// 2) Build query params from base payload first
 		q := url.Values{}
 		q.Set("zone", zone)
@@ -86,21 +54,7 @@ var setZoneOptionsCmd = &cobra.Command{
 		// Helper to set a key from "base" map if present
 		setFromBase := func(key string) {
 			if v, ok := base[key]; ok {
-				switch vv := v.(type) {
-				case []interface{}:
-					// join arrays by comma
-					q.Set(key, joinInterfaceCSV(vv))
-				case []string:
-					q.Set(key, strings.Join(vv, ","))
-				case bool:
-					if vv {
-						q.Set(key, "true")
-					} else {
-						q.Set(key, "false")
-					}
-				default:
-					q.Set(key, fmt.Sprintf("%v", vv))
-				}
+				q.Set(key, queryValue(v))
 			}
 		}
 
@@ -143,12 +97,15 @@ var setZoneOptionsCmd = &cobra.Command{
 		}
 
 		// 4) Call API with query params (GET, per API expectation)
-		if _, _, err := api.New().GetJSON("/api/zones/options/set", q); err != nil {
+		_, response, err := api.New().GetJSON(cmd.Context(), "/api/zones/options/set", q)
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "%s %v\n", red("❌"), err)
 			os.Exit(1)
 		}
 
-		fmt.Printf("%s %s %s\n", green("✅"), bold("Zone options updated for:"), zone)
+		render(response, func() {
+			fmt.Printf("%s %s %s\n", green("✅"), bold("Zone options updated for:"), zone)
+		})
 	},
 }
 
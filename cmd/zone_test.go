@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestZoneFileIssuesCombinesParseAndValidation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "example.com.zone")
+	src := "$TTL 60\nwww CNAME web\nwww A 192.0.2.1\nbad A 192.0.2.300\n"
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := zoneFileIssues(path, "example.com", []byte(src))
+	if err != nil {
+		t.Fatalf("zoneFileIssues: %v", err)
+	}
+	var got []string
+	for _, i := range issues {
+		got = append(got, i.String())
+	}
+	want := []string{
+		"line 4: A record field 1",
+		"line 3: www.example.com. has a CNAME record",
+		"zone apex example.com. has no NS records",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("issues = %q, want %d", got, len(want))
+	}
+	for i := range want {
+		if !strings.HasPrefix(got[i], want[i]) {
+			t.Errorf("issue %d = %q, want prefix %q", i, got[i], want[i])
+		}
+	}
+	if errs := printZoneFileIssues(issues); errs != 2 {
+		t.Errorf("printZoneFileIssues counted %d errors, want 2", errs)
+	}
+}
+
+func TestZoneFileIssuesFromStdinData(t *testing.T) {
+	issues, err := zoneFileIssues("-", "example.com", []byte("$TTL 60\n@ NS ns1\nwww A 192.0.2.1\n"))
+	if err != nil {
+		t.Fatalf("zoneFileIssues: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("issues = %v, want none", issues)
+	}
+}
+
+func TestParseZoneDataUsesDataAndFileDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "hosts.inc"), []byte("www A 192.0.2.1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// The file on disk is not read again: data is what was read before.
+	path := filepath.Join(dir, "example.com.zone")
+	if err := os.WriteFile(path, []byte("changed A 192.0.2.9\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	recs, err := parseZoneData(path, "example.com", []byte("$TTL 60\n$INCLUDE hosts.inc\n"))
+	if err != nil {
+		t.Fatalf("parseZoneData: %v", err)
+	}
+	if len(recs) != 1 || recs[0].Name != "www.example.com." {
+		t.Errorf("records = %+v", recs)
+	}
+}
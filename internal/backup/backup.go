@@ -0,0 +1,214 @@
+// Package backup knows the layout of Technitium DNS Server backup archives:
+// which categories of data a backup can hold and which files in the zip
+// belong to each.
+package backup
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Categories are the categories of data the server's backup and restore APIs
+// take as flags, in the order the API documents them.
+var Categories = []string{
+	"blockLists",
+	"logs",
+	"scopes",
+	"stats",
+	"zones",
+	"allowedZones",
+	"blockedZones",
+	"dnsSettings",
+	"logSettings",
+	"authConfig",
+}
+
+// Files that are neither in a category nor unexpected.
+const (
+	CategoryApps  = "apps"  // installed DNS apps
+	CategoryOther = "other" // anything else
+)
+
+// categoryDirs maps the folders of a backup to their category.
+var categoryDirs = map[string]string{
+	"blocklists": "blockLists",
+	"logs":       "logs",
+	"scopes":     "scopes",
+	"stats":      "stats",
+	"zones":      "zones",
+	"apps":       CategoryApps,
+}
+
+// categoryFiles maps the top-level files of a backup to their category.
+var categoryFiles = map[string]string{
+	"allowed.config":    "allowedZones",
+	"blocked.config":    "blockedZones",
+	"dns.config":        "dnsSettings",
+	"webservice.config": "dnsSettings",
+	"log.config":        "logSettings",
+	"auth.config":       "authConfig",
+}
+
+// Select returns the categories chosen by include and exclude, in the order
+// of Categories: those in include, or all when include is empty, less those
+// in exclude. Names are matched without regard to case.
+func Select(include, exclude []string) ([]string, error) {
+	chosen := map[string]bool{}
+	mark := func(names []string, on bool) error {
+		for _, n := range names {
+			c, ok := lookup(n)
+			if !ok {
+				return fmt.Errorf("unknown backup category %q (use %s)", n, strings.Join(Categories, ", "))
+			}
+			chosen[c] = on
+		}
+		return nil
+	}
+	if len(include) == 0 {
+		for _, c := range Categories {
+			chosen[c] = true
+		}
+	} else if err := mark(include, true); err != nil {
+		return nil, err
+	}
+	if err := mark(exclude, false); err != nil {
+		return nil, err
+	}
+
+	var out []string
+	for _, c := range Categories {
+		if chosen[c] {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func lookup(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	for _, c := range Categories {
+		if strings.EqualFold(c, name) {
+			return c, true
+		}
+	}
+	return "", false
+}
+
+// CategoryOf returns the category the zip entry name belongs to: one of
+// Categories, CategoryApps or CategoryOther.
+func CategoryOf(name string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	if dir, _, ok := strings.Cut(name, "/"); ok {
+		if c, ok := categoryDirs[strings.ToLower(dir)]; ok {
+			return c
+		}
+		return CategoryOther
+	}
+	if c, ok := categoryFiles[strings.ToLower(name)]; ok {
+		return c
+	}
+	// TLS certificates referenced by the DNS and web service settings.
+	switch strings.ToLower(path.Ext(name)) {
+	case ".pfx", ".p12":
+		return "dnsSettings"
+	}
+	return CategoryOther
+}
+
+// Entry is a file in a backup archive.
+type Entry struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Size     uint64 `json:"size"`
+}
+
+// Summary is the files of one category in a backup archive.
+type Summary struct {
+	Category string `json:"category"`
+	Files    int    `json:"files"`
+	Size     uint64 `json:"size"`
+}
+
+// Archive is the table of contents of a backup archive.
+type Archive struct {
+	Entries []Entry
+}
+
+// Inspect reads the table of contents of the zip file at path. It fails
+// when the file is not a zip archive; use Validate to check that the zip is
+// a backup.
+func Inspect(path string) (*Archive, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	defer r.Close()
+
+	a := &Archive{}
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		a.Entries = append(a.Entries, Entry{Name: f.Name, Category: CategoryOf(f.Name), Size: f.UncompressedSize64})
+	}
+	return a, nil
+}
+
+// Validate reports why the archive does not look like a server backup: it
+// is empty, has a file outside the backup folder, or holds nothing in any
+// backup category.
+func (a *Archive) Validate() error {
+	if len(a.Entries) == 0 {
+		return errors.New("the archive is empty")
+	}
+	known := false
+	for _, e := range a.Entries {
+		name := strings.ReplaceAll(e.Name, "\\", "/")
+		if path.IsAbs(name) || strings.HasPrefix(path.Clean(name), "../") {
+			return fmt.Errorf("the archive has a file outside the backup: %s", e.Name)
+		}
+		if e.Category != CategoryOther && e.Category != CategoryApps {
+			known = true
+		}
+	}
+	if !known {
+		return errors.New("the archive holds no Technitium DNS Server backup data")
+	}
+	return nil
+}
+
+// Summaries returns the number and size of the archive's files by
+// category, for the categories that have files: Categories first, in
+// order, then CategoryApps and CategoryOther.
+func (a *Archive) Summaries() []Summary {
+	byCat := map[string]*Summary{}
+	for _, e := range a.Entries {
+		s, ok := byCat[e.Category]
+		if !ok {
+			s = &Summary{Category: e.Category}
+			byCat[e.Category] = s
+		}
+		s.Files++
+		s.Size += e.Size
+	}
+	var out []Summary
+	for _, c := range append(append([]string(nil), Categories...), CategoryApps, CategoryOther) {
+		if s, ok := byCat[c]; ok {
+			out = append(out, *s)
+		}
+	}
+	return out
+}
+
+// Has reports whether the archive has files of category.
+func (a *Archive) Has(category string) bool {
+	for _, e := range a.Entries {
+		if e.Category == category {
+			return true
+		}
+	}
+	return false
+}
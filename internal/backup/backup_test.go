@@ -0,0 +1,119 @@
+package backup
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeZip writes a zip with the named files, each holding its own name.
+func writeZip(t *testing.T, names ...string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "backup.zip")
+	f, err := os.Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(f)
+	for _, n := range names {
+		fw, err := w.Create(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write([]byte(n))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	return p
+}
+
+func TestSelect(t *testing.T) {
+	tests := []struct {
+		include, exclude []string
+		want             []string
+	}{
+		{nil, nil, Categories},
+		{[]string{"ZONES", "dnsSettings"}, nil, []string{"zones", "dnsSettings"}},
+		{nil, []string{"logs", "stats"}, []string{"blockLists", "scopes", "zones", "allowedZones", "blockedZones", "dnsSettings", "logSettings", "authConfig"}},
+		{[]string{"zones", "logs"}, []string{"logs"}, []string{"zones"}},
+	}
+	for _, tt := range tests {
+		got, err := Select(tt.include, tt.exclude)
+		if err != nil {
+			t.Errorf("Select(%v, %v): %v", tt.include, tt.exclude, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Select(%v, %v) = %v, want %v", tt.include, tt.exclude, got, tt.want)
+		}
+	}
+	if _, err := Select([]string{"zone"}, nil); err == nil || !strings.Contains(err.Error(), "zone") {
+		t.Errorf("unknown category: err = %v", err)
+	}
+}
+
+func TestCategoryOf(t *testing.T) {
+	for name, want := range map[string]string{
+		"zones/example.com.zone": "zones",
+		"blocklists/abc":         "blockLists",
+		"dns.config":             "dnsSettings",
+		"cert.pfx":               "dnsSettings",
+		"allowed.config":         "allowedZones",
+		"auth.config":            "authConfig",
+		"apps/Geo/app.dll":       CategoryApps,
+		"readme.txt":             CategoryOther,
+		"other/zones/x":          CategoryOther,
+	} {
+		if got := CategoryOf(name); got != want {
+			t.Errorf("CategoryOf(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestInspect(t *testing.T) {
+	a, err := Inspect(writeZip(t, "dns.config", "zones/a.zone", "zones/b.zone", "apps/x/app.dll"))
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if err := a.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+	want := []Summary{
+		{Category: "zones", Files: 2, Size: uint64(len("zones/a.zone") + len("zones/b.zone"))},
+		{Category: "dnsSettings", Files: 1, Size: uint64(len("dns.config"))},
+		{Category: CategoryApps, Files: 1, Size: uint64(len("apps/x/app.dll"))},
+	}
+	if got := a.Summaries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Summaries = %+v, want %+v", got, want)
+	}
+	if !a.Has("zones") || a.Has("logs") {
+		t.Errorf("Has is wrong for %+v", a.Entries)
+	}
+}
+
+func TestValidateRejects(t *testing.T) {
+	for name, files := range map[string][]string{
+		"empty":     nil,
+		"unrelated": {"photo.jpg", "notes/todo.txt"},
+		"escaping":  {"dns.config", "../etc/passwd"},
+	} {
+		a, err := Inspect(writeZip(t, files...))
+		if err != nil {
+			t.Fatalf("%s: Inspect: %v", name, err)
+		}
+		if err := a.Validate(); err == nil {
+			t.Errorf("%s: Validate accepted %v", name, files)
+		}
+	}
+
+	notZip := filepath.Join(t.TempDir(), "backup.zip")
+	os.WriteFile(notZip, []byte("<html>login</html>"), 0o600)
+	if _, err := Inspect(notZip); err == nil {
+		t.Error("Inspect accepted a file that is not a zip")
+	}
+}
@@ -0,0 +1,282 @@
+package backup
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Backups written to a directory are named NamePrefix, the local time in
+// NameLayout, then NameSuffix, so that they sort by age. A second backup
+// taken in the same second gets "-2" before NameSuffix, and so on.
+const (
+	NamePrefix = "tdns-backup-"
+	NameLayout = "20060102-150405"
+	NameSuffix = ".zip"
+)
+
+// ChecksumSuffix is appended to an archive's path to name its SHA-256
+// sidecar, which is in the format of sha256sum.
+const ChecksumSuffix = ".sha256"
+
+// Name returns the file name of a backup taken at t.
+func Name(t time.Time) string {
+	return NamePrefix + t.Format(NameLayout) + NameSuffix
+}
+
+// seqName is Name for the seq'th backup taken at t.
+func seqName(t time.Time, seq int) string {
+	if seq <= 1 {
+		return Name(t)
+	}
+	return fmt.Sprintf("%s%s-%d%s", NamePrefix, t.Format(NameLayout), seq, NameSuffix)
+}
+
+// ParseName returns the time a backup named by Name was taken.
+func ParseName(name string) (time.Time, bool) {
+	t, _, ok := parseName(name)
+	return t, ok
+}
+
+// parseName is ParseName that also returns the sequence number of the
+// backup within its second.
+func parseName(name string) (time.Time, int, bool) {
+	stamp, ok := strings.CutPrefix(name, NamePrefix)
+	if !ok {
+		return time.Time{}, 0, false
+	}
+	if stamp, ok = strings.CutSuffix(stamp, NameSuffix); !ok {
+		return time.Time{}, 0, false
+	}
+	seq := 1
+	if len(stamp) > len(NameLayout) {
+		n, err := strconv.Atoi(strings.TrimPrefix(stamp[len(NameLayout):], "-"))
+		if err != nil || n < 2 || stamp[len(NameLayout)] != '-' {
+			return time.Time{}, 0, false
+		}
+		stamp, seq = stamp[:len(NameLayout)], n
+	}
+	t, err := time.ParseInLocation(NameLayout, stamp, time.Local)
+	return t, seq, err == nil
+}
+
+// WriteFile writes the data read from r to path atomically: to a temporary
+// file in the same directory that is synced and renamed over path once
+// complete, so path never holds a partial archive. Backups hold credentials,
+// so the file is only readable by its owner. It returns the SHA-256 of the
+// data in hex.
+func WriteFile(path string, r io.Reader) (string, error) {
+	tmp, sum, err := writeTemp(path, r)
+	if err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	return sum, nil
+}
+
+// WriteNew is WriteFile for a backup taken at t, saved in dir under Name.
+// It never replaces an existing backup: when one taken in the same second
+// is already there, the next sequence number is used. It returns the path
+// written and the SHA-256 of the data in hex.
+func WriteNew(dir string, t time.Time, r io.Reader) (string, string, error) {
+	tmp, sum, err := writeTemp(filepath.Join(dir, Name(t)), r)
+	if err != nil {
+		return "", "", err
+	}
+	defer os.Remove(tmp)
+	for seq := 1; ; seq++ {
+		path := filepath.Join(dir, seqName(t, seq))
+		// A hard link fails when path exists, where a rename would replace
+		// it.
+		err := os.Link(tmp, path)
+		if err == nil {
+			return path, sum, nil
+		}
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		// No hard links on this file system: check, then rename.
+		if _, statErr := os.Lstat(path); statErr == nil {
+			continue
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			return "", "", err
+		}
+		return path, sum, nil
+	}
+}
+
+// writeTemp writes the data read from r to a synced temporary file next to
+// path, and returns its name and the SHA-256 of the data in hex.
+func writeTemp(path string, r io.Reader) (string, string, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return "", "", err
+	}
+	done := false
+	defer func() {
+		if !done {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, h), r); err != nil {
+		return "", "", err
+	}
+	if err := tmp.Sync(); err != nil {
+		return "", "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", "", err
+	}
+	done = true
+	return tmp.Name(), hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// WriteChecksum writes the sidecar of the archive at path with its SHA-256
+// sum, in hex.
+func WriteChecksum(path, sum string) error {
+	line := fmt.Sprintf("%s  %s\n", sum, filepath.Base(path))
+	_, err := WriteFile(path+ChecksumSuffix, strings.NewReader(line))
+	return err
+}
+
+// ErrNoChecksum is returned by VerifyChecksum when the archive has no
+// sidecar.
+var ErrNoChecksum = errors.New("no checksum file")
+
+// VerifyChecksum checks the archive at path against its sidecar.
+func VerifyChecksum(path string) error {
+	f, err := os.Open(path + ChecksumSuffix)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNoChecksum
+	}
+	if err != nil {
+		return err
+	}
+	line, err := bufio.NewReader(f).ReadString('\n')
+	f.Close()
+	if err != nil && err != io.EOF {
+		return err
+	}
+	want, _, _ := strings.Cut(strings.TrimSpace(line), " ")
+	if len(want) != sha256.Size*2 {
+		return fmt.Errorf("%s%s is not a SHA-256 checksum file", path, ChecksumSuffix)
+	}
+
+	data, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer data.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, data); err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(got, want) {
+		return fmt.Errorf("checksum mismatch: %s has SHA-256 %s, %s%s says %s", path, got, path, ChecksumSuffix, want)
+	}
+	return nil
+}
+
+// Retention is how many backups Prune keeps: the newest backup of each of
+// the last Daily days, Weekly ISO weeks and Monthly months that have
+// backups. A backup kept by any rule is kept.
+type Retention struct {
+	Daily   int
+	Weekly  int
+	Monthly int
+}
+
+// IsZero reports whether r keeps everything, i.e. sets no limits.
+func (r Retention) IsZero() bool {
+	return r.Daily <= 0 && r.Weekly <= 0 && r.Monthly <= 0
+}
+
+// Prune deletes the backups in dir, and their sidecars, that policy doesn't
+// keep. Only files named by Name are considered, and the newest backup is
+// always kept. It returns the paths of the deleted backups, oldest first.
+func Prune(dir string, policy Retention) ([]string, error) {
+	if policy.IsZero() {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	type archive struct {
+		name  string
+		taken time.Time
+		seq   int
+	}
+	var archives []archive
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		if t, seq, ok := parseName(e.Name()); ok {
+			archives = append(archives, archive{e.Name(), t, seq})
+		}
+	}
+	if len(archives) == 0 {
+		return nil, nil
+	}
+	sort.Slice(archives, func(i, j int) bool {
+		if !archives[i].taken.Equal(archives[j].taken) {
+			return archives[i].taken.After(archives[j].taken)
+		}
+		return archives[i].seq > archives[j].seq
+	})
+
+	keep := map[string]bool{archives[0].name: true}
+	rule := func(n int, period func(time.Time) string) {
+		last := ""
+		for _, a := range archives {
+			if n <= 0 {
+				return
+			}
+			if p := period(a.taken); p != last {
+				keep[a.name] = true
+				last = p
+				n--
+			}
+		}
+	}
+	rule(policy.Daily, func(t time.Time) string { return t.Format("2006-01-02") })
+	rule(policy.Weekly, func(t time.Time) string {
+		y, w := t.ISOWeek()
+		return fmt.Sprintf("%d-%02d", y, w)
+	})
+	rule(policy.Monthly, func(t time.Time) string { return t.Format("2006-01") })
+
+	var removed []string
+	for i := len(archives) - 1; i >= 0; i-- {
+		a := archives[i]
+		if keep[a.name] {
+			continue
+		}
+		p := filepath.Join(dir, a.name)
+		if err := os.Remove(p); err != nil {
+			return removed, err
+		}
+		if err := os.Remove(p + ChecksumSuffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return removed, err
+		}
+		removed = append(removed, p)
+	}
+	return removed, nil
+}
@@ -0,0 +1,171 @@
+package backup
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestName(t *testing.T) {
+	at := time.Date(2024, 3, 9, 4, 5, 6, 0, time.Local)
+	name := Name(at)
+	if name != "tdns-backup-20240309-040506.zip" {
+		t.Errorf("Name = %q", name)
+	}
+	if got, ok := ParseName(name); !ok || !got.Equal(at) {
+		t.Errorf("ParseName(%q) = %v, %v", name, got, ok)
+	}
+	if got, ok := ParseName("tdns-backup-20240309-040506-2.zip"); !ok || !got.Equal(at) {
+		t.Errorf("ParseName of a numbered backup = %v, %v", got, ok)
+	}
+	for _, bad := range []string{"tdns-backup-2024.zip", "backup-20240309-040506.zip", "tdns-backup-20240309-040506.zip.sha256",
+		"tdns-backup-20240309-040506-1.zip", "tdns-backup-20240309-040506-x.zip", "tdns-backup-20240309-0405062.zip"} {
+		if _, ok := ParseName(bad); ok {
+			t.Errorf("ParseName(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestWriteFileAndChecksum(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "b.zip")
+	sum, err := WriteFile(p, strings.NewReader("hello"))
+	if err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if sum != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+		t.Errorf("sum = %s", sum)
+	}
+	if err := VerifyChecksum(p); !errors.Is(err, ErrNoChecksum) {
+		t.Errorf("VerifyChecksum without sidecar: %v", err)
+	}
+	if err := WriteChecksum(p, sum); err != nil {
+		t.Fatalf("WriteChecksum: %v", err)
+	}
+	if data, _ := os.ReadFile(p + ChecksumSuffix); string(data) != sum+"  b.zip\n" {
+		t.Errorf("sidecar = %q", data)
+	}
+	if err := VerifyChecksum(p); err != nil {
+		t.Errorf("VerifyChecksum: %v", err)
+	}
+
+	os.WriteFile(p, []byte("tampered"), 0o600)
+	if err := VerifyChecksum(p); err == nil || !strings.Contains(err.Error(), "mismatch") {
+		t.Errorf("VerifyChecksum of a changed file: %v", err)
+	}
+
+	// No temporary files are left behind.
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("dir has %d entries, want the archive and its sidecar", len(entries))
+	}
+}
+
+func TestWriteFileKeepsOldFileOnError(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "b.zip")
+	os.WriteFile(p, []byte("old"), 0o600)
+	if _, err := WriteFile(p, failingReader{}); err == nil {
+		t.Fatal("WriteFile succeeded")
+	}
+	if data, _ := os.ReadFile(p); string(data) != "old" {
+		t.Errorf("file = %q, want the old content", data)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(p)); len(entries) != 1 {
+		t.Errorf("temporary file left behind: %v", entries)
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("connection reset") }
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	// Two backups a day at 01:00 and 13:00 from 2024-01-01 to 2024-03-31.
+	start := time.Date(2024, 1, 1, 1, 0, 0, 0, time.Local)
+	for d := start; d.Before(time.Date(2024, 4, 1, 0, 0, 0, 0, time.Local)); d = d.Add(12 * time.Hour) {
+		p := filepath.Join(dir, Name(d))
+		os.WriteFile(p, nil, 0o600)
+		os.WriteFile(p+ChecksumSuffix, nil, 0o600)
+	}
+	os.WriteFile(filepath.Join(dir, "unrelated.zip"), nil, 0o600)
+
+	removed, err := Prune(dir, Retention{Daily: 3, Weekly: 2, Monthly: 3})
+	if err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if len(removed) == 0 {
+		t.Fatal("nothing removed")
+	}
+
+	var kept []string
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if t, ok := ParseName(e.Name()); ok {
+			kept = append(kept, t.Format("01-02 15"))
+		}
+	}
+	sort.Strings(kept)
+	want := []string{
+		"01-31 13", // January
+		"02-29 13", // February
+		"03-24 13", // the week before last (ISO weeks start on Monday)
+		"03-29 13", // daily
+		"03-30 13", // daily
+		"03-31 13", // daily, this week and this month
+	}
+	if !reflect.DeepEqual(kept, want) {
+		t.Errorf("kept %v, want %v", kept, want)
+	}
+	for _, p := range removed {
+		if _, err := os.Stat(p + ChecksumSuffix); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("sidecar of %s not removed", p)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "unrelated.zip")); err != nil {
+		t.Errorf("unrelated file removed: %v", err)
+	}
+}
+
+func TestPruneWithoutPolicyKeepsAll(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, Name(time.Now().Add(-48*time.Hour))), nil, 0o600)
+	os.WriteFile(filepath.Join(dir, Name(time.Now())), nil, 0o600)
+	if removed, err := Prune(dir, Retention{}); err != nil || len(removed) != 0 {
+		t.Errorf("Prune = %v, %v", removed, err)
+	}
+}
+
+func TestWriteNewKeepsBackupOfTheSameSecond(t *testing.T) {
+	dir := t.TempDir()
+	at := time.Date(2024, 3, 9, 4, 5, 6, 0, time.Local)
+	first, _, err := WriteNew(dir, at, strings.NewReader("first"))
+	if err != nil {
+		t.Fatalf("WriteNew: %v", err)
+	}
+	second, _, err := WriteNew(dir, at, strings.NewReader("second"))
+	if err != nil {
+		t.Fatalf("WriteNew: %v", err)
+	}
+	if filepath.Base(first) != "tdns-backup-20240309-040506.zip" || filepath.Base(second) != "tdns-backup-20240309-040506-2.zip" {
+		t.Errorf("paths = %s, %s", first, second)
+	}
+	if data, _ := os.ReadFile(first); string(data) != "first" {
+		t.Errorf("first backup = %q", data)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("dir has %v, want just the two backups", entries)
+	}
+
+	// The numbered backup is the newer one.
+	removed, err := Prune(dir, Retention{Daily: 1})
+	if err != nil || len(removed) != 1 || removed[0] != first {
+		t.Errorf("Prune = %v, %v; want %s removed", removed, err, first)
+	}
+}
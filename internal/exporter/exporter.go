@@ -0,0 +1,250 @@
+// Package exporter serves the state of a Technitium DNS Server as Prometheus
+// metrics. It polls the server's API on an interval and answers scrapes from
+// the latest poll, so scrapes never wait on, or add load to, the server.
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"tdns/pkg/technitium"
+)
+
+// ContentType is the media type of the Prometheus text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Exporter polls a server and serves its metrics over HTTP.
+type Exporter struct {
+	client *technitium.Client
+	now    func() time.Time
+
+	mu          sync.RWMutex
+	metrics     []byte
+	pollErrs    int
+	lastSuccess time.Time
+}
+
+// New returns an Exporter polling the server of client.
+func New(client *technitium.Client) *Exporter {
+	return &Exporter{client: client, now: time.Now}
+}
+
+// snapshot is what one poll read from the server. A field is nil when its
+// API call failed.
+type snapshot struct {
+	version string
+	stats   *technitium.DashboardStats
+	zones   *technitium.ZoneList
+	errs    []error
+}
+
+// Poll reads the server's statistics, zones and version and replaces the
+// metrics served. A failed call leaves its metrics out and sets
+// technitium_up to 0; the error of the first failed call is returned.
+func (e *Exporter) Poll(ctx context.Context) error {
+	start := e.now()
+	var s snapshot
+	var err error
+	if s.version, err = e.client.ServerVersion(ctx); err != nil {
+		s.errs = append(s.errs, err)
+	}
+	if s.stats, err = e.client.GetStats(ctx, technitium.StatsOptions{Type: technitium.StatsLastHour}); err != nil {
+		s.errs = append(s.errs, err)
+	}
+	if s.zones, err = e.client.ListZones(ctx, technitium.ListZonesOptions{}); err != nil {
+		s.errs = append(s.errs, err)
+	}
+	took := e.now().Sub(start)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(s.errs) > 0 {
+		e.pollErrs++
+	} else {
+		e.lastSuccess = e.now()
+	}
+	e.metrics = e.format(s, took)
+	if len(s.errs) > 0 {
+		return s.errs[0]
+	}
+	return nil
+}
+
+// Run polls every interval until ctx is done. Failed polls are passed to
+// onError, which may be nil.
+func (e *Exporter) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := e.Poll(ctx); err != nil && onError != nil && ctx.Err() == nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// ServeHTTP writes the metrics of the latest poll.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.RLock()
+	metrics := e.metrics
+	e.mu.RUnlock()
+	if metrics == nil {
+		http.Error(w, "no poll has completed yet", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.Write(metrics)
+}
+
+// format renders s as metrics. It is called with e.mu held.
+func (e *Exporter) format(s snapshot, took time.Duration) []byte {
+	w := &writer{}
+
+	up := 1.0
+	if len(s.errs) > 0 {
+		up = 0
+	}
+	w.family("technitium_up", "gauge", "Whether the last poll of the server's API succeeded.")
+	w.sample("technitium_up", up)
+
+	if s.version != "" {
+		w.family("technitium_build_info", "gauge", "The server's version, as a label.")
+		w.sample("technitium_build_info", 1, "version", s.version)
+	}
+
+	if s.stats != nil {
+		t := s.stats.Stats
+		w.family("technitium_queries_last_hour", "gauge", "Queries answered in the last hour, by kind of response.")
+		for _, q := range []struct {
+			kind string
+			n    int64
+		}{
+			{"total", t.TotalQueries},
+			{"no_error", t.TotalNoError},
+			{"server_failure", t.TotalServerFailure},
+			{"nx_domain", t.TotalNxDomain},
+			{"refused", t.TotalRefused},
+			{"authoritative", t.TotalAuthoritative},
+			{"recursive", t.TotalRecursive},
+			{"cached", t.TotalCached},
+			{"blocked", t.TotalBlocked},
+			{"dropped", t.TotalDropped},
+		} {
+			w.sample("technitium_queries_last_hour", float64(q.n), "kind", q.kind)
+		}
+		w.gauge("technitium_clients_last_hour", "Clients that queried the server in the last hour.", t.TotalClients)
+		w.gauge("technitium_cache_entries", "Entries in the DNS cache.", t.CachedEntries)
+		w.gauge("technitium_allowed_zones", "Zones in the allowed list.", t.AllowedZones)
+		w.gauge("technitium_blocked_zones", "Zones in the blocked list.", t.BlockedZones)
+		w.gauge("technitium_block_list_zones", "Zones blocked by block list subscriptions.", t.BlockListZones)
+	}
+
+	if s.zones != nil {
+		zones := append([]technitium.Zone(nil), s.zones.Zones...)
+		sort.Slice(zones, func(i, j int) bool { return zones[i].Name < zones[j].Name })
+
+		byType := map[string]int{}
+		for _, z := range zones {
+			byType[z.Type]++
+		}
+		types := make([]string, 0, len(byType))
+		for t := range byType {
+			types = append(types, t)
+		}
+		sort.Strings(types)
+		w.family("technitium_zones", "gauge", "Zones on the server, by type.")
+		for _, t := range types {
+			w.sample("technitium_zones", float64(byType[t]), "type", t)
+		}
+
+		flags := []struct {
+			name, help string
+			set        func(technitium.Zone) bool
+		}{
+			{"technitium_zone_disabled", "Whether the zone is disabled.", func(z technitium.Zone) bool { return z.Disabled }},
+			{"technitium_zone_expired", "Whether the secondary or stub zone has expired.", func(z technitium.Zone) bool { return z.IsExpired }},
+			{"technitium_zone_sync_failed", "Whether the last zone transfer or refresh failed.", func(z technitium.Zone) bool { return z.SyncFailed }},
+			{"technitium_zone_notify_failed", "Whether the last NOTIFY to the zone's secondaries failed.", func(z technitium.Zone) bool { return z.NotifyFailed }},
+		}
+		for _, f := range flags {
+			w.family(f.name, "gauge", f.help)
+			for _, z := range zones {
+				w.sample(f.name, boolValue(f.set(z)), "zone", z.Name, "type", z.Type)
+			}
+		}
+		w.family("technitium_zone_soa_serial", "gauge", "The serial of the zone's SOA record.")
+		for _, z := range zones {
+			if !z.Internal {
+				w.sample("technitium_zone_soa_serial", float64(z.SOASerial), "zone", z.Name, "type", z.Type)
+			}
+		}
+	}
+
+	w.family("technitium_exporter_poll_duration_seconds", "gauge", "How long the last poll of the server's API took.")
+	w.sample("technitium_exporter_poll_duration_seconds", took.Seconds())
+	w.family("technitium_exporter_poll_errors_total", "counter", "Polls of the server's API that failed.")
+	w.sample("technitium_exporter_poll_errors_total", float64(e.pollErrs))
+	if !e.lastSuccess.IsZero() {
+		w.family("technitium_exporter_last_success_timestamp_seconds", "gauge", "When the last successful poll finished, in Unix time.")
+		w.sample("technitium_exporter_last_success_timestamp_seconds", float64(e.lastSuccess.UnixNano())/1e9)
+	}
+	return w.buf.Bytes()
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// writer writes metrics in the Prometheus text exposition format.
+type writer struct {
+	buf bytes.Buffer
+}
+
+// family starts the metric family name.
+func (w *writer) family(name, typ, help string) {
+	fmt.Fprintf(&w.buf, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// sample writes one sample of name; labels are name, value pairs.
+func (w *writer) sample(name string, value float64, labels ...string) {
+	w.buf.WriteString(name)
+	if len(labels) > 0 {
+		w.buf.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				w.buf.WriteByte(',')
+			}
+			fmt.Fprintf(&w.buf, "%s=\"%s\"", labels[i], escapeLabel(labels[i+1]))
+		}
+		w.buf.WriteByte('}')
+	}
+	w.buf.WriteByte(' ')
+	w.buf.WriteString(strconv.FormatFloat(value, 'f', -1, 64))
+	w.buf.WriteByte('\n')
+}
+
+// gauge writes a family with a single unlabelled sample.
+func (w *writer) gauge(name, help string, value int64) {
+	w.family(name, "gauge", help)
+	w.sample(name, float64(value))
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
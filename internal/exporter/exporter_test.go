@@ -0,0 +1,115 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"tdns/pkg/technitium"
+)
+
+// standIn serves the API calls the exporter makes. A path missing from
+// responses fails with an API error.
+func standIn(t *testing.T, responses map[string]string) *technitium.Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			fmt.Fprint(w, `{"status":"error","errorMessage":"not here"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"status":"ok","response":%s}`, body)
+	}))
+	t.Cleanup(srv.Close)
+	return technitium.NewClient(srv.URL, "token")
+}
+
+var okResponses = map[string]string{
+	"/api/settings/get": `{"version":"13.6"}`,
+	"/api/dashboard/stats/get": `{"stats":{"totalQueries":120,"totalNxDomain":8,"totalBlocked":5,"totalCached":60,
+		"totalClients":3,"cachedEntries":900,"blockedZones":2}}`,
+	"/api/zones/list": `{"zones":[
+		{"name":"example.com","type":"Primary","soaSerial":2024050101},
+		{"name":"sec.example","type":"Secondary","soaSerial":7,"isExpired":true,"syncFailed":true},
+		{"name":"0.in-addr.arpa","type":"Primary","internal":true}]}`,
+}
+
+func scrape(t *testing.T, e *Exporter) (int, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	return rec.Code, string(body)
+}
+
+func TestPoll(t *testing.T) {
+	e := New(standIn(t, okResponses))
+	e.now = func() time.Time { return time.Unix(1700000000, 0) }
+	if err := e.Poll(context.Background()); err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+
+	code, out := scrape(t, e)
+	if code != http.StatusOK {
+		t.Fatalf("status = %d", code)
+	}
+	for _, want := range []string{
+		"# TYPE technitium_up gauge\ntechnitium_up 1\n",
+		`technitium_build_info{version="13.6"} 1`,
+		`technitium_queries_last_hour{kind="total"} 120`,
+		`technitium_queries_last_hour{kind="nx_domain"} 8`,
+		`technitium_queries_last_hour{kind="blocked"} 5`,
+		"technitium_cache_entries 900\n",
+		`technitium_zones{type="Primary"} 2`,
+		`technitium_zones{type="Secondary"} 1`,
+		`technitium_zone_expired{zone="sec.example",type="Secondary"} 1`,
+		`technitium_zone_sync_failed{zone="example.com",type="Primary"} 0`,
+		`technitium_zone_soa_serial{zone="example.com",type="Primary"} 2024050101`,
+		"technitium_exporter_poll_errors_total 0\n",
+		"technitium_exporter_last_success_timestamp_seconds 1700000000\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics are missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `zone_soa_serial{zone="0.in-addr.arpa"`) {
+		t.Errorf("internal zone has a serial:\n%s", out)
+	}
+}
+
+func TestPollFailure(t *testing.T) {
+	responses := map[string]string{"/api/settings/get": okResponses["/api/settings/get"]}
+	e := New(standIn(t, responses))
+	if err := e.Poll(context.Background()); err == nil {
+		t.Fatal("Poll succeeded without stats and zones")
+	}
+
+	_, out := scrape(t, e)
+	for _, want := range []string{"technitium_up 0\n", `technitium_build_info{version="13.6"} 1`, "technitium_exporter_poll_errors_total 1\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics are missing %q:\n%s", want, out)
+		}
+	}
+	for _, absent := range []string{"technitium_queries_last_hour", "technitium_zones", "last_success_timestamp"} {
+		if strings.Contains(out, absent) {
+			t.Errorf("metrics have %q after a failed call:\n%s", absent, out)
+		}
+	}
+}
+
+func TestServeBeforePoll(t *testing.T) {
+	if code, _ := scrape(t, New(technitium.NewClient("http://127.0.0.1:0", ""))); code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", code)
+	}
+}
+
+func TestEscapeLabel(t *testing.T) {
+	if got := escapeLabel("a\"b\\c\nd"); got != `a\"b\\c\nd` {
+		t.Errorf("escapeLabel = %s", got)
+	}
+}
@@ -0,0 +1,172 @@
+package zonefile
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// fieldKind describes one field of a record's data.
+type fieldKind int
+
+const (
+	fieldAny     fieldKind = iota // anything, passed through
+	fieldName                     // a domain name, made absolute
+	fieldUint8                    // 0-255
+	fieldUint16                   // 0-65535
+	fieldUint32                   // 0-4294967295
+	fieldTTL                      // seconds, BIND units allowed
+	fieldIPv4                     // an IPv4 address
+	fieldIPv6                     // an IPv6 address
+	fieldString                   // one character-string
+	fieldStrings                  // one or more character-strings (variadic)
+	fieldHex                      // hex, may be split over several fields (variadic)
+	fieldBase64                   // base64, may be split over several fields (variadic)
+	fieldRest                     // free-form remainder (variadic)
+)
+
+// variadic kinds absorb every remaining field.
+func (k fieldKind) variadic() bool {
+	return k == fieldStrings || k == fieldHex || k == fieldBase64 || k == fieldRest
+}
+
+// rdataFields lists the data fields of each supported type in presentation
+// order. A type mapped to nil is accepted with its data passed through as-is.
+var rdataFields = map[string][]fieldKind{
+	"A":          {fieldIPv4},
+	"AAAA":       {fieldIPv6},
+	"NS":         {fieldName},
+	"CNAME":      {fieldName},
+	"DNAME":      {fieldName},
+	"PTR":        {fieldName},
+	"ANAME":      {fieldName},
+	"MX":         {fieldUint16, fieldName},
+	"TXT":        {fieldStrings},
+	"SPF":        {fieldStrings},
+	"RP":         {fieldName, fieldName},
+	"SRV":        {fieldUint16, fieldUint16, fieldUint16, fieldName},
+	"NAPTR":      {fieldUint16, fieldUint16, fieldString, fieldString, fieldString, fieldName},
+	"SOA":        {fieldName, fieldName, fieldUint32, fieldTTL, fieldTTL, fieldTTL, fieldTTL},
+	"CAA":        {fieldUint8, fieldAny, fieldString},
+	"DS":         {fieldUint16, fieldAny, fieldAny, fieldHex},
+	"SSHFP":      {fieldAny, fieldAny, fieldHex},
+	"TLSA":       {fieldAny, fieldAny, fieldAny, fieldHex},
+	"URI":        {fieldUint16, fieldUint16, fieldString},
+	"SVCB":       {fieldUint16, fieldName, fieldRest},
+	"HTTPS":      {fieldUint16, fieldName, fieldRest},
+	"HINFO":      {fieldString, fieldString},
+	"LOC":        {fieldRest},
+	"DNSKEY":     {fieldUint16, fieldUint8, fieldUint8, fieldBase64},
+	"RRSIG":      {fieldRest},
+	"NSEC":       {fieldName, fieldRest},
+	"NSEC3":      {fieldRest},
+	"NSEC3PARAM": {fieldRest},
+	"CDS":        {fieldUint16, fieldAny, fieldAny, fieldHex},
+	"CDNSKEY":    {fieldUint16, fieldUint8, fieldUint8, fieldBase64},
+	"ZONEMD":     {fieldRest},
+}
+
+// requiredFields is the number of fields a record must have: every fixed
+// field, and at least one for a trailing variadic field other than fieldRest.
+func requiredFields(fields []fieldKind) int {
+	n := len(fields)
+	if n > 0 && fields[n-1] == fieldRest {
+		n--
+	}
+	return n
+}
+
+// checkFields validates record data against the type's field kinds.
+func checkFields(rtype string, fields []fieldKind, data []string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	last := fields[len(fields)-1]
+	for i := 0; i < len(data); i++ {
+		kind := last
+		if i < len(fields) {
+			kind = fields[i]
+		}
+		switch kind {
+		case fieldHex, fieldBase64:
+			// The encoded value may be split over the remaining fields.
+			joined := strings.Join(data[i:], "")
+			if err := checkField(kind, joined); err != nil {
+				return fmt.Errorf("%s record field %d: %v", rtype, i+1, err)
+			}
+			return nil
+		case fieldRest:
+			return nil
+		}
+		if err := checkField(kind, data[i]); err != nil {
+			return fmt.Errorf("%s record field %d: %v", rtype, i+1, err)
+		}
+	}
+	return nil
+}
+
+// checkField validates one data field.
+func checkField(kind fieldKind, v string) error {
+	switch kind {
+	case fieldName:
+		return checkName(v)
+	case fieldUint8, fieldUint16, fieldUint32:
+		bits := map[fieldKind]int{fieldUint8: 8, fieldUint16: 16, fieldUint32: 32}[kind]
+		if _, err := strconv.ParseUint(v, 10, bits); err != nil {
+			return fmt.Errorf("%q is not a number between 0 and %d", v, uint64(1)<<bits-1)
+		}
+	case fieldTTL:
+		if _, ok := parseTTL(v); !ok {
+			return fmt.Errorf("%q is not a valid TTL", v)
+		}
+	case fieldIPv4:
+		if ip := net.ParseIP(v); ip == nil || ip.To4() == nil || strings.Contains(v, ":") {
+			return fmt.Errorf("%q is not an IPv4 address", v)
+		}
+	case fieldIPv6:
+		if ip := net.ParseIP(v); ip == nil || !strings.Contains(v, ":") {
+			return fmt.Errorf("%q is not an IPv6 address", v)
+		}
+	case fieldString:
+		if len(v) > 255 {
+			return fmt.Errorf("character-string longer than 255 octets")
+		}
+	case fieldStrings:
+		if len(v) > 255 {
+			return fmt.Errorf("character-string longer than 255 octets (split it into several quoted strings)")
+		}
+	case fieldHex:
+		if _, err := hex.DecodeString(v); err != nil {
+			return fmt.Errorf("invalid hex data")
+		}
+	case fieldBase64:
+		if _, err := base64.StdEncoding.DecodeString(v); err != nil {
+			return fmt.Errorf("invalid base64 data")
+		}
+	}
+	return nil
+}
+
+// checkName does the structural checks of RFC 1035 on an absolute name: at
+// most 255 octets and labels of 1-63 octets.
+func checkName(name string) error {
+	if name == "." {
+		return nil
+	}
+	n := strings.TrimSuffix(name, ".")
+	if len(n) > 253 {
+		return fmt.Errorf("%q is longer than 255 octets", name)
+	}
+	for _, label := range strings.Split(n, ".") {
+		if label == "" {
+			return fmt.Errorf("%q has an empty label", name)
+		}
+		if len(label) > 63 {
+			return fmt.Errorf("label %q is longer than 63 octets", label)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package zonefile
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Issue is a problem Validate found with a set of records. Warnings are
+// suspicious but loadable; anything else would be rejected or misbehave.
+type Issue struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Msg     string `json:"message"`
+	Warning bool   `json:"warning"`
+}
+
+func (i Issue) String() string {
+	if i.Line == 0 {
+		return i.Msg
+	}
+	loc := fmt.Sprintf("line %d", i.Line)
+	if i.File != "" {
+		loc = fmt.Sprintf("%s:%d", i.File, i.Line)
+	}
+	return fmt.Sprintf("%s: %s", loc, i.Msg)
+}
+
+// dnssecTypes may share a name with a CNAME (RFC 4035 section 2.5).
+var dnssecTypes = map[string]bool{"RRSIG": true, "NSEC": true, "NSEC3": true}
+
+// Validate checks records parsed for the zone origin for problems that only
+// show across records: names outside the zone, a CNAME sharing its name with
+// other data or another CNAME, and an apex without NS records. Issues are
+// returned in input order.
+func Validate(records []Record, origin string) []Issue {
+	origin = Fqdn(origin)
+	// Issues are keyed by the index of the record they are about so they
+	// can be put back into input order.
+	type indexed struct {
+		at    int
+		issue Issue
+	}
+	var found []indexed
+	add := func(at int, format string, args ...interface{}) {
+		r := records[at]
+		found = append(found, indexed{at, Issue{File: r.File, Line: r.Line, Msg: fmt.Sprintf(format, args...)}})
+	}
+
+	byName := map[string][]int{}
+	var names []string
+	apexNS := false
+	for i, r := range records {
+		if !InZone(r.Name, origin) {
+			add(i, "%s %s is outside zone %s", r.Name, r.Type, origin)
+			continue
+		}
+		if r.Name == origin && r.Type == "NS" {
+			apexNS = true
+		}
+		if _, ok := byName[r.Name]; !ok {
+			names = append(names, r.Name)
+		}
+		byName[r.Name] = append(byName[r.Name], i)
+	}
+
+	for _, name := range names {
+		cname := -1
+		for _, i := range byName[name] {
+			if records[i].Type != "CNAME" {
+				continue
+			}
+			if cname >= 0 {
+				add(i, "%s has more than one CNAME record (first at line %d)", name, records[cname].Line)
+				continue
+			}
+			cname = i
+		}
+		if cname < 0 {
+			continue
+		}
+		for _, i := range byName[name] {
+			r := records[i]
+			if r.Type == "CNAME" || dnssecTypes[r.Type] {
+				continue
+			}
+			add(i, "%s has a CNAME record (line %d) and other data (%s)", name, records[cname].Line, r.Type)
+		}
+	}
+
+	sort.SliceStable(found, func(i, j int) bool { return found[i].at < found[j].at })
+	issues := make([]Issue, 0, len(found)+1)
+	for _, f := range found {
+		issues = append(issues, f.issue)
+	}
+	if !apexNS && len(records) > 0 {
+		issues = append(issues, Issue{Msg: fmt.Sprintf("zone apex %s has no NS records", origin), Warning: true})
+	}
+	return issues
+}
+
+// InZone reports whether the absolute name is origin or below it.
+func InZone(name, origin string) bool {
+	name, origin = Fqdn(name), Fqdn(origin)
+	return origin == "." || name == origin || strings.HasSuffix(name, "."+origin)
+}
@@ -0,0 +1,68 @@
+package zonefile
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	src := `$TTL 60
+@        NS     ns1
+www      CNAME  web
+www      A      192.0.2.1
+www      RRSIG  A 13 3 60 20300101000000 20200101000000 1234 example.com. abc=
+alias    CNAME  a
+alias    CNAME  b
+stray.example.org. A 192.0.2.2
+`
+	recs, err := Parse(strings.NewReader(src), "example.com", "")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	issues := Validate(recs, "example.com")
+
+	want := []struct {
+		line int
+		msg  string
+	}{
+		{4, "CNAME record (line 3) and other data (A)"},
+		{7, "more than one CNAME record"},
+		{8, "outside zone"},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("got %d issues, want %d: %v", len(issues), len(want), issues)
+	}
+	for i, w := range want {
+		if issues[i].Line != w.line || !strings.Contains(issues[i].Msg, w.msg) || issues[i].Warning {
+			t.Errorf("issue %d = %+v, want error at line %d containing %q", i, issues[i], w.line, w.msg)
+		}
+	}
+}
+
+func TestValidateMissingApexNS(t *testing.T) {
+	recs, err := Parse(strings.NewReader("$TTL 60\nsub NS ns1\nwww A 192.0.2.1\n"), "example.com", "")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	issues := Validate(recs, "example.com")
+	if len(issues) != 1 || !issues[0].Warning || !strings.Contains(issues[0].Msg, "no NS records") {
+		t.Errorf("issues = %v, want a single missing apex NS warning", issues)
+	}
+}
+
+func TestInZone(t *testing.T) {
+	for _, tt := range []struct {
+		name, origin string
+		want         bool
+	}{
+		{"example.com.", "example.com", true},
+		{"a.b.example.com.", "example.com.", true},
+		{"badexample.com.", "example.com.", false},
+		{"example.org.", "example.com.", false},
+		{"anything.", ".", true},
+	} {
+		if got := InZone(tt.name, tt.origin); got != tt.want {
+			t.Errorf("InZone(%q, %q) = %v, want %v", tt.name, tt.origin, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,547 @@
+// Package zonefile parses RFC 1035 master (BIND style) zone files into
+// structured records.
+//
+// It understands the $ORIGIN, $TTL and $INCLUDE directives, parenthesised
+// multi-line entries, comments, quoted character-strings, `@`, relative names,
+// blank owners that repeat the previous owner, and TTL/class fields in either
+// order. The data of the types listed in rdataFields is checked field by field
+// and domain names inside it are made absolute.
+package zonefile
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// maxIncludeDepth bounds nested $INCLUDEs, so a file including itself fails
+// instead of recursing forever.
+const maxIncludeDepth = 10
+
+// Record is one resource record from a zone file. Names are absolute, lower
+// case and end with a dot.
+type Record struct {
+	Name  string
+	TTL   uint32
+	Class string
+	Type  string
+	// Data holds the record data fields. Quotes are removed from
+	// character-strings and domain names are made absolute.
+	Data []string
+	// File is the $INCLUDEd file the record came from, empty for the main
+	// input.
+	File string
+	Line int
+}
+
+// String renders the record in zone file presentation format.
+func (r Record) String() string {
+	return fmt.Sprintf("%s %d %s %s %s", r.Name, r.TTL, r.Class, r.Type, strings.Join(r.presentData(), " "))
+}
+
+// presentData quotes the character-string fields of the record data.
+func (r Record) presentData() []string {
+	fields := rdataFields[r.Type]
+	out := make([]string, len(r.Data))
+	for i, d := range r.Data {
+		kind := fieldAny
+		if i < len(fields) {
+			kind = fields[i]
+		} else if len(fields) > 0 {
+			kind = fields[len(fields)-1]
+		}
+		if kind == fieldString || kind == fieldStrings {
+			d = strconv.Quote(d)
+		}
+		out[i] = d
+	}
+	return out
+}
+
+// Error reports a problem at a line of the zone file.
+type Error struct {
+	File string // set for problems inside an $INCLUDEd file
+	Line int
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	if e.File != "" {
+		return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Msg)
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+// ErrorList is every problem found in a zone file, in input order.
+type ErrorList []*Error
+
+func (l ErrorList) Error() string {
+	msgs := make([]string, len(l))
+	for i, e := range l {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap lets errors.As reach the individual *Error values.
+func (l ErrorList) Unwrap() []error {
+	errs := make([]error, len(l))
+	for i, e := range l {
+		errs[i] = e
+	}
+	return errs
+}
+
+// Parse reads a zone file whose names are relative to origin. Relative
+// $INCLUDE paths are resolved against dir, or the current directory when dir
+// is empty.
+//
+// Parsing carries on past bad records so that every problem is reported; the
+// records that did parse are returned along with an ErrorList.
+func Parse(r io.Reader, origin, dir string) ([]Record, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if dir == "" {
+		dir = "."
+	}
+	p := &parser{origin: Fqdn(origin), dir: dir}
+	p.parse(string(data), "", 0)
+	if len(p.errs) > 0 {
+		return p.records, p.errs
+	}
+	return p.records, nil
+}
+
+// ParseFile is Parse for a file on disk; relative $INCLUDE paths are resolved
+// against the file's directory.
+func ParseFile(path, origin string) ([]Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f, origin, filepath.Dir(path))
+}
+
+// HasInclude reports whether the zone file src uses $INCLUDE. A file that
+// can't be tokenized is reported as not using it.
+func HasInclude(src []byte) bool {
+	entries, err := splitEntries(string(src))
+	if err != nil {
+		return false
+	}
+	for _, e := range entries {
+		first := e.tokens[0]
+		if !e.blankOwner && !first.quoted && strings.EqualFold(first.text, "$INCLUDE") {
+			return true
+		}
+	}
+	return false
+}
+
+// Fqdn lower-cases name and adds the trailing dot if it is missing.
+func Fqdn(name string) string {
+	name = strings.ToLower(name)
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+	return name
+}
+
+// token is one field of an entry.
+type token struct {
+	text   string
+	quoted bool
+}
+
+// entry is one logical line: a directive or a record, with parenthesised
+// continuation lines joined.
+type entry struct {
+	line int
+	// blankOwner is set when the entry starts with whitespace, meaning the
+	// owner of the previous record is reused.
+	blankOwner bool
+	tokens     []token
+}
+
+type parser struct {
+	origin    string
+	dir       string // base for relative $INCLUDE paths
+	file      string // file being parsed, empty for the main input
+	errs      ErrorList
+	ttl       uint32 // from $TTL
+	hasTTL    bool
+	lastTTL   uint32 // from the previous record, for files without $TTL
+	hasLast   bool
+	lastOwner string
+	records   []Record
+}
+
+// parse handles every entry of src, recording errors rather than stopping.
+// file names the $INCLUDEd file src came from and depth its nesting.
+func (p *parser) parse(src, file string, depth int) {
+	prev := p.file
+	p.file = file
+	defer func() { p.file = prev }()
+
+	entries, err := splitEntries(src)
+	if err != nil {
+		p.fail(err)
+		return
+	}
+	for _, e := range entries {
+		if err := p.handle(e, depth); err != nil {
+			p.fail(err)
+		}
+	}
+}
+
+// fail records err, tagging it with the file being parsed.
+func (p *parser) fail(err error) {
+	e, ok := err.(*Error)
+	if !ok {
+		e = &Error{Msg: err.Error()}
+	}
+	if e.File == "" {
+		e.File = p.file
+	}
+	p.errs = append(p.errs, e)
+}
+
+// splitEntries tokenizes src into entries, handling comments, quotes,
+// escapes and parentheses.
+func splitEntries(src string) ([]entry, error) {
+	var (
+		entries []entry
+		cur     entry
+		tok     strings.Builder
+		inTok   bool
+		quoted  bool
+		inQuote bool
+		depth   int
+		parenAt int
+	)
+	line := 1
+	lineStart := true
+	cur.line = 1
+
+	flushTok := func() {
+		if inTok {
+			cur.tokens = append(cur.tokens, token{text: tok.String(), quoted: quoted})
+			tok.Reset()
+			inTok, quoted = false, false
+		}
+	}
+	flushEntry := func() {
+		flushTok()
+		if len(cur.tokens) > 0 {
+			entries = append(entries, cur)
+		}
+		cur = entry{line: line}
+	}
+
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		if inQuote {
+			switch c {
+			case '\\':
+				if i+1 < len(src) {
+					i++
+					tok.WriteByte(src[i])
+					if src[i] == '\n' {
+						line++
+					}
+				}
+			case '"':
+				inQuote = false
+			case '\n':
+				return nil, &Error{Line: line, Msg: "unterminated quoted string"}
+			default:
+				tok.WriteByte(c)
+			}
+			continue
+		}
+
+		if lineStart {
+			lineStart = false
+			if depth == 0 {
+				cur.line = line
+				cur.blankOwner = c == ' ' || c == '\t'
+			}
+		}
+
+		switch c {
+		case '\n':
+			line++
+			lineStart = true
+			if depth == 0 {
+				flushEntry()
+			} else {
+				flushTok()
+			}
+		case ' ', '\t', '\r':
+			flushTok()
+		case ';':
+			for i+1 < len(src) && src[i+1] != '\n' {
+				i++
+			}
+		case '(':
+			flushTok()
+			if depth == 0 {
+				parenAt = line
+			}
+			depth++
+		case ')':
+			flushTok()
+			if depth == 0 {
+				return nil, &Error{Line: line, Msg: "unbalanced )"}
+			}
+			depth--
+		case '"':
+			flushTok()
+			inTok, quoted, inQuote = true, true, true
+		case '\\':
+			inTok = true
+			tok.WriteByte(c)
+			if i+1 < len(src) {
+				i++
+				tok.WriteByte(src[i])
+			}
+		default:
+			inTok = true
+			tok.WriteByte(c)
+		}
+	}
+	if inQuote {
+		return nil, &Error{Line: line, Msg: "unterminated quoted string"}
+	}
+	if depth > 0 {
+		return nil, &Error{Line: parenAt, Msg: "unbalanced ("}
+	}
+	flushEntry()
+	return entries, nil
+}
+
+func (p *parser) handle(e entry, depth int) error {
+	first := e.tokens[0]
+	if !e.blankOwner && !first.quoted && strings.HasPrefix(first.text, "$") {
+		return p.directive(e, depth)
+	}
+
+	toks := e.tokens
+	owner := p.lastOwner
+	if !e.blankOwner {
+		owner = p.absolute(toks[0].text)
+		toks = toks[1:]
+	}
+	if owner == "" {
+		return &Error{Line: e.line, Msg: "record has no owner name"}
+	}
+
+	// Later blank-owner records belong to this owner even if this one turns
+	// out to be invalid.
+	p.lastOwner = owner
+	if err := checkName(owner); err != nil {
+		return &Error{Line: e.line, Msg: fmt.Sprintf("owner name: %v", err)}
+	}
+
+	rec := Record{Name: owner, Class: "IN", File: p.file, Line: e.line}
+	hasTTL, hasClass := false, false
+	for len(toks) > 0 && !toks[0].quoted {
+		t := toks[0].text
+		if ttl, ok := parseTTL(t); ok && !hasTTL {
+			rec.TTL, hasTTL = ttl, true
+		} else if isClass(t) && !hasClass {
+			rec.Class, hasClass = strings.ToUpper(t), true
+		} else {
+			break
+		}
+		toks = toks[1:]
+	}
+	if len(toks) == 0 {
+		return &Error{Line: e.line, Msg: "record has no type"}
+	}
+	rec.Type = strings.ToUpper(toks[0].text)
+	if !isType(rec.Type) {
+		return &Error{Line: e.line, Msg: fmt.Sprintf("unknown record type %q", toks[0].text)}
+	}
+	toks = toks[1:]
+
+	switch {
+	case hasTTL:
+	case p.hasTTL:
+		rec.TTL = p.ttl
+	case p.hasLast:
+		rec.TTL = p.lastTTL
+	default:
+		return &Error{Line: e.line, Msg: "no TTL given and no $TTL in effect"}
+	}
+
+	data, err := p.rdata(rec.Type, toks)
+	if err != nil {
+		return &Error{Line: e.line, Msg: err.Error()}
+	}
+	rec.Data = data
+
+	p.lastTTL, p.hasLast = rec.TTL, true
+	p.records = append(p.records, rec)
+	return nil
+}
+
+func (p *parser) directive(e entry, depth int) error {
+	args := e.tokens[1:]
+	switch name := strings.ToUpper(e.tokens[0].text); name {
+	case "$ORIGIN":
+		if len(args) != 1 {
+			return &Error{Line: e.line, Msg: "$ORIGIN takes one domain name"}
+		}
+		p.origin = p.absolute(args[0].text)
+	case "$TTL":
+		if len(args) != 1 {
+			return &Error{Line: e.line, Msg: "$TTL takes one value"}
+		}
+		ttl, ok := parseTTL(args[0].text)
+		if !ok {
+			return &Error{Line: e.line, Msg: fmt.Sprintf("invalid $TTL %q", args[0].text)}
+		}
+		p.ttl, p.hasTTL = ttl, true
+	case "$INCLUDE":
+		if len(args) < 1 || len(args) > 2 {
+			return &Error{Line: e.line, Msg: "$INCLUDE takes a file name and an optional origin"}
+		}
+		return p.include(e.line, args, depth)
+	default:
+		return &Error{Line: e.line, Msg: fmt.Sprintf("unsupported directive %s", name)}
+	}
+	return nil
+}
+
+// include parses an $INCLUDEd file. Its optional origin, and any $ORIGIN
+// inside it, only apply to the included file (RFC 1035 section 5.1).
+func (p *parser) include(line int, args []token, depth int) error {
+	if depth >= maxIncludeDepth {
+		return &Error{Line: line, Msg: fmt.Sprintf("$INCLUDE nested more than %d deep", maxIncludeDepth)}
+	}
+	path := args[0].text
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(p.dir, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return &Error{Line: line, Msg: fmt.Sprintf("$INCLUDE: %v", err)}
+	}
+
+	savedOrigin, savedDir := p.origin, p.dir
+	defer func() { p.origin, p.dir = savedOrigin, savedDir }()
+	if len(args) == 2 {
+		p.origin = p.absolute(args[1].text)
+	}
+	p.dir = filepath.Dir(path)
+	p.parse(string(data), path, depth+1)
+	return nil
+}
+
+// absolute resolves name against the current origin.
+func (p *parser) absolute(name string) string {
+	switch {
+	case name == "@":
+		return p.origin
+	case strings.HasSuffix(name, ".") && !strings.HasSuffix(name, `\.`):
+		return strings.ToLower(name)
+	case p.origin == ".":
+		return strings.ToLower(name) + "."
+	default:
+		return strings.ToLower(name) + "." + p.origin
+	}
+}
+
+// rdata converts the data tokens of a record, making domain names absolute.
+func (p *parser) rdata(rtype string, toks []token) ([]string, error) {
+	if len(toks) == 0 {
+		return nil, fmt.Errorf("%s record has no data", rtype)
+	}
+	fields := rdataFields[rtype]
+	out := make([]string, 0, len(toks))
+	for i, t := range toks {
+		kind := fieldAny
+		switch {
+		case i < len(fields):
+			kind = fields[i]
+		case len(fields) > 0 && fields[len(fields)-1].variadic():
+			kind = fields[len(fields)-1]
+		case len(fields) > 0:
+			return nil, fmt.Errorf("too many fields for %s record", rtype)
+		}
+		v := t.text
+		if kind == fieldName && !t.quoted {
+			v = p.absolute(v)
+		}
+		out = append(out, v)
+	}
+	if n := requiredFields(fields); len(out) < n {
+		return nil, fmt.Errorf("%s record needs %d fields, got %d", rtype, n, len(out))
+	}
+	if err := checkFields(rtype, fields, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// parseTTL accepts plain seconds or BIND style units such as 1h30m or 1W.
+func parseTTL(s string) (uint32, bool) {
+	if s == "" {
+		return 0, false
+	}
+	if n, err := strconv.ParseUint(s, 10, 32); err == nil {
+		return uint32(n), true
+	}
+	var total, cur uint64
+	digits := false
+	for _, c := range strings.ToLower(s) {
+		if c >= '0' && c <= '9' {
+			cur = cur*10 + uint64(c-'0')
+			digits = true
+			continue
+		}
+		if !digits {
+			return 0, false
+		}
+		mult, ok := map[rune]uint64{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}[c]
+		if !ok {
+			return 0, false
+		}
+		total += cur * mult
+		cur, digits = 0, false
+	}
+	total += cur
+	if total > 1<<32-1 {
+		return 0, false
+	}
+	return uint32(total), true
+}
+
+func isClass(s string) bool {
+	switch strings.ToUpper(s) {
+	case "IN", "CH", "HS", "CS":
+		return true
+	}
+	return false
+}
+
+// isType reports whether s is a known type mnemonic or the RFC 3597 TYPEnnn
+// form.
+func isType(s string) bool {
+	if _, ok := rdataFields[s]; ok {
+		return true
+	}
+	if rest, ok := strings.CutPrefix(s, "TYPE"); ok {
+		_, err := strconv.ParseUint(rest, 10, 16)
+		return err == nil
+	}
+	return false
+}
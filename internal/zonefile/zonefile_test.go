@@ -0,0 +1,200 @@
+package zonefile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleZone = `$TTL 1D
+@        21600    IN    SOA    ns1 hostmaster.example.com. (
+                                       1387784764 ; serial
+                                       28800      ; refresh
+                                       7200       ; retry
+                                       1W         ; expire
+                                       3600 )     ; minimum
+
+                       IN    NS    ns1.example.com.
+                       IN    NS    ns2
+                       TXT     "v=spf1 -all" ; trailing comment
+       3600            TXT     "part one" "part two"
+               10800   IN      MX      10 mail
+www                     IN      A       192.0.2.1
+WWW.Sub                 A       192.0.2.2
+$ORIGIN other.example.com.
+host   IN 300 AAAA 2001:db8::1
+`
+
+func TestParse(t *testing.T) {
+	recs, err := Parse(strings.NewReader(sampleZone), "example.com", "")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	want := []Record{
+		{Name: "example.com.", TTL: 21600, Class: "IN", Type: "SOA", Line: 2,
+			Data: []string{"ns1.example.com.", "hostmaster.example.com.", "1387784764", "28800", "7200", "1W", "3600"}},
+		{Name: "example.com.", TTL: 86400, Class: "IN", Type: "NS", Line: 9, Data: []string{"ns1.example.com."}},
+		{Name: "example.com.", TTL: 86400, Class: "IN", Type: "NS", Line: 10, Data: []string{"ns2.example.com."}},
+		{Name: "example.com.", TTL: 86400, Class: "IN", Type: "TXT", Line: 11, Data: []string{"v=spf1 -all"}},
+		{Name: "example.com.", TTL: 3600, Class: "IN", Type: "TXT", Line: 12, Data: []string{"part one", "part two"}},
+		{Name: "example.com.", TTL: 10800, Class: "IN", Type: "MX", Line: 13, Data: []string{"10", "mail.example.com."}},
+		{Name: "www.example.com.", TTL: 86400, Class: "IN", Type: "A", Line: 14, Data: []string{"192.0.2.1"}},
+		{Name: "www.sub.example.com.", TTL: 86400, Class: "IN", Type: "A", Line: 15, Data: []string{"192.0.2.2"}},
+		{Name: "host.other.example.com.", TTL: 300, Class: "IN", Type: "AAAA", Line: 17, Data: []string{"2001:db8::1"}},
+	}
+	if len(recs) != len(want) {
+		t.Fatalf("got %d records, want %d:\n%v", len(recs), len(want), recs)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(recs[i], want[i]) {
+			t.Errorf("record %d:\n got %+v\nwant %+v", i, recs[i], want[i])
+		}
+	}
+}
+
+func TestParseTTLFallsBackToPreviousRecord(t *testing.T) {
+	recs, err := Parse(strings.NewReader("a 600 A 192.0.2.1\nb A 192.0.2.2\n"), "example.com.", "")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if recs[1].TTL != 600 {
+		t.Errorf("TTL = %d, want the previous record's 600", recs[1].TTL)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name, src string
+		line      int
+	}{
+		{"no ttl", "www A 192.0.2.1\n", 1},
+		{"unknown type", "$TTL 60\nwww IN BOGUS 1\n", 2},
+		{"unterminated quote", "$TTL 60\n\nwww TXT \"abc\n", 3},
+		{"unbalanced open", "$TTL 60\n@ SOA ns1 host ( 1 2 3\n4 5\n", 2},
+		{"unbalanced close", "$TTL 60\nwww A 192.0.2.1 )\n", 2},
+		{"missing data", "$TTL 60\nwww MX 10\n", 2},
+		{"too many fields", "$TTL 60\nwww A 192.0.2.1 192.0.2.2\n", 2},
+		{"bad $TTL", "$TTL soon\n", 1},
+		{"no owner", " 60 A 192.0.2.1\n", 1},
+		{"bad IPv4", "$TTL 60\nwww A 192.0.2.256\n", 2},
+		{"IPv6 in A", "$TTL 60\nwww A 2001:db8::1\n", 2},
+		{"bad IPv6", "$TTL 60\nwww AAAA 192.0.2.1\n", 2},
+		{"bad MX preference", "$TTL 60\n@ MX 70000 mail\n", 2},
+		{"bad hex", "$TTL 60\n@ DS 12345 13 2 ZZZZ\n", 2},
+		{"long label", "$TTL 60\n" + strings.Repeat("a", 64) + " A 192.0.2.1\n", 2},
+		{"empty label", "$TTL 60\nwww CNAME a..example.com.\n", 2},
+		{"missing $INCLUDE", "$INCLUDE /nonexistent/file.zone\n", 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.src), "example.com", "")
+			var pe *Error
+			if !errors.As(err, &pe) {
+				t.Fatalf("want *Error, got %T (%v)", err, err)
+			}
+			if pe.Line != tt.line {
+				t.Errorf("line = %d, want %d (%v)", pe.Line, tt.line, err)
+			}
+		})
+	}
+}
+
+func TestParseTTLUnits(t *testing.T) {
+	for in, want := range map[string]uint32{"60": 60, "1h": 3600, "1h30m": 5400, "1W": 604800, "2d": 172800} {
+		if got, ok := parseTTL(in); !ok || got != want {
+			t.Errorf("parseTTL(%q) = %d, %v; want %d", in, got, ok, want)
+		}
+	}
+	for _, in := range []string{"", "h", "1x", "99999999999"} {
+		if _, ok := parseTTL(in); ok {
+			t.Errorf("parseTTL(%q) should fail", in)
+		}
+	}
+}
+
+func TestRecordString(t *testing.T) {
+	r := Record{Name: "example.com.", TTL: 60, Class: "IN", Type: "TXT", Data: []string{"a b", "c"}}
+	if got, want := r.String(), `example.com. 60 IN TXT "a b" "c"`; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestParseReportsEveryError(t *testing.T) {
+	src := "$TTL 60\na A 192.0.2.1\nb A bogus\nc AAAA 2001:db8::1\nd MX x mail\n"
+	recs, err := Parse(strings.NewReader(src), "example.com", "")
+	var list ErrorList
+	if !errors.As(err, &list) {
+		t.Fatalf("want ErrorList, got %T (%v)", err, err)
+	}
+	if len(list) != 2 || list[0].Line != 3 || list[1].Line != 5 {
+		t.Errorf("errors = %v, want lines 3 and 5", list)
+	}
+	if len(recs) != 2 {
+		t.Errorf("got %d good records, want 2", len(recs))
+	}
+}
+
+func TestParseFileInclude(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("main.zone", "$TTL 60\n@ NS ns1\n$INCLUDE hosts.zone sub\nafter A 192.0.2.9\n$INCLUDE bad.zone\n")
+	write("hosts.zone", "$ORIGIN deeper.sub.example.com.\nwww A 192.0.2.1\n")
+	write("bad.zone", "\nx A nope\n")
+
+	recs, err := ParseFile(filepath.Join(dir, "main.zone"), "example.com")
+	var list ErrorList
+	if !errors.As(err, &list) || len(list) != 1 {
+		t.Fatalf("want one error, got %v", err)
+	}
+	if filepath.Base(list[0].File) != "bad.zone" || list[0].Line != 2 {
+		t.Errorf("error at %s:%d, want bad.zone:2", list[0].File, list[0].Line)
+	}
+
+	var names []string
+	for _, r := range recs {
+		names = append(names, r.Name)
+	}
+	want := []string{"example.com.", "www.deeper.sub.example.com.", "after.example.com."}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("names = %v, want %v (the include's $ORIGIN must not leak)", names, want)
+	}
+	if filepath.Base(recs[1].File) != "hosts.zone" || recs[1].Line != 2 {
+		t.Errorf("included record at %s:%d, want hosts.zone:2", recs[1].File, recs[1].Line)
+	}
+}
+
+func TestParseIncludeLoop(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "loop.zone")
+	if err := os.WriteFile(path, []byte("$INCLUDE loop.zone\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseFile(path, "example.com"); err == nil {
+		t.Fatal("want an error for a recursive $INCLUDE")
+	}
+}
+
+func TestHasInclude(t *testing.T) {
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{"$TTL 60\n$include hosts.inc\n", true},
+		{"$TTL 60\nwww A 192.0.2.1\n", false},
+		{"txt TXT \"$INCLUDE x\"\n", false},
+		{"$INCLUDE \"unterminated\n", false},
+	}
+	for _, tt := range tests {
+		if got := HasInclude([]byte(tt.src)); got != tt.want {
+			t.Errorf("HasInclude(%q) = %v, want %v", tt.src, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package technitium
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// DNSApp is an app installed on the server.
+type DNSApp struct {
+	Name            string     `json:"name"`
+	Version         string     `json:"version"`
+	UpdateVersion   string     `json:"updateVersion,omitempty"`
+	UpdateAvailable bool       `json:"updateAvailable"`
+	Classes         []AppClass `json:"dnsApps"`
+}
+
+// AppClass is one of the classes an app provides, and the roles it plays.
+type AppClass struct {
+	ClassPath                     string `json:"classPath"`
+	Description                   string `json:"description"`
+	IsAppRecordRequestHandler     bool   `json:"isAppRecordRequestHandler"`
+	IsRequestController           bool   `json:"isRequestController"`
+	IsAuthoritativeRequestHandler bool   `json:"isAuthoritativeRequestHandler"`
+	IsRequestBlockingHandler      bool   `json:"isRequestBlockingHandler"`
+	IsQueryLogger                 bool   `json:"isQueryLogger"`
+	IsPostProcessor               bool   `json:"isPostProcessor"`
+}
+
+// StoreApp is an app offered by the DNS App Store.
+type StoreApp struct {
+	Name             string `json:"name"`
+	Version          string `json:"version"`
+	Description      string `json:"description"`
+	URL              string `json:"url"`
+	Size             string `json:"size"`
+	Installed        bool   `json:"installed"`
+	InstalledVersion string `json:"installedVersion,omitempty"`
+	UpdateAvailable  bool   `json:"updateAvailable"`
+}
+
+// ListApps lists the installed apps.
+func (c *Client) ListApps(ctx context.Context) ([]DNSApp, error) {
+	var out struct {
+		Apps []DNSApp `json:"apps"`
+	}
+	if err := c.call(ctx, "/api/apps/list", nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Apps, nil
+}
+
+// ListStoreApps lists the apps of the DNS App Store. The server downloads
+// the list, so this fails when it can't reach the store.
+func (c *Client) ListStoreApps(ctx context.Context) ([]StoreApp, error) {
+	var out struct {
+		StoreApps []StoreApp `json:"storeApps"`
+	}
+	if err := c.call(ctx, "/api/apps/listStoreApps", nil, &out); err != nil {
+		return nil, err
+	}
+	return out.StoreApps, nil
+}
+
+// InstallApp installs the app package (a zip file) read from r as name.
+func (c *Client) InstallApp(ctx context.Context, name string, r io.Reader) (*DNSApp, error) {
+	return c.uploadApp(ctx, "/api/apps/install", "installedApp", name, r)
+}
+
+// UpdateApp replaces the installed app name with the package read from r.
+// The app's config is kept.
+func (c *Client) UpdateApp(ctx context.Context, name string, r io.Reader) (*DNSApp, error) {
+	return c.uploadApp(ctx, "/api/apps/update", "updatedApp", name, r)
+}
+
+func (c *Client) uploadApp(ctx context.Context, path, key, name string, r io.Reader) (*DNSApp, error) {
+	resp, err := c.PostFile(ctx, path, url.Values{"name": {name}}, name+".zip", r)
+	out := map[string]*DNSApp{}
+	if err := decodeResponse(resp, err, path, &out); err != nil {
+		return nil, err
+	}
+	return appResult(out, path, key)
+}
+
+// DownloadAndInstallApp has the server download the app package at
+// packageURL and install it as name.
+func (c *Client) DownloadAndInstallApp(ctx context.Context, name, packageURL string) (*DNSApp, error) {
+	return c.downloadApp(ctx, "/api/apps/downloadAndInstall", "installedApp", name, packageURL)
+}
+
+// DownloadAndUpdateApp has the server download the app package at
+// packageURL and update the installed app name with it.
+func (c *Client) DownloadAndUpdateApp(ctx context.Context, name, packageURL string) (*DNSApp, error) {
+	return c.downloadApp(ctx, "/api/apps/downloadAndUpdate", "updatedApp", name, packageURL)
+}
+
+func (c *Client) downloadApp(ctx context.Context, path, key, name, packageURL string) (*DNSApp, error) {
+	out := map[string]*DNSApp{}
+	if err := c.call(ctx, path, url.Values{"name": {name}, "url": {packageURL}}, &out); err != nil {
+		return nil, err
+	}
+	return appResult(out, path, key)
+}
+
+// appResult returns the app the server reported under key, such as
+// "installedApp".
+func appResult(out map[string]*DNSApp, path, key string) (*DNSApp, error) {
+	app := out[key]
+	if app == nil {
+		return nil, fmt.Errorf("invalid response from %s: no %s", path, key)
+	}
+	return app, nil
+}
+
+// UninstallApp uninstalls the app name, deleting its config.
+func (c *Client) UninstallApp(ctx context.Context, name string) error {
+	return c.call(ctx, "/api/apps/uninstall", url.Values{"name": {name}}, nil)
+}
+
+// GetAppConfig returns the config of the app name as stored, usually JSON.
+// It is empty when the app has no config.
+func (c *Client) GetAppConfig(ctx context.Context, name string) (string, error) {
+	var out struct {
+		Config *string `json:"config"`
+	}
+	if err := c.call(ctx, "/api/apps/config/get", url.Values{"name": {name}}, &out); err != nil {
+		return "", err
+	}
+	if out.Config == nil {
+		return "", nil
+	}
+	return *out.Config, nil
+}
+
+// SetAppConfig replaces the config of the app name, which reloads it. The
+// config is posted as a form, as it may be too large for a URL.
+func (c *Client) SetAppConfig(ctx context.Context, name, config string) error {
+	form := url.Values{"config": {config}}
+	return c.callPost(ctx, "/api/apps/config/set", url.Values{"name": {name}}, strings.NewReader(form.Encode()), "application/x-www-form-urlencoded", nil)
+}
@@ -0,0 +1,106 @@
+package technitium
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestListApps(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok","response":{"apps":[{"name":"Split Horizon","version":"8.0",
+		"dnsApps":[{"classPath":"SplitHorizon.SimpleAddress","description":"Returns A records.","isAppRecordRequestHandler":true}]}]}}`)
+
+	apps, err := c.ListApps(context.Background())
+	if err != nil {
+		t.Fatalf("ListApps: %v", err)
+	}
+	if got.Path != "/api/apps/list" {
+		t.Errorf("path = %s", got.Path)
+	}
+	if len(apps) != 1 || apps[0].Name != "Split Horizon" || len(apps[0].Classes) != 1 || !apps[0].Classes[0].IsAppRecordRequestHandler {
+		t.Errorf("apps = %+v", apps)
+	}
+}
+
+func TestInstallApp(t *testing.T) {
+	var name, fileName, data string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/apps/install" || r.Method != http.MethodPost {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		name = r.URL.Query().Get("name")
+		f, h, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("FormFile: %v", err)
+		} else {
+			b, _ := io.ReadAll(f)
+			fileName, data = h.Filename, string(b)
+		}
+		io.WriteString(w, `{"status":"ok","response":{"installedApp":{"name":"Failover","version":"7.0"}}}`)
+	}))
+	defer srv.Close()
+
+	c := &Client{Host: srv.URL, HTTP: srv.Client()}
+	app, err := c.InstallApp(context.Background(), "Failover", strings.NewReader("PK zip"))
+	if err != nil {
+		t.Fatalf("InstallApp: %v", err)
+	}
+	if name != "Failover" || fileName != "Failover.zip" || data != "PK zip" {
+		t.Errorf("upload = %q %q %q", name, fileName, data)
+	}
+	if app == nil || app.Version != "7.0" {
+		t.Errorf("app = %+v", app)
+	}
+}
+
+func TestGetAppConfig(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok","response":{"config":"{\n  \"enable\": true\n}"}}`)
+	config, err := c.GetAppConfig(context.Background(), "Query Logs (Sqlite)")
+	if err != nil {
+		t.Fatalf("GetAppConfig: %v", err)
+	}
+	if got.Path != "/api/apps/config/get" || got.Query().Get("name") != "Query Logs (Sqlite)" {
+		t.Errorf("request = %s", got)
+	}
+	if config != "{\n  \"enable\": true\n}" {
+		t.Errorf("config = %q", config)
+	}
+
+	c, _ = stubServer(t, `{"status":"ok","response":{"config":null}}`)
+	if config, err := c.GetAppConfig(context.Background(), "Geo"); err != nil || config != "" {
+		t.Errorf("null config = %q, %v", config, err)
+	}
+}
+
+func TestSetAppConfig(t *testing.T) {
+	var config string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/apps/config/set" || r.URL.Query().Get("name") != "Failover" {
+			t.Errorf("request = %s", r.URL)
+		}
+		r.ParseForm()
+		config = r.PostForm.Get("config")
+		io.WriteString(w, `{"status":"error","errorMessage":"bad config"}`)
+	}))
+	defer srv.Close()
+
+	c := &Client{Host: srv.URL, HTTP: srv.Client()}
+	err := c.SetAppConfig(context.Background(), "Failover", `{"a": "b&c"}`)
+	if apiErr, ok := err.(*APIError); !ok || apiErr.Message != "bad config" {
+		t.Errorf("err = %v", err)
+	}
+	if config != `{"a": "b&c"}` {
+		t.Errorf("config = %q", config)
+	}
+}
+
+func TestDownloadAndInstallAppWithoutApp(t *testing.T) {
+	c, _ := stubServer(t, `{"status":"ok","response":{}}`)
+	app, err := c.DownloadAndInstallApp(context.Background(), "Failover", "https://example.com/FailoverApp.zip")
+	if err == nil || app != nil {
+		t.Errorf("app = %+v, err = %v; want an error", app, err)
+	}
+}
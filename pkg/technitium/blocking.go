@@ -0,0 +1,53 @@
+package technitium
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AllowListPrefix marks an entry of Settings.BlockListURLs as an allow list:
+// a list of domains to allow rather than block.
+const AllowListPrefix = "!"
+
+// SetBlockLists replaces the server's block list URLs with urls; an empty
+// list removes them all. intervalHours, when positive, also sets how often
+// the lists are downloaded again. Other settings are left alone. The URLs
+// are posted as a form, as a long list doesn't fit in a URL.
+func (c *Client) SetBlockLists(ctx context.Context, urls []string, intervalHours int) error {
+	value := "false"
+	if len(urls) > 0 {
+		value = strings.Join(urls, ",")
+	}
+	form := url.Values{"blockListUrls": {value}}
+	if intervalHours > 0 {
+		form.Set("blockListUrlUpdateIntervalHours", strconv.Itoa(intervalHours))
+	}
+	return c.callForm(ctx, "/api/settings/set", form, nil)
+}
+
+// ForceUpdateBlockLists makes the server download its block lists now
+// instead of at the next update interval. The download runs in the
+// background on the server.
+func (c *Client) ForceUpdateBlockLists(ctx context.Context) error {
+	return c.call(ctx, "/api/settings/forceUpdateBlockLists", nil, nil)
+}
+
+// TemporaryDisableBlocking turns blocking off for the given number of
+// minutes and returns when the server will turn it back on.
+func (c *Client) TemporaryDisableBlocking(ctx context.Context, minutes int) (time.Time, error) {
+	var out struct {
+		Till string `json:"temporaryDisableBlockingTill"`
+	}
+	if err := c.call(ctx, "/api/settings/temporaryDisableBlocking", url.Values{"minutes": {strconv.Itoa(minutes)}}, &out); err != nil {
+		return time.Time{}, err
+	}
+	till, err := time.Parse(time.RFC3339, out.Till)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid temporaryDisableBlockingTill %q: %w", out.Till, err)
+	}
+	return till, nil
+}
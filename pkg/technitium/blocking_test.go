@@ -0,0 +1,66 @@
+package technitium
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestSetBlockLists(t *testing.T) {
+	var rawQuery string
+	var form url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/settings/set" || r.Method != http.MethodPost {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		r.ParseForm()
+		rawQuery, form = r.URL.RawQuery, r.PostForm
+		io.WriteString(w, `{"status":"ok","response":{}}`)
+	}))
+	defer srv.Close()
+
+	c := &Client{Host: srv.URL, HTTP: srv.Client()}
+	urls := []string{"https://lists.example/ads.txt", "!https://lists.example/allow.txt"}
+	if err := c.SetBlockLists(context.Background(), urls, 12); err != nil {
+		t.Fatalf("SetBlockLists: %v", err)
+	}
+	if form.Get("blockListUrls") != "https://lists.example/ads.txt,!https://lists.example/allow.txt" ||
+		form.Get("blockListUrlUpdateIntervalHours") != "12" || rawQuery != "" {
+		t.Errorf("form = %v, query = %q", form, rawQuery)
+	}
+
+	if err := c.SetBlockLists(context.Background(), nil, 0); err != nil {
+		t.Fatalf("SetBlockLists: %v", err)
+	}
+	if form.Get("blockListUrls") != "false" || form.Has("blockListUrlUpdateIntervalHours") {
+		t.Errorf("form = %v", form)
+	}
+}
+
+func TestTemporaryDisableBlocking(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok","response":{"temporaryDisableBlockingTill":"2024-05-01T10:15:00.123Z"}}`)
+	till, err := c.TemporaryDisableBlocking(context.Background(), 15)
+	if err != nil {
+		t.Fatalf("TemporaryDisableBlocking: %v", err)
+	}
+	if got.Path != "/api/settings/temporaryDisableBlocking" || got.Query().Get("minutes") != "15" {
+		t.Errorf("request = %s?%s", got.Path, got.RawQuery)
+	}
+	if want := time.Date(2024, 5, 1, 10, 15, 0, 123e6, time.UTC); !till.Equal(want) {
+		t.Errorf("till = %v, want %v", till, want)
+	}
+}
+
+func TestForceUpdateBlockLists(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok","response":{}}`)
+	if err := c.ForceUpdateBlockLists(context.Background()); err != nil {
+		t.Fatalf("ForceUpdateBlockLists: %v", err)
+	}
+	if got.Path != "/api/settings/forceUpdateBlockLists" {
+		t.Errorf("path = %s", got.Path)
+	}
+}
@@ -0,0 +1,78 @@
+package technitium
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// CacheTTL is the remaining TTL of a cached record in seconds. The cache API
+// formats it as a string such as "285 (4 mins 45 sec)"; plain numbers are
+// accepted too.
+type CacheTTL uint32
+
+// UnmarshalJSON reads the seconds the TTL string starts with.
+func (t *CacheTTL) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var n uint32
+		if err := json.Unmarshal(data, &n); err != nil {
+			return err
+		}
+		*t = CacheTTL(n)
+		return nil
+	}
+	digits, _, _ := strings.Cut(strings.TrimSpace(s), " ")
+	n, err := strconv.ParseUint(digits, 10, 32)
+	if err != nil {
+		return err
+	}
+	*t = CacheTTL(n)
+	return nil
+}
+
+// CachedRecord is a record in the DNS cache.
+type CachedRecord struct {
+	Name         string                 `json:"name"`
+	Type         string                 `json:"type"`
+	TTL          CacheTTL               `json:"ttl"`
+	RData        map[string]interface{} `json:"rData"`
+	DNSSECStatus string                 `json:"dnssecStatus,omitempty"`
+	LastUsedOn   string                 `json:"lastUsedOn,omitempty"`
+}
+
+// CacheList is one level of the cache: the records of Domain and the names
+// of its subdomains that have cached data. Zones are full domain names.
+type CacheList struct {
+	Domain  string         `json:"domain"`
+	Zones   []string       `json:"zones"`
+	Records []CachedRecord `json:"records"`
+}
+
+// ListCache lists the cached records of domain and its subdomains in the
+// cache; an empty domain lists the top-level domains.
+func (c *Client) ListCache(ctx context.Context, domain string) (*CacheList, error) {
+	var list CacheList
+	if err := c.call(ctx, "/api/cache/list", url.Values{"domain": {domain}}, &list); err != nil {
+		return nil, err
+	}
+	// The server names subdomains by their own label; make them full names.
+	for i, z := range list.Zones {
+		if list.Domain != "" && !strings.HasSuffix(strings.ToLower(z), "."+strings.ToLower(list.Domain)) {
+			list.Zones[i] = z + "." + list.Domain
+		}
+	}
+	return &list, nil
+}
+
+// DeleteCached removes domain and everything under it from the cache.
+func (c *Client) DeleteCached(ctx context.Context, domain string) error {
+	return c.call(ctx, "/api/cache/delete", url.Values{"domain": {domain}}, nil)
+}
+
+// FlushCache empties the cache.
+func (c *Client) FlushCache(ctx context.Context) error {
+	return c.call(ctx, "/api/cache/flush", nil, nil)
+}
@@ -0,0 +1,55 @@
+package technitium
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestListCache(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok","response":{"domain":"example.com","zones":["www","mail.example.com"],
+		"records":[{"name":"example.com","type":"A","ttl":"285 (4 mins 45 sec)","rData":{"ipAddress":"192.0.2.1"},"dnssecStatus":"Disabled"}]}}`)
+
+	list, err := c.ListCache(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("ListCache: %v", err)
+	}
+	if got.Path != "/api/cache/list" || got.Query().Get("domain") != "example.com" {
+		t.Errorf("request = %s?%s", got.Path, got.RawQuery)
+	}
+	if len(list.Zones) != 2 || list.Zones[0] != "www.example.com" || list.Zones[1] != "mail.example.com" {
+		t.Errorf("zones = %v", list.Zones)
+	}
+	if len(list.Records) != 1 || list.Records[0].TTL != 285 || list.Records[0].RData["ipAddress"] != "192.0.2.1" {
+		t.Errorf("records = %+v", list.Records)
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	for in, want := range map[string]CacheTTL{`"60 (1 min)"`: 60, `300`: 300, `"0"`: 0} {
+		var ttl CacheTTL
+		if err := json.Unmarshal([]byte(in), &ttl); err != nil || ttl != want {
+			t.Errorf("%s: ttl = %d, %v; want %d", in, ttl, err, want)
+		}
+	}
+	var ttl CacheTTL
+	if err := json.Unmarshal([]byte(`"soon"`), &ttl); err == nil {
+		t.Error("\"soon\" parsed as a TTL")
+	}
+}
+
+func TestDeleteCachedAndFlush(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok","response":{}}`)
+	if err := c.DeleteCached(context.Background(), "example.com"); err != nil {
+		t.Fatalf("DeleteCached: %v", err)
+	}
+	if got.Path != "/api/cache/delete" || got.Query().Get("domain") != "example.com" {
+		t.Errorf("request = %s?%s", got.Path, got.RawQuery)
+	}
+	if err := c.FlushCache(context.Background()); err != nil {
+		t.Fatalf("FlushCache: %v", err)
+	}
+	if got.Path != "/api/cache/flush" {
+		t.Errorf("path = %s", got.Path)
+	}
+}
@@ -0,0 +1,363 @@
+// Package technitium is an HTTP client for the Technitium DNS Server API.
+//
+// It targets v15+, sending the session token via the
+// `Authorization: Bearer <token>` header. For older servers (or endpoints
+// that haven't been updated to honor the header), set Client.LegacyToken to
+// also emit the token as a `token=` query/form parameter.
+//
+// Get, Post, GetJSON and PostFormJSON give raw access to any endpoint; methods such as
+// ListZones, GetRecords and ListSessions decode responses into typed structs.
+// Every API error is returned as *APIError. Set Client.Retry to retry
+// transient failures of read-only calls.
+package technitium
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout is the request timeout of clients made with NewClient. It
+// bounds total request time including connect, TLS handshake, redirects, and
+// reading the response body.
+const DefaultTimeout = 5 * time.Second
+
+// Client talks to a Technitium DNS Server HTTP API.
+type Client struct {
+	Host        string
+	Token       string
+	HTTP        *http.Client
+	Timeout     time.Duration
+	LegacyToken bool // when true, also append `token=` to the query string
+	Retry       RetryPolicy
+	Logger      *slog.Logger // retries are logged at debug level; nil uses slog.Default()
+}
+
+// NewClient returns a Client for the server at host (for example
+// "http://localhost:5380") authenticating with token, using DefaultTimeout.
+func NewClient(host, token string) *Client {
+	return &Client{
+		Host:    host,
+		Token:   token,
+		Timeout: DefaultTimeout,
+		HTTP:    &http.Client{Timeout: DefaultTimeout},
+	}
+}
+
+func (c *Client) buildURL(path string, q url.Values) string {
+	host := strings.TrimRight(c.Host, "/")
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	if c.LegacyToken && c.Token != "" {
+		if q == nil {
+			q = url.Values{}
+		}
+		if q.Get("token") == "" {
+			q.Set("token", c.Token)
+		}
+	}
+	if len(q) == 0 {
+		return host + path
+	}
+	return host + path + "?" + q.Encode()
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, q url.Values, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.buildURL(path, q), body)
+	if err != nil {
+		return nil, err
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	return req, nil
+}
+
+// Do executes a pre-built request under ctx after attaching the Bearer auth
+// header.
+func (c *Client) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	req = req.WithContext(ctx)
+	if c.Token != "" && req.Header.Get("Authorization") == "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	return c.do(req)
+}
+
+// Get issues a GET against the API.
+func (c *Client) Get(ctx context.Context, path string, q url.Values) (*http.Response, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, path, q, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(req)
+}
+
+// Post issues a POST with the given body.
+func (c *Client) Post(ctx context.Context, path string, q url.Values, body io.Reader, contentType string) (*http.Response, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, path, q, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return c.do(req)
+}
+
+// PostFile uploads r as the "file" field of a multipart form named fileName,
+// the way the server takes backups and app packages. The form is buffered so
+// the request can be retried.
+func (c *Client) PostFile(ctx context.Context, path string, q url.Values, fileName string, r io.Reader) (*http.Response, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", fileName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(part, r); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return c.Post(ctx, path, q, body, writer.FormDataContentType())
+}
+
+// do is the single chokepoint where transport errors are inspected. It retries
+// transient failures as c.Retry allows and wraps timeout errors in a
+// TimeoutError so the CLI can print a friendly message.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	attempts := c.Retry.attempts(req)
+	for n := 1; ; n++ {
+		resp, err := c.HTTP.Do(req)
+		reason := retryable(resp, err)
+		if reason == "" || n >= attempts || req.Context().Err() != nil {
+			if err != nil && isTimeout(err) {
+				return resp, &TimeoutError{Timeout: c.Timeout, Err: err}
+			}
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		wait := c.Retry.backoff(n)
+		c.logger().Debug("retrying API request", "path", req.URL.Path, "reason", reason,
+			"attempt", n+1, "max_attempts", attempts, "wait", wait)
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+// TimeoutError indicates the request exceeded the client timeout.
+type TimeoutError struct {
+	Timeout time.Duration
+	Err     error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("request timed out after %s (set --timeout to override)", e.Timeout)
+}
+
+func (e *TimeoutError) Unwrap() error { return e.Err }
+
+func isTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+	var ue *url.Error
+	if errors.As(err, &ue) && ue.Timeout() {
+		return true
+	}
+	type timeoutIface interface{ Timeout() bool }
+	var t timeoutIface
+	if errors.As(err, &t) && t.Timeout() {
+		return true
+	}
+	return false
+}
+
+// APIError is returned when the API responds with status != "ok".
+type APIError struct {
+	Status  string
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	return "unexpected API error"
+}
+
+// GetJSON issues a GET, decodes the envelope, and returns both the full
+// envelope and the inner "response" object. It returns *APIError when the
+// server reports a non-ok status.
+func (c *Client) GetJSON(ctx context.Context, path string, q url.Values) (map[string]interface{}, map[string]interface{}, error) {
+	resp, err := c.Get(ctx, path, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	return decodeEnvelope(resp.Body)
+}
+
+// PostFormJSON is GetJSON with the parameters posted as a form instead, for
+// parameters too long for a URL.
+func (c *Client) PostFormJSON(ctx context.Context, path string, form url.Values) (map[string]interface{}, map[string]interface{}, error) {
+	resp, err := c.Post(ctx, path, nil, strings.NewReader(form.Encode()), "application/x-www-form-urlencoded")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	return decodeEnvelope(resp.Body)
+}
+
+// DoJSON executes the request and decodes the JSON envelope.
+func (c *Client) DoJSON(ctx context.Context, req *http.Request) (map[string]interface{}, map[string]interface{}, error) {
+	resp, err := c.Do(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	return decodeEnvelope(resp.Body)
+}
+
+// ServerVersion fetches the connected server's version string by calling
+// /api/settings/get and reading its `version` field.
+func (c *Client) ServerVersion(ctx context.Context) (string, error) {
+	_, response, err := c.GetJSON(ctx, "/api/settings/get", nil)
+	if err != nil {
+		return "", err
+	}
+	v, _ := response["version"].(string)
+	if v == "" {
+		return "", fmt.Errorf("server did not return a version field")
+	}
+	return v, nil
+}
+
+// envelope is the wrapper around every API response.
+type envelope struct {
+	Status       string          `json:"status"`
+	ErrorMessage string          `json:"errorMessage"`
+	Response     json.RawMessage `json:"response"`
+}
+
+// call issues a GET and decodes the envelope's response object into out,
+// which may be nil when the response carries nothing of interest. A response
+// of an unexpected shape is an error rather than a partly filled out.
+func (c *Client) call(ctx context.Context, path string, q url.Values, out interface{}) error {
+	resp, err := c.Get(ctx, path, q)
+	return decodeResponse(resp, err, path, out)
+}
+
+// callPost is call for endpoints that take a POST body, such as uploads and
+// forms too large for a URL.
+func (c *Client) callPost(ctx context.Context, path string, q url.Values, body io.Reader, contentType string, out interface{}) error {
+	resp, err := c.Post(ctx, path, q, body, contentType)
+	return decodeResponse(resp, err, path, out)
+}
+
+// callForm is call with the parameters posted as a form instead, for values
+// that must stay out of URLs, and so out of access logs, such as secrets.
+func (c *Client) callForm(ctx context.Context, path string, form url.Values, out interface{}) error {
+	return c.callPost(ctx, path, nil, strings.NewReader(form.Encode()), "application/x-www-form-urlencoded", out)
+}
+
+// decodeResponse decodes the "response" of an API reply into out.
+func decodeResponse(resp *http.Response, err error, path string, out interface{}) error {
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var env envelope
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	if env.Status != "ok" {
+		return &APIError{Status: env.Status, Message: env.ErrorMessage}
+	}
+	if out == nil || len(env.Response) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(env.Response, out); err != nil {
+		return fmt.Errorf("invalid response from %s: %w", path, err)
+	}
+	return nil
+}
+
+// callFlat is call for the few endpoints, such as /api/user/session/get,
+// that put their fields next to "status" instead of under "response".
+func (c *Client) callFlat(ctx context.Context, path string, q url.Values, out interface{}) error {
+	resp, err := c.Get(ctx, path, q)
+	return decodeFlat(resp, err, path, out)
+}
+
+// callFlatForm is callFlat with the parameters posted as a form instead, for
+// credentials such as those of /api/user/login.
+func (c *Client) callFlatForm(ctx context.Context, path string, form url.Values, out interface{}) error {
+	resp, err := c.Post(ctx, path, nil, strings.NewReader(form.Encode()), "application/x-www-form-urlencoded")
+	return decodeFlat(resp, err, path, out)
+}
+
+// decodeFlat decodes an API reply whose fields sit next to "status" into out.
+func decodeFlat(resp *http.Response, err error, path string, out interface{}) error {
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var env envelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	if env.Status != "ok" {
+		return &APIError{Status: env.Status, Message: env.ErrorMessage}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("invalid response from %s: %w", path, err)
+	}
+	return nil
+}
+
+func decodeEnvelope(r io.Reader) (map[string]interface{}, map[string]interface{}, error) {
+	var result map[string]interface{}
+	if err := json.NewDecoder(r).Decode(&result); err != nil {
+		return nil, nil, fmt.Errorf("invalid response: %w", err)
+	}
+	status, _ := result["status"].(string)
+	if status != "ok" {
+		msg, _ := result["errorMessage"].(string)
+		return result, nil, &APIError{Status: status, Message: msg}
+	}
+	response, _ := result["response"].(map[string]interface{})
+	return result, response, nil
+}
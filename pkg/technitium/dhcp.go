@@ -0,0 +1,113 @@
+package technitium
+
+import (
+	"context"
+	"net/url"
+)
+
+// DHCPScope is a DHCP scope as listed by /api/dhcp/scopes/list. The full
+// configuration of a scope comes from /api/dhcp/scopes/get.
+type DHCPScope struct {
+	Name             string `json:"name"`
+	Enabled          bool   `json:"enabled"`
+	StartingAddress  string `json:"startingAddress"`
+	EndingAddress    string `json:"endingAddress"`
+	SubnetMask       string `json:"subnetMask"`
+	NetworkAddress   string `json:"networkAddress"`
+	BroadcastAddress string `json:"broadcastAddress"`
+	InterfaceAddress string `json:"interfaceAddress,omitempty"`
+}
+
+// ListDHCPScopes lists the DHCP scopes of the server.
+func (c *Client) ListDHCPScopes(ctx context.Context) ([]DHCPScope, error) {
+	var out struct {
+		Scopes []DHCPScope `json:"scopes"`
+	}
+	if err := c.call(ctx, "/api/dhcp/scopes/list", nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Scopes, nil
+}
+
+// EnableDHCPScope starts serving leases from the named scope.
+func (c *Client) EnableDHCPScope(ctx context.Context, name string) error {
+	return c.call(ctx, "/api/dhcp/scopes/enable", url.Values{"name": {name}}, nil)
+}
+
+// DisableDHCPScope stops serving leases from the named scope.
+func (c *Client) DisableDHCPScope(ctx context.Context, name string) error {
+	return c.call(ctx, "/api/dhcp/scopes/disable", url.Values{"name": {name}}, nil)
+}
+
+// DeleteDHCPScope deletes the named scope and its leases.
+func (c *Client) DeleteDHCPScope(ctx context.Context, name string) error {
+	return c.call(ctx, "/api/dhcp/scopes/delete", url.Values{"name": {name}}, nil)
+}
+
+// DHCPLease is a lease given out by the DHCP server. Type is Dynamic or
+// Reserved. The lease times are formatted by the server.
+type DHCPLease struct {
+	Scope            string `json:"scope"`
+	Type             string `json:"type"`
+	HardwareAddress  string `json:"hardwareAddress"`
+	ClientIdentifier string `json:"clientIdentifier"`
+	Address          string `json:"address"`
+	HostName         string `json:"hostName"`
+	LeaseObtained    string `json:"leaseObtained"`
+	LeaseExpires     string `json:"leaseExpires"`
+}
+
+// ReservedLease is an address reserved in a scope for a MAC address.
+type ReservedLease struct {
+	HostName        string `json:"hostName,omitempty"`
+	HardwareAddress string `json:"hardwareAddress"`
+	Address         string `json:"address"`
+	Comments        string `json:"comments,omitempty"`
+}
+
+// ListDHCPLeases lists the leases of every scope.
+func (c *Client) ListDHCPLeases(ctx context.Context) ([]DHCPLease, error) {
+	var out struct {
+		Leases []DHCPLease `json:"leases"`
+	}
+	if err := c.call(ctx, "/api/dhcp/leases/list", nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Leases, nil
+}
+
+func leaseValues(scope, hardwareAddress string) url.Values {
+	return url.Values{"name": {scope}, "hardwareAddress": {hardwareAddress}}
+}
+
+// RemoveDHCPLease removes the lease of hardwareAddress in scope, freeing its
+// address.
+func (c *Client) RemoveDHCPLease(ctx context.Context, scope, hardwareAddress string) error {
+	return c.call(ctx, "/api/dhcp/leases/remove", leaseValues(scope, hardwareAddress), nil)
+}
+
+// ConvertToReservedLease turns the dynamic lease of hardwareAddress in scope
+// into a reservation, so the client keeps its address.
+func (c *Client) ConvertToReservedLease(ctx context.Context, scope, hardwareAddress string) error {
+	return c.call(ctx, "/api/dhcp/leases/convertToReserved", leaseValues(scope, hardwareAddress), nil)
+}
+
+// ConvertToDynamicLease turns the reserved lease of hardwareAddress in scope
+// back into a dynamic one.
+func (c *Client) ConvertToDynamicLease(ctx context.Context, scope, hardwareAddress string) error {
+	return c.call(ctx, "/api/dhcp/leases/convertToDynamic", leaseValues(scope, hardwareAddress), nil)
+}
+
+// AddReservedLease reserves lease.Address in scope for lease.HardwareAddress.
+func (c *Client) AddReservedLease(ctx context.Context, scope string, lease ReservedLease) error {
+	q := leaseValues(scope, lease.HardwareAddress)
+	q.Set("ipAddress", lease.Address)
+	setNonEmpty(q, "hostName", lease.HostName)
+	setNonEmpty(q, "comments", lease.Comments)
+	return c.call(ctx, "/api/dhcp/scopes/addReservedLease", q, nil)
+}
+
+// RemoveReservedLease removes the reservation of hardwareAddress from scope.
+func (c *Client) RemoveReservedLease(ctx context.Context, scope, hardwareAddress string) error {
+	return c.call(ctx, "/api/dhcp/scopes/removeReservedLease", leaseValues(scope, hardwareAddress), nil)
+}
@@ -0,0 +1,85 @@
+package technitium
+
+import (
+	"context"
+	"testing"
+)
+
+func TestListDHCPScopes(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok","response":{"scopes":[{"name":"Default","enabled":true,
+		"startingAddress":"192.168.1.1","endingAddress":"192.168.1.254","subnetMask":"255.255.255.0",
+		"networkAddress":"192.168.1.0","broadcastAddress":"192.168.1.255"}]}}`)
+
+	scopes, err := c.ListDHCPScopes(context.Background())
+	if err != nil {
+		t.Fatalf("ListDHCPScopes: %v", err)
+	}
+	if got.Path != "/api/dhcp/scopes/list" {
+		t.Errorf("path = %s", got.Path)
+	}
+	if len(scopes) != 1 || scopes[0].Name != "Default" || !scopes[0].Enabled || scopes[0].NetworkAddress != "192.168.1.0" {
+		t.Errorf("scopes = %+v", scopes)
+	}
+}
+
+func TestDHCPScopeActions(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok","response":{}}`)
+	ctx := context.Background()
+	for path, call := range map[string]func() error{
+		"/api/dhcp/scopes/enable":  func() error { return c.EnableDHCPScope(ctx, "Office") },
+		"/api/dhcp/scopes/disable": func() error { return c.DisableDHCPScope(ctx, "Office") },
+		"/api/dhcp/scopes/delete":  func() error { return c.DeleteDHCPScope(ctx, "Office") },
+	} {
+		if err := call(); err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		if got.Path != path || got.Query().Get("name") != "Office" {
+			t.Errorf("request = %s?%s, want %s", got.Path, got.RawQuery, path)
+		}
+	}
+}
+
+func TestListDHCPLeases(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok","response":{"leases":[{"scope":"Default","type":"Dynamic",
+		"hardwareAddress":"00-11-22-33-44-55","clientIdentifier":"1-001122334455","address":"192.168.1.5",
+		"hostName":"laptop.lan","leaseObtained":"05/01/2024 10:00:00","leaseExpires":"05/08/2024 10:00:00"}]}}`)
+
+	leases, err := c.ListDHCPLeases(context.Background())
+	if err != nil {
+		t.Fatalf("ListDHCPLeases: %v", err)
+	}
+	if got.Path != "/api/dhcp/leases/list" {
+		t.Errorf("path = %s", got.Path)
+	}
+	if len(leases) != 1 || leases[0].Address != "192.168.1.5" || leases[0].HardwareAddress != "00-11-22-33-44-55" {
+		t.Errorf("leases = %+v", leases)
+	}
+}
+
+func TestDHCPLeaseActions(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok","response":{}}`)
+	ctx := context.Background()
+	const mac = "00-11-22-33-44-55"
+	for path, call := range map[string]func() error{
+		"/api/dhcp/leases/remove":              func() error { return c.RemoveDHCPLease(ctx, "Office", mac) },
+		"/api/dhcp/leases/convertToReserved":   func() error { return c.ConvertToReservedLease(ctx, "Office", mac) },
+		"/api/dhcp/leases/convertToDynamic":    func() error { return c.ConvertToDynamicLease(ctx, "Office", mac) },
+		"/api/dhcp/scopes/removeReservedLease": func() error { return c.RemoveReservedLease(ctx, "Office", mac) },
+	} {
+		if err := call(); err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		if q := got.Query(); got.Path != path || q.Get("name") != "Office" || q.Get("hardwareAddress") != mac {
+			t.Errorf("request = %s?%s, want %s", got.Path, got.RawQuery, path)
+		}
+	}
+
+	lease := ReservedLease{HardwareAddress: mac, Address: "10.0.0.50", HostName: "printer"}
+	if err := c.AddReservedLease(ctx, "Office", lease); err != nil {
+		t.Fatalf("AddReservedLease: %v", err)
+	}
+	q := got.Query()
+	if got.Path != "/api/dhcp/scopes/addReservedLease" || q.Get("ipAddress") != "10.0.0.50" || q.Get("hostName") != "printer" || q.Has("comments") {
+		t.Errorf("request = %s?%s", got.Path, got.RawQuery)
+	}
+}
@@ -0,0 +1,197 @@
+package technitium
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// DNSSEC key types, as AddDNSSECKey and DNSSECKey name them.
+const (
+	KeySigningKey  = "KeySigningKey"
+	ZoneSigningKey = "ZoneSigningKey"
+)
+
+// SignOptions configures SignZone. Algorithm is RSA, ECDSA or EDDSA; the
+// RSA fields (HashAlgorithm and the key sizes) are only sent for RSA and
+// Curve only for the others. Zero values are not sent, except that a zero
+// ZSKRolloverDays disables automatic ZSK rollover.
+type SignOptions struct {
+	Algorithm     string
+	HashAlgorithm string // MD5, SHA1, SHA256 or SHA512
+	KSKKeySize    int
+	ZSKKeySize    int
+	Curve         string // P256 or P384 for ECDSA, ED25519 or ED448 for EDDSA
+	// NxProof is how non-existence is proven: NSEC or NSEC3.
+	NxProof         string
+	Iterations      int // NSEC3 only
+	SaltLength      int // NSEC3 only
+	DNSKEYTTL       int
+	ZSKRolloverDays int
+}
+
+func (o SignOptions) values() url.Values {
+	q := url.Values{}
+	q.Set("algorithm", o.Algorithm)
+	if o.Algorithm == "RSA" {
+		setNonEmpty(q, "hashAlgorithm", o.HashAlgorithm)
+		setPositive(q, "kskKeySize", o.KSKKeySize)
+		setPositive(q, "zskKeySize", o.ZSKKeySize)
+	} else {
+		setNonEmpty(q, "curve", o.Curve)
+	}
+	setNonEmpty(q, "nxProof", o.NxProof)
+	if o.NxProof == "NSEC3" {
+		q.Set("iterations", strconv.Itoa(o.Iterations))
+		q.Set("saltLength", strconv.Itoa(o.SaltLength))
+	}
+	setPositive(q, "dnsKeyTtl", o.DNSKEYTTL)
+	q.Set("zskRolloverDays", strconv.Itoa(o.ZSKRolloverDays))
+	return q
+}
+
+// AddKeyOptions configures AddDNSSECKey. KeyType is KeySigningKey or
+// ZoneSigningKey; the algorithm fields are as for SignOptions.
+type AddKeyOptions struct {
+	KeyType       string
+	Algorithm     string
+	HashAlgorithm string
+	KeySize       int
+	Curve         string
+	RolloverDays  int // ZSKs only; 0 disables automatic rollover
+}
+
+func (o AddKeyOptions) values() url.Values {
+	q := url.Values{}
+	q.Set("keyType", o.KeyType)
+	q.Set("algorithm", o.Algorithm)
+	if o.Algorithm == "RSA" {
+		setNonEmpty(q, "hashAlgorithm", o.HashAlgorithm)
+		setPositive(q, "keySize", o.KeySize)
+	} else {
+		setNonEmpty(q, "curve", o.Curve)
+	}
+	if o.KeyType == ZoneSigningKey {
+		q.Set("rolloverDays", strconv.Itoa(o.RolloverDays))
+	}
+	return q
+}
+
+// DNSSECKey is one of a zone's DNSSEC private keys. State is one of
+// Generated, Published, Ready, Active, Retired or Revoked; StateReadyBy is
+// set while the key waits for the next state.
+type DNSSECKey struct {
+	KeyTag         uint16 `json:"keyTag"`
+	KeyType        string `json:"keyType"`
+	Algorithm      string `json:"algorithm"`
+	State          string `json:"state"`
+	StateChangedOn string `json:"stateChangedOn"`
+	StateReadyBy   string `json:"stateReadyBy,omitempty"`
+	IsRetiring     bool   `json:"isRetiring"`
+	RolloverDays   int    `json:"rolloverDays"`
+}
+
+// DNSSECProperties are the DNSSEC settings and keys of a zone.
+type DNSSECProperties struct {
+	Name              string      `json:"name"`
+	Type              string      `json:"type"`
+	Internal          bool        `json:"internal"`
+	Disabled          bool        `json:"disabled"`
+	DNSSECStatus      string      `json:"dnssecStatus"`
+	NSEC3Iterations   int         `json:"nsec3Iterations,omitempty"`
+	NSEC3SaltLength   int         `json:"nsec3SaltLength,omitempty"`
+	DNSKEYTTL         uint32      `json:"dnsKeyTtl"`
+	DNSSECPrivateKeys []DNSSECKey `json:"dnssecPrivateKeys"`
+}
+
+// DSDigest is one digest of a DS record.
+type DSDigest struct {
+	DigestType string `json:"digestType"`
+	Digest     string `json:"digest"`
+}
+
+// DSRecord holds the DS record data for one of a zone's key signing keys,
+// with a digest for each digest type the server offers.
+type DSRecord struct {
+	KeyTag             uint16     `json:"keyTag"`
+	DNSKeyState        string     `json:"dnsKeyState"`
+	DNSKeyStateReadyBy string     `json:"dnsKeyStateReadyBy,omitempty"`
+	Algorithm          string     `json:"algorithm"`
+	PublicKey          string     `json:"publicKey"`
+	Digests            []DSDigest `json:"digests"`
+}
+
+// SignZone signs a primary zone with new keys.
+func (c *Client) SignZone(ctx context.Context, zone string, opts SignOptions) error {
+	q := opts.values()
+	q.Set("zone", zone)
+	return c.call(ctx, "/api/zones/dnssec/sign", q, nil)
+}
+
+// UnsignZone removes DNSSEC from a zone, deleting its keys.
+func (c *Client) UnsignZone(ctx context.Context, zone string) error {
+	return c.call(ctx, "/api/zones/dnssec/unsign", url.Values{"zone": {zone}}, nil)
+}
+
+// GetDNSSECProperties returns the DNSSEC settings and keys of a zone.
+func (c *Client) GetDNSSECProperties(ctx context.Context, zone string) (*DNSSECProperties, error) {
+	var props DNSSECProperties
+	if err := c.call(ctx, "/api/zones/dnssec/properties/get", url.Values{"zone": {zone}}, &props); err != nil {
+		return nil, err
+	}
+	return &props, nil
+}
+
+// AddDNSSECKey generates a private key for a signed zone. The key is not
+// published until PublishAllDNSSECKeys.
+func (c *Client) AddDNSSECKey(ctx context.Context, zone string, opts AddKeyOptions) error {
+	q := opts.values()
+	q.Set("zone", zone)
+	return c.call(ctx, "/api/zones/dnssec/properties/addPrivateKey", q, nil)
+}
+
+// PublishAllDNSSECKeys publishes the zone's generated keys, starting their
+// way to active.
+func (c *Client) PublishAllDNSSECKeys(ctx context.Context, zone string) error {
+	return c.call(ctx, "/api/zones/dnssec/properties/publishAllPrivateKeys", url.Values{"zone": {zone}}, nil)
+}
+
+// RolloverDNSKey replaces the active key with the given tag by a new one of
+// the same kind, retiring the old key once the new one is active.
+func (c *Client) RolloverDNSKey(ctx context.Context, zone string, keyTag uint16) error {
+	return c.call(ctx, "/api/zones/dnssec/properties/rolloverDnsKey", keyTagValues(zone, keyTag), nil)
+}
+
+// RetireDNSKey retires the key with the given tag. The server refuses when
+// no other active key of the same kind would be left.
+func (c *Client) RetireDNSKey(ctx context.Context, zone string, keyTag uint16) error {
+	return c.call(ctx, "/api/zones/dnssec/properties/retireDnsKey", keyTagValues(zone, keyTag), nil)
+}
+
+// ViewDS returns the DS record data of the zone's key signing keys, for
+// publishing at the parent zone.
+func (c *Client) ViewDS(ctx context.Context, zone string) ([]DSRecord, error) {
+	var out struct {
+		DSRecords []DSRecord `json:"dsRecords"`
+	}
+	if err := c.call(ctx, "/api/zones/dnssec/viewDS", url.Values{"zone": {zone}}, &out); err != nil {
+		return nil, err
+	}
+	return out.DSRecords, nil
+}
+
+func keyTagValues(zone string, keyTag uint16) url.Values {
+	return url.Values{"zone": {zone}, "keyTag": {strconv.Itoa(int(keyTag))}}
+}
+
+func setNonEmpty(q url.Values, key, value string) {
+	if value != "" {
+		q.Set(key, value)
+	}
+}
+
+func setPositive(q url.Values, key string, value int) {
+	if value > 0 {
+		q.Set(key, strconv.Itoa(value))
+	}
+}
@@ -0,0 +1,89 @@
+package technitium
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSignZone(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok","response":{}}`)
+	err := c.SignZone(context.Background(), "example.com", SignOptions{
+		Algorithm: "ECDSA", Curve: "P256", HashAlgorithm: "SHA256", KSKKeySize: 2048,
+		NxProof: "NSEC3", DNSKEYTTL: 3600, ZSKRolloverDays: 30,
+	})
+	if err != nil {
+		t.Fatalf("SignZone: %v", err)
+	}
+	if got.Path != "/api/zones/dnssec/sign" {
+		t.Errorf("path = %q", got.Path)
+	}
+	q := got.Query()
+	if q.Get("zone") != "example.com" || q.Get("algorithm") != "ECDSA" || q.Get("curve") != "P256" ||
+		q.Get("nxProof") != "NSEC3" || q.Get("iterations") != "0" || q.Get("saltLength") != "0" ||
+		q.Get("dnsKeyTtl") != "3600" || q.Get("zskRolloverDays") != "30" {
+		t.Errorf("query = %v", q)
+	}
+	// The RSA parameters don't apply to ECDSA.
+	if q.Has("hashAlgorithm") || q.Has("kskKeySize") || q.Has("zskKeySize") {
+		t.Errorf("query has RSA parameters: %v", q)
+	}
+}
+
+func TestAddDNSSECKeyRSA(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok","response":{}}`)
+	err := c.AddDNSSECKey(context.Background(), "example.com", AddKeyOptions{
+		KeyType: KeySigningKey, Algorithm: "RSA", HashAlgorithm: "SHA256", KeySize: 2048, Curve: "P256",
+	})
+	if err != nil {
+		t.Fatalf("AddDNSSECKey: %v", err)
+	}
+	q := got.Query()
+	if got.Path != "/api/zones/dnssec/properties/addPrivateKey" || q.Get("keyType") != KeySigningKey ||
+		q.Get("hashAlgorithm") != "SHA256" || q.Get("keySize") != "2048" || q.Has("curve") || q.Has("rolloverDays") {
+		t.Errorf("request = %s?%v", got.Path, q)
+	}
+}
+
+func TestGetDNSSECProperties(t *testing.T) {
+	c, _ := stubServer(t, `{"status":"ok","response":{"name":"example.com","type":"Primary","dnssecStatus":"SignedWithNSEC3",
+		"dnsKeyTtl":3600,"dnssecPrivateKeys":[{"keyTag":12345,"keyType":"KeySigningKey","algorithm":"ECDSAP256SHA256",
+		"state":"Active","stateChangedOn":"2024-01-01T00:00:00Z","isRetiring":false,"rolloverDays":0}]}}`)
+
+	props, err := c.GetDNSSECProperties(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("GetDNSSECProperties: %v", err)
+	}
+	if props.DNSSECStatus != "SignedWithNSEC3" || props.DNSKEYTTL != 3600 || len(props.DNSSECPrivateKeys) != 1 {
+		t.Fatalf("props = %+v", props)
+	}
+	if k := props.DNSSECPrivateKeys[0]; k.KeyTag != 12345 || k.KeyType != KeySigningKey || k.State != "Active" {
+		t.Errorf("key = %+v", k)
+	}
+}
+
+func TestRolloverDNSKey(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok","response":{}}`)
+	if err := c.RolloverDNSKey(context.Background(), "example.com", 54321); err != nil {
+		t.Fatalf("RolloverDNSKey: %v", err)
+	}
+	if got.Path != "/api/zones/dnssec/properties/rolloverDnsKey" || got.Query().Get("keyTag") != "54321" {
+		t.Errorf("request = %s?%s", got.Path, got.RawQuery)
+	}
+}
+
+func TestViewDS(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok","response":{"name":"example.com","dsRecords":[{"keyTag":12345,
+		"dnsKeyState":"Ready","algorithm":"ECDSAP256SHA256","publicKey":"abc",
+		"digests":[{"digestType":"SHA256","digest":"DEADBEEF"}]}]}}`)
+
+	ds, err := c.ViewDS(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("ViewDS: %v", err)
+	}
+	if got.Path != "/api/zones/dnssec/viewDS" {
+		t.Errorf("path = %q", got.Path)
+	}
+	if len(ds) != 1 || ds[0].KeyTag != 12345 || len(ds[0].Digests) != 1 || ds[0].Digests[0].Digest != "DEADBEEF" {
+		t.Errorf("ds = %+v", ds)
+	}
+}
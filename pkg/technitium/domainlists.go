@@ -0,0 +1,86 @@
+package technitium
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// The server's two lists of domains that override blocking: allowed zones are
+// never blocked, blocked zones always are. Each list covers the domain and
+// its subdomains.
+const (
+	AllowedList = "allowed"
+	BlockedList = "blocked"
+)
+
+// listParam is the parameter /api/<list>/import takes the domains in.
+func listParam(list string) string {
+	return list + "Zones"
+}
+
+// ExportDomains returns every domain in list (AllowedList or BlockedList),
+// in the order the server exports them.
+func (c *Client) ExportDomains(ctx context.Context, list string) ([]string, error) {
+	resp, err := c.Get(ctx, "/api/"+list+"/export", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	// Errors come back as the usual JSON envelope instead of the text file.
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		if _, _, err := decodeEnvelope(resp.Body); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("unexpected JSON response from /api/%s/export", list)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("exporting %s zones: HTTP %d", list, resp.StatusCode)
+	}
+	return ReadDomains(resp.Body)
+}
+
+// ReadDomains reads a list of domains, one per line. Blank lines and
+// comments starting with # are skipped, and trailing dots are dropped.
+func ReadDomains(r io.Reader) ([]string, error) {
+	var domains []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		line = strings.TrimSuffix(strings.TrimSpace(line), ".")
+		if line == "" {
+			continue
+		}
+		if strings.ContainsAny(line, " \t") {
+			return nil, fmt.Errorf("invalid domain %q: one domain per line", line)
+		}
+		domains = append(domains, line)
+	}
+	return domains, sc.Err()
+}
+
+// AddDomain adds domain to list.
+func (c *Client) AddDomain(ctx context.Context, list, domain string) error {
+	return c.call(ctx, "/api/"+list+"/add", url.Values{"domain": {domain}}, nil)
+}
+
+// DeleteDomain removes domain from list.
+func (c *Client) DeleteDomain(ctx context.Context, list, domain string) error {
+	return c.call(ctx, "/api/"+list+"/delete", url.Values{"domain": {domain}}, nil)
+}
+
+// FlushDomains empties list.
+func (c *Client) FlushDomains(ctx context.Context, list string) error {
+	return c.call(ctx, "/api/"+list+"/flush", nil, nil)
+}
+
+// ImportDomains adds domains to list, keeping the domains already in it. The
+// domains are posted as a form, as there may be too many for a URL.
+func (c *Client) ImportDomains(ctx context.Context, list string, domains []string) error {
+	form := url.Values{listParam(list): {strings.Join(domains, ",")}}
+	return c.callPost(ctx, "/api/"+list+"/import", nil, strings.NewReader(form.Encode()), "application/x-www-form-urlencoded", nil)
+}
@@ -0,0 +1,72 @@
+package technitium
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExportDomains(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/blocked/export" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "ads.example\r\ntracker.example.\r\n\r\n")
+	}))
+	defer srv.Close()
+
+	got, err := (&Client{Host: srv.URL, HTTP: srv.Client()}).ExportDomains(context.Background(), BlockedList)
+	if err != nil {
+		t.Fatalf("ExportDomains: %v", err)
+	}
+	if want := []string{"ads.example", "tracker.example"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("domains = %v, want %v", got, want)
+	}
+}
+
+func TestExportDomainsAPIError(t *testing.T) {
+	c, _ := stubServer(t, `{"status":"error","errorMessage":"Access was denied."}`)
+	if _, err := c.ExportDomains(context.Background(), AllowedList); err == nil || !strings.Contains(err.Error(), "Access was denied") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestReadDomains(t *testing.T) {
+	got, err := ReadDomains(strings.NewReader("# blocked\nads.example\n\n  tracker.example.  # old\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"ads.example", "tracker.example"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("domains = %v, want %v", got, want)
+	}
+	if _, err := ReadDomains(strings.NewReader("a.example b.example\n")); err == nil {
+		t.Error("two domains on a line were accepted")
+	}
+}
+
+func TestImportDomains(t *testing.T) {
+	var form url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/allowed/import" || r.Method != http.MethodPost {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		r.ParseForm()
+		form = r.PostForm
+		io.WriteString(w, `{"status":"ok","response":{}}`)
+	}))
+	defer srv.Close()
+
+	c := &Client{Host: srv.URL, HTTP: srv.Client()}
+	if err := c.ImportDomains(context.Background(), AllowedList, []string{"a.example", "b.example"}); err != nil {
+		t.Fatalf("ImportDomains: %v", err)
+	}
+	if got := form.Get("allowedZones"); got != "a.example,b.example" {
+		t.Errorf("allowedZones = %q", got)
+	}
+}
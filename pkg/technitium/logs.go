@@ -0,0 +1,170 @@
+package technitium
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// LogFile is a server log file. Size is formatted by the server, for
+// example "8.5 KB".
+type LogFile struct {
+	FileName string `json:"fileName"`
+	Size     string `json:"size"`
+}
+
+// ListLogs lists the server's log files, newest first.
+func (c *Client) ListLogs(ctx context.Context) ([]LogFile, error) {
+	var out struct {
+		LogFiles []LogFile `json:"logFiles"`
+	}
+	if err := c.call(ctx, "/api/logs/list", nil, &out); err != nil {
+		return nil, err
+	}
+	return out.LogFiles, nil
+}
+
+// ReadLog returns the log file fileName from byte offset on, and the size of
+// the file. Only that part is asked for, with a Range header; when the server
+// sends the whole file anyway the start is skipped here. When the file is now
+// shorter than offset, as after it was replaced, all of it is returned.
+func (c *Client) ReadLog(ctx context.Context, fileName string, offset int64) ([]byte, int64, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/logs/download", url.Values{"fileName": {fileName}}, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	// Errors come back as the usual JSON envelope instead of the log file.
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		if _, _, err := decodeEnvelope(resp.Body); err != nil {
+			return nil, 0, err
+		}
+		return nil, 0, fmt.Errorf("unexpected JSON response from /api/logs/download")
+	}
+
+	switch resp.StatusCode {
+	case http.StatusRequestedRangeNotSatisfiable:
+		// Nothing past offset: either the file hasn't grown, or it shrank.
+		var size int64
+		if _, err := fmt.Sscanf(resp.Header.Get("Content-Range"), "bytes */%d", &size); err == nil && size == offset {
+			return nil, size, nil
+		}
+		return c.ReadLog(ctx, fileName, 0)
+	case http.StatusPartialContent:
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, 0, err
+		}
+		return data, offset + int64(len(data)), nil
+	case http.StatusOK:
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, 0, err
+		}
+		size := int64(len(data))
+		if offset <= size {
+			data = data[offset:]
+		}
+		return data, size, nil
+	}
+	return nil, 0, fmt.Errorf("downloading log %s: HTTP %d", fileName, resp.StatusCode)
+}
+
+// DeleteLog deletes the named log file.
+func (c *Client) DeleteLog(ctx context.Context, fileName string) error {
+	return c.call(ctx, "/api/logs/delete", url.Values{"log": {fileName}}, nil)
+}
+
+// DeleteAllLogs deletes every log file.
+func (c *Client) DeleteAllLogs(ctx context.Context) error {
+	return c.call(ctx, "/api/logs/deleteAll", nil, nil)
+}
+
+// QueryLogOptions selects the query logger app and filters the entries of
+// QueryLogs. Empty fields are not filtered on.
+type QueryLogOptions struct {
+	App       string // name of the installed app that logs queries
+	ClassPath string // its query logger class
+
+	PageNumber     int
+	EntriesPerPage int
+	Ascending      bool // oldest entries first; the server default is newest first
+
+	Start        time.Time
+	End          time.Time
+	ClientIP     string
+	Protocol     string // Udp, Tcp, Tls, Https or Quic
+	ResponseType string // for example Authoritative, Recursive, Cached or Blocked
+	RCode        string // for example NoError or NxDomain
+	QName        string
+	QType        string
+	QClass       string
+}
+
+func (o QueryLogOptions) values() url.Values {
+	q := url.Values{"name": {o.App}, "classPath": {o.ClassPath}}
+	setPositive(q, "pageNumber", o.PageNumber)
+	setPositive(q, "entriesPerPage", o.EntriesPerPage)
+	if o.Ascending {
+		q.Set("descendingOrder", "false")
+	}
+	if !o.Start.IsZero() {
+		q.Set("start", o.Start.UTC().Format(time.RFC3339))
+	}
+	if !o.End.IsZero() {
+		q.Set("end", o.End.UTC().Format(time.RFC3339))
+	}
+	setNonEmpty(q, "clientIpAddress", o.ClientIP)
+	setNonEmpty(q, "protocol", o.Protocol)
+	setNonEmpty(q, "responseType", o.ResponseType)
+	setNonEmpty(q, "rcode", o.RCode)
+	setNonEmpty(q, "qname", o.QName)
+	setNonEmpty(q, "qtype", o.QType)
+	setNonEmpty(q, "qclass", o.QClass)
+	return q
+}
+
+// QueryLogEntry is a logged query and the answer it got. ResponseRTT, in
+// milliseconds, is only set for answers from upstream servers.
+type QueryLogEntry struct {
+	RowNumber       int64    `json:"rowNumber"`
+	Timestamp       string   `json:"timestamp"`
+	ClientIPAddress string   `json:"clientIpAddress"`
+	Protocol        string   `json:"protocol"`
+	ResponseType    string   `json:"responseType"`
+	ResponseRTT     *float64 `json:"responseRtt,omitempty"`
+	RCode           string   `json:"rcode"`
+	QName           string   `json:"qname"`
+	QType           string   `json:"qtype"`
+	QClass          string   `json:"qclass"`
+	Answer          string   `json:"answer"`
+}
+
+// QueryLogPage is a page of QueryLogs results.
+type QueryLogPage struct {
+	PageNumber   int             `json:"pageNumber"`
+	TotalPages   int             `json:"totalPages"`
+	TotalEntries int64           `json:"totalEntries"`
+	Entries      []QueryLogEntry `json:"entries"`
+}
+
+// QueryLogs searches the queries logged by a query logger app, such as
+// Query Logs (Sqlite).
+func (c *Client) QueryLogs(ctx context.Context, opts QueryLogOptions) (*QueryLogPage, error) {
+	var page QueryLogPage
+	if err := c.call(ctx, "/api/logs/query", opts.values(), &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
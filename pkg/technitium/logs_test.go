@@ -0,0 +1,89 @@
+package technitium
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestListLogs(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok","response":{"logFiles":[{"fileName":"2024-01-02","size":"8.5 KB"},{"fileName":"2024-01-01","size":"1 KB"}]}}`)
+	logs, err := c.ListLogs(context.Background())
+	if err != nil {
+		t.Fatalf("ListLogs: %v", err)
+	}
+	if got.Path != "/api/logs/list" || len(logs) != 2 || logs[0].FileName != "2024-01-02" || logs[0].Size != "8.5 KB" {
+		t.Errorf("path %q, logs = %+v", got.Path, logs)
+	}
+}
+
+func TestDeleteLog(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok"}`)
+	if err := c.DeleteLog(context.Background(), "2024-01-01"); err != nil {
+		t.Fatalf("DeleteLog: %v", err)
+	}
+	if got.Path != "/api/logs/delete" || got.Query().Get("log") != "2024-01-01" {
+		t.Errorf("request = %v", got)
+	}
+}
+
+func TestQueryLogs(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok","response":{"pageNumber":1,"totalPages":4,"totalEntries":76,"entries":[
+		{"rowNumber":1,"timestamp":"2026-10-17T09:30:00.123Z","clientIpAddress":"10.0.0.9","protocol":"Udp","responseType":"Recursive",
+		"responseRtt":12.5,"rcode":"NoError","qname":"example.com","qtype":"A","qclass":"IN","answer":"93.184.215.14"}]}}`)
+
+	page, err := c.QueryLogs(context.Background(), QueryLogOptions{
+		App: "Query Logs (Sqlite)", ClassPath: "QueryLogsSqlite.App", EntriesPerPage: 25, Ascending: true,
+		Start: time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC), QName: "example.com", RCode: "NoError",
+	})
+	if err != nil {
+		t.Fatalf("QueryLogs: %v", err)
+	}
+	q := got.Query()
+	if got.Path != "/api/logs/query" || q.Get("name") != "Query Logs (Sqlite)" || q.Get("classPath") != "QueryLogsSqlite.App" ||
+		q.Get("entriesPerPage") != "25" || q.Get("descendingOrder") != "false" || q.Get("start") != "2026-10-17T09:00:00Z" ||
+		q.Get("qname") != "example.com" || q.Get("rcode") != "NoError" || q.Has("end") || q.Has("pageNumber") || q.Has("qtype") {
+		t.Errorf("request = %v", got)
+	}
+	if page.TotalEntries != 76 || len(page.Entries) != 1 || page.Entries[0].ResponseRTT == nil || *page.Entries[0].ResponseRTT != 12.5 {
+		t.Errorf("page = %+v", page)
+	}
+}
+
+func TestReadLog(t *testing.T) {
+	const log = "line 1\nline 2\n"
+	for _, ranges := range []bool{true, false} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("fileName") != "2026-10-17" {
+				w.Header().Set("Content-Type", "application/json")
+				io.WriteString(w, `{"status":"error","errorMessage":"Could not find file."}`)
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain")
+			if ranges {
+				http.ServeContent(w, r, "", time.Time{}, strings.NewReader(log))
+				return
+			}
+			io.WriteString(w, log)
+		}))
+		c := &Client{Host: srv.URL, HTTP: srv.Client()}
+
+		for _, tt := range []struct {
+			offset int64
+			want   string
+		}{{0, log}, {7, "line 2\n"}, {14, ""}, {20, log}} {
+			data, size, err := c.ReadLog(context.Background(), "2026-10-17", tt.offset)
+			if err != nil || string(data) != tt.want || size != int64(len(log)) {
+				t.Errorf("ranges %v, offset %d = %q, %d, %v; want %q", ranges, tt.offset, data, size, err, tt.want)
+			}
+		}
+		if _, _, err := c.ReadLog(context.Background(), "2026-10-01", 0); err == nil || !strings.Contains(err.Error(), "Could not find file.") {
+			t.Errorf("missing file: err = %v", err)
+		}
+		srv.Close()
+	}
+}
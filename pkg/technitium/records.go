@@ -0,0 +1,107 @@
+package technitium
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// Record is a resource record as returned by the records API. RData holds
+// the type-specific fields, keyed as the API names them (for example
+// "ipAddress" for A records or "exchange" and "preference" for MX).
+type Record struct {
+	Name         string                 `json:"name"`
+	Type         string                 `json:"type"`
+	TTL          uint32                 `json:"ttl"`
+	Disabled     bool                   `json:"disabled"`
+	RData        map[string]interface{} `json:"rData"`
+	DNSSECStatus string                 `json:"dnssecStatus,omitempty"`
+	Comments     string                 `json:"comments,omitempty"`
+	ExpiryTTL    uint32                 `json:"expiryTtl,omitempty"`
+	LastUsedOn   string                 `json:"lastUsedOn,omitempty"`
+	LastModified string                 `json:"lastModified,omitempty"`
+}
+
+// Records is the response of GetRecords.
+type Records struct {
+	Zone    Zone     `json:"zone"`
+	Records []Record `json:"records"`
+}
+
+// GetRecords returns the records of domain in zone. With listZone set it
+// returns every record in the zone instead, domain then naming the zone.
+func (c *Client) GetRecords(ctx context.Context, zone, domain string, listZone bool) (*Records, error) {
+	q := url.Values{
+		"domain":   {domain},
+		"zone":     {zone},
+		"listZone": {strconv.FormatBool(listZone)},
+	}
+	var recs Records
+	if err := c.call(ctx, "/api/zones/records/get", q, &recs); err != nil {
+		return nil, err
+	}
+	return &recs, nil
+}
+
+// RecordRequest identifies a record for AddRecord, UpdateRecord and
+// DeleteRecord. Params carries the type-specific parameters, named as the
+// API names them: "ipAddress" for A records, "exchange" and "preference" for
+// MX, and for updates the "new"-prefixed replacements such as "newIpAddress".
+type RecordRequest struct {
+	Zone     string
+	Domain   string
+	Type     string
+	TTL      int // 0 leaves the server default
+	Comments string
+	// Overwrite replaces the existing record set on add.
+	Overwrite bool
+	Params    url.Values
+}
+
+func (r RecordRequest) values() url.Values {
+	q := url.Values{}
+	for k, v := range r.Params {
+		q[k] = v
+	}
+	q.Set("zone", r.Zone)
+	q.Set("domain", r.Domain)
+	q.Set("type", r.Type)
+	if r.TTL > 0 {
+		q.Set("ttl", strconv.Itoa(r.TTL))
+	}
+	if r.Comments != "" {
+		q.Set("comments", r.Comments)
+	}
+	if r.Overwrite {
+		q.Set("overwrite", "true")
+	}
+	return q
+}
+
+// AddRecord adds a record and returns it as stored by the server.
+func (c *Client) AddRecord(ctx context.Context, r RecordRequest) (*Record, error) {
+	var out struct {
+		AddedRecord Record `json:"addedRecord"`
+	}
+	if err := c.call(ctx, "/api/zones/records/add", r.values(), &out); err != nil {
+		return nil, err
+	}
+	return &out.AddedRecord, nil
+}
+
+// UpdateRecord changes a record and returns it as stored by the server.
+func (c *Client) UpdateRecord(ctx context.Context, r RecordRequest) (*Record, error) {
+	var out struct {
+		UpdatedRecord Record `json:"updatedRecord"`
+	}
+	if err := c.call(ctx, "/api/zones/records/update", r.values(), &out); err != nil {
+		return nil, err
+	}
+	return &out.UpdatedRecord, nil
+}
+
+// DeleteRecord deletes a record. TTL, Comments and Overwrite are ignored.
+func (c *Client) DeleteRecord(ctx context.Context, r RecordRequest) error {
+	r.TTL, r.Comments, r.Overwrite = 0, "", false
+	return c.call(ctx, "/api/zones/records/delete", r.values(), nil)
+}
@@ -0,0 +1,76 @@
+package technitium
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestGetRecords(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok","response":{"zone":{"name":"example.com","type":"Primary"},
+		"records":[{"name":"www.example.com","type":"A","ttl":3600,"rData":{"ipAddress":"192.0.2.1"}},
+		{"name":"example.com","type":"TXT","ttl":60,"comments":null,"rData":{"text":"hi"}}]}}`)
+
+	recs, err := c.GetRecords(context.Background(), "example.com", "example.com", true)
+	if err != nil {
+		t.Fatalf("GetRecords: %v", err)
+	}
+	if q := got.Query(); q.Get("listZone") != "true" || q.Get("zone") != "example.com" {
+		t.Errorf("query = %v", q)
+	}
+	if recs.Zone.Type != "Primary" || len(recs.Records) != 2 {
+		t.Fatalf("records = %+v", recs)
+	}
+	if r := recs.Records[0]; r.TTL != 3600 || r.RData["ipAddress"] != "192.0.2.1" {
+		t.Errorf("record = %+v", r)
+	}
+}
+
+func TestAddRecord(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok","response":{"zone":{"name":"example.com"},
+		"addedRecord":{"name":"mail.example.com","type":"MX","ttl":300,"rData":{"preference":10,"exchange":"mx.example.com"}}}}`)
+
+	rec, err := c.AddRecord(context.Background(), RecordRequest{
+		Zone: "example.com", Domain: "mail.example.com", Type: "MX", TTL: 300, Overwrite: true,
+		Params: url.Values{"preference": {"10"}, "exchange": {"mx.example.com"}},
+	})
+	if err != nil {
+		t.Fatalf("AddRecord: %v", err)
+	}
+	if got.Path != "/api/zones/records/add" {
+		t.Errorf("path = %q", got.Path)
+	}
+	q := got.Query()
+	for k, want := range map[string]string{"zone": "example.com", "domain": "mail.example.com", "type": "MX",
+		"ttl": "300", "overwrite": "true", "preference": "10", "exchange": "mx.example.com"} {
+		if q.Get(k) != want {
+			t.Errorf("query %s = %q, want %q", k, q.Get(k), want)
+		}
+	}
+	if rec.Type != "MX" || rec.TTL != 300 {
+		t.Errorf("record = %+v", rec)
+	}
+}
+
+func TestDeleteRecordSendsOnlyIdentity(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok","response":{}}`)
+	err := c.DeleteRecord(context.Background(), RecordRequest{Zone: "example.com", Domain: "www.example.com", Type: "A", TTL: 60,
+		Comments: "x", Params: url.Values{"ipAddress": {"192.0.2.1"}}})
+	if err != nil {
+		t.Fatalf("DeleteRecord: %v", err)
+	}
+	q := got.Query()
+	if q.Has("ttl") || q.Has("comments") || q.Get("ipAddress") != "192.0.2.1" {
+		t.Errorf("query = %v", q)
+	}
+}
+
+func TestRecordAPIError(t *testing.T) {
+	c, _ := stubServer(t, `{"status":"error","errorMessage":"Zone does not exist."}`)
+	_, err := c.GetRecords(context.Background(), "nope.example", "nope.example", true)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "Zone does not exist." {
+		t.Errorf("err = %v, want the API error", err)
+	}
+}
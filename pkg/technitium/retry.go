@@ -0,0 +1,130 @@
+package technitium
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// DefaultMaxRetryWait caps a single backoff delay when RetryPolicy.MaxWait
+// is not set.
+const DefaultMaxRetryWait = 30 * time.Second
+
+// RetryPolicy controls how a Client retries requests that failed for a
+// transient reason: the connection being refused or reset, a timeout, or a
+// 502, 503 or 504 response. The zero value makes a single attempt.
+//
+// The Technitium API uses GET for everything, including calls that change
+// the server, so only calls that ReadOnly recognizes are retried unless
+// RetryWrites is set. A write that timed out may still have been applied,
+// which is why retrying it is opt-in.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, the first included.
+	MaxAttempts int
+	// Wait is the delay before the first retry. It doubles with every
+	// further retry, up to MaxWait, and is jittered by up to half.
+	Wait    time.Duration
+	MaxWait time.Duration
+	// RetryWrites also retries calls that are not read-only.
+	RetryWrites bool
+}
+
+// readOnlyPrefixes and readOnlyNames classify the last segment of an API
+// path, such as "list" in /api/zones/list or "getTop" in
+// /api/dashboard/stats/getTop.
+var (
+	readOnlyPrefixes = []string{"get", "list", "view", "check"}
+	readOnlyNames    = map[string]bool{"export": true, "download": true, "backup": true, "query": true}
+)
+
+// ReadOnly reports whether the API call at path only reads server state and
+// so is safe to repeat.
+func ReadOnly(path string) bool {
+	name := strings.ToLower(path[strings.LastIndex(path, "/")+1:])
+	if readOnlyNames[name] {
+		return true
+	}
+	for _, p := range readOnlyPrefixes {
+		if strings.HasPrefix(name, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryable reports why the outcome of an attempt is worth retrying, or ""
+// when it isn't.
+func retryable(resp *http.Response, err error) string {
+	switch {
+	case err != nil && isTimeout(err):
+		return "timeout"
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return "connection refused"
+	case errors.Is(err, syscall.ECONNRESET):
+		return "connection reset"
+	case err != nil:
+		return ""
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return fmt.Sprintf("HTTP %d", resp.StatusCode)
+	}
+	return ""
+}
+
+// backoff is the jittered delay before retry number n (1 for the first).
+func (p RetryPolicy) backoff(n int) time.Duration {
+	maxWait := p.MaxWait
+	if maxWait <= 0 {
+		maxWait = DefaultMaxRetryWait
+	}
+	d := p.Wait
+	for i := 1; i < n && d < maxWait; i++ {
+		d *= 2
+	}
+	d = min(d, maxWait)
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// attempts is how many times req may be tried under the policy.
+func (p RetryPolicy) attempts(req *http.Request) int {
+	if p.MaxAttempts <= 1 {
+		return 1
+	}
+	if !p.RetryWrites && !ReadOnly(req.URL.Path) {
+		return 1
+	}
+	// A body that can't be rewound can only be sent once.
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+func (c *Client) logger() *slog.Logger {
+	if c.Logger != nil {
+		return c.Logger
+	}
+	return slog.Default()
+}
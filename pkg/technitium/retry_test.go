@@ -0,0 +1,174 @@
+package technitium
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyServer fails the first failures requests with status, then answers
+// with an ok envelope. It returns the client and the request counter.
+func flakyServer(t *testing.T, failures int, status int) (*Client, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if int(calls.Add(1)) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		if r.Method == http.MethodPost && string(body) != "payload" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"ok","response":{}}`))
+	}))
+	t.Cleanup(srv.Close)
+	c := &Client{Host: srv.URL, HTTP: srv.Client(), Retry: RetryPolicy{MaxAttempts: 3, Wait: time.Millisecond}}
+	return c, &calls
+}
+
+func TestReadOnly(t *testing.T) {
+	cases := map[string]bool{
+		"/api/zones/list":                true,
+		"/api/zones/records/get":         true,
+		"/api/dashboard/stats/getTop":    true,
+		"/api/zones/export":              true,
+		"/api/settings/backup":           true,
+		"/api/logs/download":             true,
+		"/api/user/checkForUpdate":       true,
+		"/api/zones/dnssec/viewDS":       true,
+		"/api/zones/records/add":         false,
+		"/api/zones/delete":              false,
+		"/api/settings/set":              false,
+		"/api/user/login":                false,
+		"/api/dashboard/stats/deleteAll": false,
+	}
+	for path, want := range cases {
+		if got := ReadOnly(path); got != want {
+			t.Errorf("ReadOnly(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestRetryReadOn503(t *testing.T) {
+	c, calls := flakyServer(t, 2, http.StatusServiceUnavailable)
+	if _, _, err := c.GetJSON(context.Background(), "/api/zones/list", nil); err != nil {
+		t.Fatalf("GetJSON: %v", err)
+	}
+	if n := calls.Load(); n != 3 {
+		t.Errorf("calls = %d, want 3", n)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	c, calls := flakyServer(t, 5, http.StatusBadGateway)
+	resp, err := c.Get(context.Background(), "/api/zones/list", nil)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("status = %d, want the last 502", resp.StatusCode)
+	}
+	if n := calls.Load(); n != 3 {
+		t.Errorf("calls = %d, want 3", n)
+	}
+}
+
+func TestNoRetryOnOtherStatus(t *testing.T) {
+	c, calls := flakyServer(t, 1, http.StatusInternalServerError)
+	resp, err := c.Get(context.Background(), "/api/zones/list", nil)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+	if n := calls.Load(); n != 1 {
+		t.Errorf("calls = %d, want 1", n)
+	}
+}
+
+func TestNoRetryOfWritesByDefault(t *testing.T) {
+	c, calls := flakyServer(t, 1, http.StatusServiceUnavailable)
+	if _, _, err := c.GetJSON(context.Background(), "/api/zones/delete", nil); err == nil {
+		t.Fatal("expected the 503 to be returned")
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("calls = %d, want 1", n)
+	}
+}
+
+func TestRetryWritesOptInResendsBody(t *testing.T) {
+	c, calls := flakyServer(t, 1, http.StatusServiceUnavailable)
+	c.Retry.RetryWrites = true
+	resp, err := c.Post(context.Background(), "/api/zones/import", nil, strings.NewReader("payload"), "text/plain")
+	if err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200 (body not resent?)", resp.StatusCode)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("calls = %d, want 2", n)
+	}
+}
+
+func TestRetryConnectionRefused(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	var calls atomic.Int32
+	c := &Client{Host: "http://" + addr, HTTP: &http.Client{}, Retry: RetryPolicy{MaxAttempts: 3, Wait: time.Millisecond}}
+	c.HTTP.Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		calls.Add(1)
+		return http.DefaultTransport.RoundTrip(r)
+	})
+	_, err = c.Get(context.Background(), "/api/zones/list", nil)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if n := calls.Load(); n != 3 {
+		t.Errorf("calls = %d, want 3 (err: %v)", n, err)
+	}
+}
+
+func TestRetryStopsWhenCancelled(t *testing.T) {
+	c, calls := flakyServer(t, 5, http.StatusServiceUnavailable)
+	c.Retry.Wait = time.Hour
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.Get(ctx, "/api/zones/list", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("calls = %d, want 1", n)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := RetryPolicy{Wait: 100 * time.Millisecond, MaxWait: time.Second}
+	for n, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond, 10: time.Second} {
+		for range 20 {
+			if d := p.backoff(n); d < want/2 || d > want {
+				t.Errorf("backoff(%d) = %v, want within [%v, %v]", n, d, want/2, want)
+			}
+		}
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
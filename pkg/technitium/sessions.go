@@ -0,0 +1,152 @@
+package technitium
+
+import (
+	"context"
+	"net/url"
+)
+
+// Session is a logged in session or API token.
+type Session struct {
+	Username              string `json:"username"`
+	IsCurrentSession      bool   `json:"isCurrentSession"`
+	PartialToken          string `json:"partialToken"`
+	Type                  string `json:"type"`
+	TokenName             string `json:"tokenName"`
+	LastSeen              string `json:"lastSeen"`
+	LastSeenRemoteAddress string `json:"lastSeenRemoteAddress"`
+	LastSeenUserAgent     string `json:"lastSeenUserAgent"`
+}
+
+// APIToken is a newly created API token.
+type APIToken struct {
+	Username  string `json:"username"`
+	TokenName string `json:"tokenName"`
+	Token     string `json:"token"`
+}
+
+// User is a user account. Sessions, Groups and MemberOfGroups are only
+// filled in by GetUser.
+type User struct {
+	DisplayName                  string    `json:"displayName"`
+	Username                     string    `json:"username"`
+	Disabled                     bool      `json:"disabled"`
+	PreviousSessionLoggedOn      string    `json:"previousSessionLoggedOn"`
+	PreviousSessionRemoteAddress string    `json:"previousSessionRemoteAddress"`
+	RecentSessionLoggedOn        string    `json:"recentSessionLoggedOn"`
+	RecentSessionRemoteAddress   string    `json:"recentSessionRemoteAddress"`
+	SessionTimeoutSeconds        int       `json:"sessionTimeoutSeconds,omitempty"`
+	MemberOfGroups               []string  `json:"memberOfGroups,omitempty"`
+	Sessions                     []Session `json:"sessions,omitempty"`
+	Groups                       []string  `json:"groups,omitempty"`
+}
+
+// ListSessions lists the active sessions of every user.
+func (c *Client) ListSessions(ctx context.Context) ([]Session, error) {
+	var out struct {
+		Sessions []Session `json:"sessions"`
+	}
+	if err := c.call(ctx, "/api/admin/sessions/list", nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Sessions, nil
+}
+
+// DeleteSession ends the session with the given partial token.
+func (c *Client) DeleteSession(ctx context.Context, partialToken string) error {
+	return c.call(ctx, "/api/admin/sessions/delete", url.Values{"partialToken": {partialToken}}, nil)
+}
+
+// CreateToken creates a named API token for user.
+func (c *Client) CreateToken(ctx context.Context, user, tokenName string) (*APIToken, error) {
+	var tok APIToken
+	q := url.Values{"user": {user}, "tokenName": {tokenName}}
+	if err := c.call(ctx, "/api/admin/sessions/createToken", q, &tok); err != nil {
+		return nil, err
+	}
+	return &tok, nil
+}
+
+// ListUsers lists the user accounts.
+func (c *Client) ListUsers(ctx context.Context) ([]User, error) {
+	var out struct {
+		Users []User `json:"users"`
+	}
+	if err := c.call(ctx, "/api/admin/users/list", nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Users, nil
+}
+
+// GetUser returns the details of a user, including its sessions and the
+// groups it may be added to.
+func (c *Client) GetUser(ctx context.Context, username string) (*User, error) {
+	var u User
+	q := url.Values{"user": {username}, "includeGroups": {"true"}}
+	if err := c.call(ctx, "/api/admin/users/get", q, &u); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// UserSession describes the user behind a session token, as returned by
+// Login and CurrentSession.
+type UserSession struct {
+	DisplayName string      `json:"displayName"`
+	Username    string      `json:"username"`
+	TOTPEnabled bool        `json:"totpEnabled"`
+	Token       string      `json:"token,omitempty"`
+	Info        SessionInfo `json:"info"`
+}
+
+// SessionInfo is the server information sent along with a session.
+type SessionInfo struct {
+	Version          string                `json:"version"`
+	UptimeStamp      string                `json:"uptimestamp"`
+	DNSServerDomain  string                `json:"dnsServerDomain"`
+	DefaultRecordTTL int                   `json:"defaultRecordTtl"`
+	Permissions      map[string]Permission `json:"permissions"`
+}
+
+// Permission is what a user may do in one section of the server.
+type Permission struct {
+	CanView   bool `json:"canView"`
+	CanModify bool `json:"canModify"`
+	CanDelete bool `json:"canDelete"`
+}
+
+// ErrTOTPRequired is the APIError status of a login that needs a TOTP code.
+const ErrTOTPRequired = "2fa-required"
+
+// Login starts a session for user and returns it, its token included. totp
+// is the 2FA code, or "" for accounts without 2FA. The credentials are posted
+// as a form, so they stay out of URLs, and the client's own token is not
+// sent.
+func (c *Client) Login(ctx context.Context, user, password, totp string) (*UserSession, error) {
+	anon := *c
+	anon.Token, anon.LegacyToken = "", false
+	q := url.Values{"user": {user}, "pass": {password}, "includeInfo": {"true"}}
+	if totp != "" {
+		q.Set("totp", totp)
+	}
+	var s UserSession
+	if err := anon.callFlatForm(ctx, "/api/user/login", q, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Logout ends the session of the client's token. An API token made with
+// CreateToken is deleted by this too.
+func (c *Client) Logout(ctx context.Context) error {
+	return c.callFlat(ctx, "/api/user/logout", nil, nil)
+}
+
+// CurrentSession returns the user and server information of the client's
+// token.
+func (c *Client) CurrentSession(ctx context.Context) (*UserSession, error) {
+	var s UserSession
+	if err := c.callFlat(ctx, "/api/user/session/get", nil, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
@@ -0,0 +1,110 @@
+package technitium
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestListSessionsToleratesMissingFields(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok","response":{"sessions":[
+		{"username":"admin","isCurrentSession":true,"partialToken":"abc","type":"Standard","tokenName":null,"lastSeen":"2024-01-01T00:00:00Z"},
+		{"partialToken":"def"}]}}`)
+
+	sessions, err := c.ListSessions(context.Background())
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	if got.Path != "/api/admin/sessions/list" {
+		t.Errorf("path = %q", got.Path)
+	}
+	if len(sessions) != 2 || !sessions[0].IsCurrentSession || sessions[1].IsCurrentSession || sessions[1].PartialToken != "def" {
+		t.Errorf("sessions = %+v", sessions)
+	}
+}
+
+func TestCreateToken(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok","response":{"username":"admin","tokenName":"ci","token":"secret"}}`)
+	tok, err := c.CreateToken(context.Background(), "admin", "ci")
+	if err != nil {
+		t.Fatalf("CreateToken: %v", err)
+	}
+	if q := got.Query(); q.Get("user") != "admin" || q.Get("tokenName") != "ci" {
+		t.Errorf("query = %v", q)
+	}
+	if tok.Token != "secret" {
+		t.Errorf("token = %+v", tok)
+	}
+}
+
+func TestGetUser(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok","response":{"displayName":"Admin","username":"admin","disabled":false,
+		"sessionTimeoutSeconds":1800,"memberOfGroups":["Administrators"],"groups":["Administrators","Everyone"],
+		"sessions":[{"partialToken":"abc","type":"ApiToken"}]}}`)
+	u, err := c.GetUser(context.Background(), "admin")
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if got.Query().Get("includeGroups") != "true" {
+		t.Errorf("query = %v", got.Query())
+	}
+	if u.SessionTimeoutSeconds != 1800 || len(u.Groups) != 2 || len(u.Sessions) != 1 {
+		t.Errorf("user = %+v", u)
+	}
+}
+
+func TestLogin(t *testing.T) {
+	var requestURI, auth string
+	var form url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestURI, auth = r.RequestURI, r.Header.Get("Authorization")
+		if r.URL.Path != "/api/user/login" || r.Method != http.MethodPost {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		r.ParseForm()
+		form = r.PostForm
+		io.WriteString(w, `{"displayName":"Administrator","username":"admin","totpEnabled":true,"token":"sess",
+			"info":{"version":"13.2","dnsServerDomain":"dns1","permissions":{"Zones":{"canView":true,"canModify":true,"canDelete":false}}},"status":"ok"}`)
+	}))
+	defer srv.Close()
+
+	c := &Client{Host: srv.URL, HTTP: srv.Client(), Token: "old-token", LegacyToken: true}
+	s, err := c.Login(context.Background(), "admin", "s3cret", "123456")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if form.Get("user") != "admin" || form.Get("pass") != "s3cret" || form.Get("totp") != "123456" || form.Has("token") || auth != "" {
+		t.Errorf("form = %v, Authorization = %q", form, auth)
+	}
+	if strings.Contains(requestURI, "s3cret") || strings.Contains(requestURI, "123456") {
+		t.Errorf("the credentials are in the URL: %s", requestURI)
+	}
+	if s.Token != "sess" || s.Username != "admin" || !s.TOTPEnabled || s.Info.Version != "13.2" || !s.Info.Permissions["Zones"].CanModify {
+		t.Errorf("session = %+v", s)
+	}
+}
+
+func TestLoginTOTPRequired(t *testing.T) {
+	c, _ := stubServer(t, `{"status":"2fa-required","errorMessage":"A time-based one-time password (TOTP) is required."}`)
+	_, err := c.Login(context.Background(), "admin", "pw", "")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Status != ErrTOTPRequired {
+		t.Fatalf("err = %v, want APIError with status %q", err, ErrTOTPRequired)
+	}
+}
+
+func TestCurrentSession(t *testing.T) {
+	c, got := stubServer(t, `{"displayName":"Administrator","username":"admin","token":"sess","info":{"version":"13.2"},"status":"ok"}`)
+	s, err := c.CurrentSession(context.Background())
+	if err != nil {
+		t.Fatalf("CurrentSession: %v", err)
+	}
+	if got.Path != "/api/user/session/get" || s.Username != "admin" || s.Info.Version != "13.2" {
+		t.Errorf("path = %q, session = %+v", got.Path, s)
+	}
+}
@@ -0,0 +1,120 @@
+package technitium
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// TSIGKey is a TSIG key configured on the server.
+type TSIGKey struct {
+	KeyName       string `json:"keyName"`
+	SharedSecret  string `json:"sharedSecret"`
+	AlgorithmName string `json:"algorithmName"`
+}
+
+// Settings holds the commonly used DNS server settings. The server returns
+// many more; Raw has every one of them as sent.
+type Settings struct {
+	Version                      string   `json:"version"`
+	Uptimestamp                  string   `json:"uptimestamp"`
+	DNSServerDomain              string   `json:"dnsServerDomain"`
+	DNSServerLocalEndPoints      []string `json:"dnsServerLocalEndPoints"`
+	DNSServerIPv4SourceAddresses []string `json:"dnsServerIPv4SourceAddresses"`
+	DNSServerIPv6SourceAddresses []string `json:"dnsServerIPv6SourceAddresses"`
+
+	WebServiceHTTPPort  int  `json:"webServiceHttpPort"`
+	WebServiceTLSPort   int  `json:"webServiceTlsPort"`
+	WebServiceEnableTLS bool `json:"webServiceEnableTls"`
+
+	EnableBlocking                  bool     `json:"enableBlocking"`
+	BlockingType                    string   `json:"blockingType"`
+	BlockingAnswerTTL               int      `json:"blockingAnswerTtl"`
+	CustomBlockingAddresses         []string `json:"customBlockingAddresses"`
+	BlockListURLs                   []string `json:"blockListUrls"`
+	BlockListURLUpdateIntervalHours int      `json:"blockListUrlUpdateIntervalHours"`
+	TemporaryDisableBlockingTill    string   `json:"temporaryDisableBlockingTill,omitempty"`
+
+	DNSSECValidation      bool  `json:"dnssecValidation"`
+	SaveCache             bool  `json:"saveCache"`
+	ServeStale            bool  `json:"serveStale"`
+	CacheMaximumEntries   int64 `json:"cacheMaximumEntries"`
+	CacheFailureRecordTTL int   `json:"cacheFailureRecordTtl"`
+	CachePrefetchTrigger  int   `json:"cachePrefetchTrigger"`
+
+	Forwarders           []string `json:"forwarders"`
+	ForwarderProtocol    string   `json:"forwarderProtocol"`
+	ForwarderTimeout     int      `json:"forwarderTimeout"`
+	ConcurrentForwarding bool     `json:"concurrentForwarding"`
+
+	EnableLogging       bool   `json:"enableLogging"`
+	LogFolder           string `json:"logFolder"`
+	MaxLogFileDays      int    `json:"maxLogFileDays"`
+	EnableInMemoryStats bool   `json:"enableInMemoryStats"`
+
+	TSIGKeys []TSIGKey `json:"tsigKeys"`
+
+	Raw map[string]interface{} `json:"-"`
+}
+
+// UnmarshalJSON fills the typed fields and keeps every setting in Raw.
+func (s *Settings) UnmarshalJSON(data []byte) error {
+	type plain Settings
+	if err := json.Unmarshal(data, (*plain)(s)); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &s.Raw)
+}
+
+// GetSettings returns the DNS server settings.
+func (c *Client) GetSettings(ctx context.Context) (*Settings, error) {
+	var s Settings
+	if err := c.call(ctx, "/api/settings/get", nil, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// SetTSIGKeys replaces the server's TSIG keys with keys; other settings are
+// left alone. An empty list deletes every key. The keys are posted as a form
+// so their secrets stay out of URLs.
+func (c *Client) SetTSIGKeys(ctx context.Context, keys []TSIGKey) error {
+	// The API takes the keys as one pipe-separated list of
+	// name|secret|algorithm rows, or "false" for none, so a "|" in a field
+	// would shift every key after it.
+	value := "false"
+	if len(keys) > 0 {
+		fields := make([]string, 0, 3*len(keys))
+		for _, k := range keys {
+			if strings.Contains(k.KeyName+k.SharedSecret+k.AlgorithmName, "|") {
+				return fmt.Errorf("TSIG key %q: fields must not contain \"|\"", k.KeyName)
+			}
+			fields = append(fields, k.KeyName, k.SharedSecret, k.AlgorithmName)
+		}
+		value = strings.Join(fields, "|")
+	}
+	return c.callForm(ctx, "/api/settings/set", url.Values{"tsigKeys": {value}}, nil)
+}
+
+// UpdateInfo is the result of CheckForUpdate.
+type UpdateInfo struct {
+	UpdateAvailable  bool   `json:"updateAvailable"`
+	CurrentVersion   string `json:"currentVersion"`
+	UpdateVersion    string `json:"updateVersion"`
+	UpdateTitle      string `json:"updateTitle"`
+	UpdateMessage    string `json:"updateMessage"`
+	DownloadLink     string `json:"downloadLink"`
+	InstructionsLink string `json:"instructionsLink"`
+	ChangeLogLink    string `json:"changeLogLink"`
+}
+
+// CheckForUpdate asks the server whether a newer release is available.
+func (c *Client) CheckForUpdate(ctx context.Context) (*UpdateInfo, error) {
+	var u UpdateInfo
+	if err := c.call(ctx, "/api/user/checkForUpdate", nil, &u); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
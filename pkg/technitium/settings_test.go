@@ -0,0 +1,82 @@
+package technitium
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestGetSettingsKeepsRaw(t *testing.T) {
+	c, _ := stubServer(t, `{"status":"ok","response":{"version":"13.6","dnsServerDomain":"dns.example",
+		"forwarderTimeout":2000,"tsigKeys":[{"keyName":"k1","algorithmName":"hmac-sha256","sharedSecret":"c2VjcmV0"}],
+		"someNewSetting":true}}`)
+
+	s, err := c.GetSettings(context.Background())
+	if err != nil {
+		t.Fatalf("GetSettings: %v", err)
+	}
+	if s.Version != "13.6" || s.ForwarderTimeout != 2000 || len(s.TSIGKeys) != 1 || s.TSIGKeys[0].AlgorithmName != "hmac-sha256" {
+		t.Errorf("settings = %+v", s)
+	}
+	if s.Raw["someNewSetting"] != true {
+		t.Errorf("Raw is missing settings without a typed field: %v", s.Raw)
+	}
+}
+
+func TestCheckForUpdate(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok","response":{"updateAvailable":true,"currentVersion":"13.0","updateVersion":"13.1"}}`)
+	u, err := c.CheckForUpdate(context.Background())
+	if err != nil {
+		t.Fatalf("CheckForUpdate: %v", err)
+	}
+	if got.Path != "/api/user/checkForUpdate" || !u.UpdateAvailable || u.UpdateVersion != "13.1" {
+		t.Errorf("path %q, update = %+v", got.Path, u)
+	}
+}
+
+func TestSetTSIGKeys(t *testing.T) {
+	var requestURI string
+	var form url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestURI = r.RequestURI
+		if r.URL.Path != "/api/settings/set" || r.Method != http.MethodPost {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		r.ParseForm()
+		form = r.PostForm
+		io.WriteString(w, `{"status":"ok","response":{}}`)
+	}))
+	defer srv.Close()
+
+	c := &Client{Host: srv.URL, HTTP: srv.Client()}
+	keys := []TSIGKey{
+		{KeyName: "k1", SharedSecret: "c2VjcmV0", AlgorithmName: "hmac-sha256"},
+		{KeyName: "k2", SharedSecret: "b3RoZXI=", AlgorithmName: "hmac-sha512"},
+	}
+	if err := c.SetTSIGKeys(context.Background(), keys); err != nil {
+		t.Fatalf("SetTSIGKeys: %v", err)
+	}
+	if len(form) != 1 || form.Get("tsigKeys") != "k1|c2VjcmV0|hmac-sha256|k2|b3RoZXI=|hmac-sha512" {
+		t.Errorf("form = %v", form)
+	}
+	if strings.Contains(requestURI, "c2VjcmV0") || strings.Contains(requestURI, "tsigKeys") {
+		t.Errorf("the keys are in the URL: %s", requestURI)
+	}
+
+	if err := c.SetTSIGKeys(context.Background(), nil); err != nil {
+		t.Fatalf("SetTSIGKeys: %v", err)
+	}
+	if v := form.Get("tsigKeys"); v != "false" {
+		t.Errorf("tsigKeys = %q, want false for no keys", v)
+	}
+
+	form = nil
+	bad := []TSIGKey{{KeyName: "k1|x", SharedSecret: "c2VjcmV0", AlgorithmName: "hmac-sha256"}}
+	if err := c.SetTSIGKeys(context.Background(), bad); err == nil || form != nil {
+		t.Errorf("a key name with | was sent: err = %v, form = %v", err, form)
+	}
+}
@@ -0,0 +1,123 @@
+package technitium
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Periods of the dashboard statistics.
+const (
+	StatsLastHour  = "LastHour"
+	StatsLastDay   = "LastDay"
+	StatsLastWeek  = "LastWeek"
+	StatsLastMonth = "LastMonth"
+	StatsLastYear  = "LastYear"
+	StatsCustom    = "Custom"
+)
+
+// Kinds of top-N list for GetTopStats.
+const (
+	TopClients        = "TopClients"
+	TopDomains        = "TopDomains"
+	TopBlockedDomains = "TopBlockedDomains"
+)
+
+// StatsOptions chooses the period of GetStats and GetTopStats. Start and
+// End are only used, and then required, for StatsCustom.
+type StatsOptions struct {
+	Type  string // one of the Stats* periods; empty means StatsLastHour
+	Start time.Time
+	End   time.Time
+}
+
+func (o StatsOptions) values() url.Values {
+	q := url.Values{"utc": {"true"}}
+	typ := o.Type
+	if typ == "" {
+		typ = StatsLastHour
+	}
+	q.Set("type", typ)
+	if typ == StatsCustom {
+		q.Set("start", o.Start.UTC().Format(time.RFC3339))
+		q.Set("end", o.End.UTC().Format(time.RFC3339))
+	}
+	return q
+}
+
+// StatsTotals are the query counters of a period.
+type StatsTotals struct {
+	TotalQueries       int64 `json:"totalQueries"`
+	TotalNoError       int64 `json:"totalNoError"`
+	TotalServerFailure int64 `json:"totalServerFailure"`
+	TotalNxDomain      int64 `json:"totalNxDomain"`
+	TotalRefused       int64 `json:"totalRefused"`
+	TotalAuthoritative int64 `json:"totalAuthoritative"`
+	TotalRecursive     int64 `json:"totalRecursive"`
+	TotalCached        int64 `json:"totalCached"`
+	TotalBlocked       int64 `json:"totalBlocked"`
+	TotalDropped       int64 `json:"totalDropped"`
+	TotalClients       int64 `json:"totalClients"`
+	Zones              int64 `json:"zones"`
+	CachedEntries      int64 `json:"cachedEntries"`
+	AllowedZones       int64 `json:"allowedZones"`
+	BlockedZones       int64 `json:"blockedZones"`
+	AllowListZones     int64 `json:"allowListZones"`
+	BlockListZones     int64 `json:"blockListZones"`
+}
+
+// TopEntry is one line of a top-N list: a client address (with its
+// resolved Domain, if any) or a queried domain.
+type TopEntry struct {
+	Name        string `json:"name"`
+	Domain      string `json:"domain,omitempty"`
+	Hits        int64  `json:"hits"`
+	RateLimited bool   `json:"rateLimited,omitempty"`
+}
+
+// DashboardStats are the dashboard statistics of a period. The chart data
+// the server also returns is left out.
+type DashboardStats struct {
+	Stats             StatsTotals `json:"stats"`
+	TopClients        []TopEntry  `json:"topClients"`
+	TopDomains        []TopEntry  `json:"topDomains"`
+	TopBlockedDomains []TopEntry  `json:"topBlockedDomains"`
+}
+
+// GetStats returns the dashboard statistics of a period, with the top 10 of
+// each top-N list.
+func (c *Client) GetStats(ctx context.Context, opts StatsOptions) (*DashboardStats, error) {
+	var s DashboardStats
+	if err := c.call(ctx, "/api/dashboard/stats/get", opts.values(), &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// GetTopStats returns up to limit entries of the top-N list statsType (one
+// of TopClients, TopDomains and TopBlockedDomains) for a period.
+func (c *Client) GetTopStats(ctx context.Context, opts StatsOptions, statsType string, limit int) ([]TopEntry, error) {
+	q := opts.values()
+	q.Set("statsType", statsType)
+	q.Set("limit", strconv.Itoa(limit))
+	// The list is keyed by the stats type with a lower-case first letter,
+	// such as "topClients".
+	var out map[string]json.RawMessage
+	if err := c.call(ctx, "/api/dashboard/stats/getTop", q, &out); err != nil {
+		return nil, err
+	}
+	key := strings.ToLower(statsType[:1]) + statsType[1:]
+	raw, ok := out[key]
+	if !ok {
+		return nil, fmt.Errorf("response has no %s list", key)
+	}
+	var list []TopEntry
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", key, err)
+	}
+	return list, nil
+}
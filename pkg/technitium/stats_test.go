@@ -0,0 +1,51 @@
+package technitium
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGetStats(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok","response":{"stats":{"totalQueries":100,"totalNxDomain":7,"totalBlocked":3,
+		"totalCached":40,"totalRecursive":50,"totalClients":4},"mainChartData":{"labels":[]},
+		"topClients":[{"name":"192.0.2.1","domain":"pc.lan","hits":60,"rateLimited":false}],
+		"topDomains":[{"name":"example.com","hits":12}],"topBlockedDomains":[]}}`)
+
+	s, err := c.GetStats(context.Background(), StatsOptions{Type: StatsLastDay})
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if got.Path != "/api/dashboard/stats/get" || got.Query().Get("type") != "LastDay" || got.Query().Has("start") {
+		t.Errorf("request = %s?%s", got.Path, got.RawQuery)
+	}
+	if s.Stats.TotalQueries != 100 || s.Stats.TotalNxDomain != 7 || s.Stats.TotalClients != 4 {
+		t.Errorf("totals = %+v", s.Stats)
+	}
+	if len(s.TopClients) != 1 || s.TopClients[0].Domain != "pc.lan" || s.TopDomains[0].Hits != 12 {
+		t.Errorf("stats = %+v", s)
+	}
+}
+
+func TestGetTopStatsCustomPeriod(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok","response":{"topBlockedDomains":[{"name":"ads.example","hits":9},{"name":"t.example","hits":2}]}}`)
+
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	opts := StatsOptions{Type: StatsCustom, Start: start, End: start.Add(24 * time.Hour)}
+	top, err := c.GetTopStats(context.Background(), opts, TopBlockedDomains, 50)
+	if err != nil {
+		t.Fatalf("GetTopStats: %v", err)
+	}
+	q := got.Query()
+	if got.Path != "/api/dashboard/stats/getTop" || q.Get("statsType") != "TopBlockedDomains" || q.Get("limit") != "50" ||
+		q.Get("start") != "2024-05-01T00:00:00Z" || q.Get("end") != "2024-05-02T00:00:00Z" {
+		t.Errorf("request = %s?%v", got.Path, q)
+	}
+	if len(top) != 2 || top[0].Name != "ads.example" {
+		t.Errorf("top = %+v", top)
+	}
+
+	if _, err := c.GetTopStats(context.Background(), opts, TopClients, 10); err == nil {
+		t.Error("want an error when the list is missing")
+	}
+}
@@ -0,0 +1,102 @@
+package technitium
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// TLSOptions configures how a Client verifies the server's certificate and
+// authenticates itself, for servers on the HTTPS web service port or behind a
+// reverse proxy. The zero value uses the system roots and Go's defaults.
+type TLSOptions struct {
+	// CAFile is a PEM bundle of CA certificates to trust in addition to the
+	// system roots, for self-signed or internal-CA certificates.
+	CAFile string
+	// CertFile and KeyFile are a PEM client certificate and its key, for
+	// proxies that require mutual TLS. Both or neither must be set.
+	CertFile string
+	KeyFile  string
+	// InsecureSkipVerify accepts any server certificate. It leaves the
+	// connection open to interception and is meant for testing only.
+	InsecureSkipVerify bool
+	// MinVersion is the lowest TLS version accepted: "1.0", "1.1", "1.2" or
+	// "1.3". Empty keeps Go's default.
+	MinVersion string
+}
+
+// IsZero reports whether o changes nothing from the defaults.
+func (o TLSOptions) IsZero() bool {
+	return o == TLSOptions{}
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ParseTLSVersion converts a version such as "1.2" or "TLS1.2" to its
+// crypto/tls constant.
+func ParseTLSVersion(s string) (uint16, error) {
+	v := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "tls")
+	if id, ok := tlsVersions[strings.TrimSpace(v)]; ok {
+		return id, nil
+	}
+	return 0, fmt.Errorf("unknown TLS version %q (use 1.0, 1.1, 1.2 or 1.3)", s)
+}
+
+// Config builds the tls.Config described by o.
+func (o TLSOptions) Config() (*tls.Config, error) {
+	cfg := &tls.Config{InsecureSkipVerify: o.InsecureSkipVerify}
+
+	if o.MinVersion != "" {
+		v, err := ParseTLSVersion(o.MinVersion)
+		if err != nil {
+			return nil, err
+		}
+		cfg.MinVersion = v
+	}
+
+	if o.CAFile != "" {
+		pem, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in CA bundle %s", o.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	switch {
+	case o.CertFile != "" && o.KeyFile != "":
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	case o.CertFile != "" || o.KeyFile != "":
+		return nil, fmt.Errorf("a client certificate needs both a certificate and a key file")
+	}
+	return cfg, nil
+}
+
+// Transport returns a copy of http.DefaultTransport using o's TLS settings.
+func (o TLSOptions) Transport() (*http.Transport, error) {
+	cfg, err := o.Config()
+	if err != nil {
+		return nil, err
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.TLSClientConfig = cfg
+	return t, nil
+}
@@ -0,0 +1,183 @@
+package technitium
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// tlsServer starts an HTTPS stub answering with an ok envelope, configured
+// by setup before it starts.
+func tlsServer(t *testing.T, setup func(*tls.Config)) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"ok","response":{"version":"13.0"}}`))
+	}))
+	srv.TLS = &tls.Config{}
+	if setup != nil {
+		setup(srv.TLS)
+	}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// writePEM writes blocks of the given type to a file in dir.
+func writePEM(t *testing.T, dir, name, typ string, blocks ...[]byte) string {
+	t.Helper()
+	var buf []byte
+	for _, b := range blocks {
+		buf = append(buf, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: b})...)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, buf, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// clientCert makes a self-signed client certificate and returns its DER
+// form, the parsed certificate and the DER of its key.
+func clientCert(t *testing.T) ([]byte, *x509.Certificate, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "tdns test client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der, cert, keyDER
+}
+
+func tlsClient(t *testing.T, host string, opts TLSOptions) *Client {
+	t.Helper()
+	tr, err := opts.Transport()
+	if err != nil {
+		t.Fatalf("Transport: %v", err)
+	}
+	return &Client{Host: host, HTTP: &http.Client{Transport: tr, Timeout: 5 * time.Second}}
+}
+
+func TestTLSUnknownCAFails(t *testing.T) {
+	srv := tlsServer(t, nil)
+	c := tlsClient(t, srv.URL, TLSOptions{})
+	if _, err := c.ServerVersion(context.Background()); err == nil || !strings.Contains(err.Error(), "certificate") {
+		t.Fatalf("err = %v, want a certificate error", err)
+	}
+}
+
+func TestTLSCAFile(t *testing.T) {
+	srv := tlsServer(t, nil)
+	ca := writePEM(t, t.TempDir(), "ca.pem", "CERTIFICATE", srv.Certificate().Raw)
+	c := tlsClient(t, srv.URL, TLSOptions{CAFile: ca})
+	if _, err := c.ServerVersion(context.Background()); err != nil {
+		t.Fatalf("ServerVersion: %v", err)
+	}
+}
+
+func TestTLSInsecureSkipVerify(t *testing.T) {
+	srv := tlsServer(t, nil)
+	c := tlsClient(t, srv.URL, TLSOptions{InsecureSkipVerify: true})
+	if _, err := c.ServerVersion(context.Background()); err != nil {
+		t.Fatalf("ServerVersion: %v", err)
+	}
+}
+
+func TestTLSClientCertificate(t *testing.T) {
+	der, cert, keyDER := clientCert(t)
+	srv := tlsServer(t, func(cfg *tls.Config) {
+		pool := x509.NewCertPool()
+		pool.AddCert(cert)
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	})
+	dir := t.TempDir()
+	ca := writePEM(t, dir, "ca.pem", "CERTIFICATE", srv.Certificate().Raw)
+
+	without := tlsClient(t, srv.URL, TLSOptions{CAFile: ca})
+	if _, err := without.ServerVersion(context.Background()); err == nil {
+		t.Fatal("expected the server to reject a client without a certificate")
+	}
+
+	opts := TLSOptions{
+		CAFile:   ca,
+		CertFile: writePEM(t, dir, "client.pem", "CERTIFICATE", der),
+		KeyFile:  writePEM(t, dir, "client.key", "EC PRIVATE KEY", keyDER),
+	}
+	if _, err := tlsClient(t, srv.URL, opts).ServerVersion(context.Background()); err != nil {
+		t.Fatalf("ServerVersion with client certificate: %v", err)
+	}
+}
+
+func TestTLSMinVersion(t *testing.T) {
+	srv := tlsServer(t, func(cfg *tls.Config) { cfg.MaxVersion = tls.VersionTLS12 })
+	c := tlsClient(t, srv.URL, TLSOptions{InsecureSkipVerify: true, MinVersion: "1.3"})
+	if _, err := c.ServerVersion(context.Background()); err == nil {
+		t.Fatal("expected a TLS 1.2 server to be rejected with MinVersion 1.3")
+	}
+	c = tlsClient(t, srv.URL, TLSOptions{InsecureSkipVerify: true, MinVersion: "1.2"})
+	if _, err := c.ServerVersion(context.Background()); err != nil {
+		t.Fatalf("ServerVersion: %v", err)
+	}
+}
+
+func TestTLSConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "empty.pem")
+	if err := os.WriteFile(notPEM, []byte("nothing here"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]TLSOptions{
+		"missing CA file":  {CAFile: filepath.Join(dir, "missing.pem")},
+		"CA file not PEM":  {CAFile: notPEM},
+		"cert without key": {CertFile: notPEM},
+		"bad key pair":     {CertFile: notPEM, KeyFile: notPEM},
+		"unknown version":  {MinVersion: "1.4"},
+	}
+	for name, opts := range cases {
+		if _, err := opts.Config(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestParseTLSVersion(t *testing.T) {
+	for in, want := range map[string]uint16{"1.2": tls.VersionTLS12, "TLS1.3": tls.VersionTLS13, " tls 1.0 ": tls.VersionTLS10} {
+		if got, err := ParseTLSVersion(in); err != nil || got != want {
+			t.Errorf("ParseTLSVersion(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+}
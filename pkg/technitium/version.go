@@ -1,6 +1,7 @@
-package api
+package technitium
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -68,8 +69,8 @@ func CompareVersions(a, b string) (int, error) {
 
 // ServerAtLeast reports whether the connected server's version is at least
 // min. It also returns the version the server reported, for error messages.
-func (c *Client) ServerAtLeast(min string) (bool, string, error) {
-	version, err := c.ServerVersion()
+func (c *Client) ServerAtLeast(ctx context.Context, min string) (bool, string, error) {
+	version, err := c.ServerVersion(ctx)
 	if err != nil {
 		return false, "", err
 	}
@@ -1,6 +1,7 @@
-package api
+package technitium
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -50,21 +51,21 @@ func TestServerAtLeast(t *testing.T) {
 
 	srv := serve(`{"status":"ok","response":{"version":"15.0.1"}}`)
 	defer srv.Close()
-	ok, version, err := (&Client{Host: srv.URL, HTTP: srv.Client()}).ServerAtLeast("15.0")
+	ok, version, err := (&Client{Host: srv.URL, HTTP: srv.Client()}).ServerAtLeast(context.Background(), "15.0")
 	if err != nil || !ok || version != "15.0.1" {
 		t.Errorf("ServerAtLeast = %v, %q, %v; want true, \"15.0.1\", nil", ok, version, err)
 	}
 
 	old := serve(`{"status":"ok","response":{"version":"14.1"}}`)
 	defer old.Close()
-	ok, version, err = (&Client{Host: old.URL, HTTP: old.Client()}).ServerAtLeast("15.0")
+	ok, version, err = (&Client{Host: old.URL, HTTP: old.Client()}).ServerAtLeast(context.Background(), "15.0")
 	if err != nil || ok || version != "14.1" {
 		t.Errorf("ServerAtLeast = %v, %q, %v; want false, \"14.1\", nil", ok, version, err)
 	}
 
 	denied := serve(`{"status":"error","errorMessage":"Access was denied."}`)
 	defer denied.Close()
-	if ok, _, err = (&Client{Host: denied.URL, HTTP: denied.Client()}).ServerAtLeast("15.0"); err == nil || ok {
+	if ok, _, err = (&Client{Host: denied.URL, HTTP: denied.Client()}).ServerAtLeast(context.Background(), "15.0"); err == nil || ok {
 		t.Errorf("ServerAtLeast on an error response = %v, %v; want false and an error", ok, err)
 	}
 }
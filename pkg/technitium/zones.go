@@ -0,0 +1,67 @@
+package technitium
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// Zone is a zone as listed by /api/zones/list.
+type Zone struct {
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	Internal     bool   `json:"internal"`
+	DNSSECStatus string `json:"dnssecStatus"`
+	SOASerial    uint32 `json:"soaSerial"`
+	Expiry       string `json:"expiry,omitempty"`
+	IsExpired    bool   `json:"isExpired"`
+	SyncFailed   bool   `json:"syncFailed"`
+	NotifyFailed bool   `json:"notifyFailed"`
+	LastModified string `json:"lastModified"`
+	Disabled     bool   `json:"disabled"`
+	Catalog      string `json:"catalog,omitempty"`
+}
+
+// ZoneList is one page of zones. The page fields are zero when the list
+// was not paginated.
+type ZoneList struct {
+	PageNumber int    `json:"pageNumber"`
+	TotalPages int    `json:"totalPages"`
+	TotalZones int    `json:"totalZones"`
+	Zones      []Zone `json:"zones"`
+}
+
+// ListZonesOptions filters and paginates ListZones. Zero values are not sent.
+// FilterName and FilterType need server v15.3+; older servers ignore them.
+type ListZonesOptions struct {
+	FilterName   string // name pattern, `*` and `?` wildcards allowed
+	FilterType   string // zone type, such as Primary or Forwarder
+	PageNumber   int
+	ZonesPerPage int
+}
+
+func (o ListZonesOptions) values() url.Values {
+	q := url.Values{}
+	if o.FilterName != "" {
+		q.Set("filterName", o.FilterName)
+	}
+	if o.FilterType != "" {
+		q.Set("filterType", o.FilterType)
+	}
+	if o.PageNumber > 0 {
+		q.Set("pageNumber", strconv.Itoa(o.PageNumber))
+	}
+	if o.ZonesPerPage > 0 {
+		q.Set("zonesPerPage", strconv.Itoa(o.ZonesPerPage))
+	}
+	return q
+}
+
+// ListZones lists the zones on the server.
+func (c *Client) ListZones(ctx context.Context, opts ListZonesOptions) (*ZoneList, error) {
+	var list ZoneList
+	if err := c.call(ctx, "/api/zones/list", opts.values(), &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
@@ -0,0 +1,63 @@
+package technitium
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// stubServer answers every request with body and records the query.
+func stubServer(t *testing.T, body string) (*Client, *url.URL) {
+	t.Helper()
+	got := &url.URL{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*got = *r.URL
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return &Client{Host: srv.URL, HTTP: srv.Client()}, got
+}
+
+func TestListZones(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok","response":{"pageNumber":2,"totalPages":3,"totalZones":25,
+		"zones":[{"name":"example.com","type":"Primary","dnssecStatus":"SignedWithNSEC","soaSerial":2024010101,"disabled":true,"unknownField":1}]}}`)
+
+	list, err := c.ListZones(context.Background(), ListZonesOptions{FilterType: "Primary", PageNumber: 2, ZonesPerPage: 10})
+	if err != nil {
+		t.Fatalf("ListZones: %v", err)
+	}
+	if got.Path != "/api/zones/list" {
+		t.Errorf("path = %q", got.Path)
+	}
+	q := got.Query()
+	if q.Get("filterType") != "Primary" || q.Get("pageNumber") != "2" || q.Get("zonesPerPage") != "10" || q.Has("filterName") {
+		t.Errorf("query = %v", q)
+	}
+	if list.TotalZones != 25 || len(list.Zones) != 1 {
+		t.Fatalf("list = %+v", list)
+	}
+	z := list.Zones[0]
+	if z.Name != "example.com" || z.Type != "Primary" || !z.Disabled || z.SOASerial != 2024010101 || z.DNSSECStatus != "SignedWithNSEC" {
+		t.Errorf("zone = %+v", z)
+	}
+}
+
+func TestListZonesNoOptions(t *testing.T) {
+	c, got := stubServer(t, `{"status":"ok","response":{"zones":[]}}`)
+	if _, err := c.ListZones(context.Background(), ListZonesOptions{}); err != nil {
+		t.Fatalf("ListZones: %v", err)
+	}
+	if got.RawQuery != "" {
+		t.Errorf("query = %q, want none", got.RawQuery)
+	}
+}
+
+func TestCallRejectsUnexpectedShape(t *testing.T) {
+	c, _ := stubServer(t, `{"status":"ok","response":{"zones":"not a list"}}`)
+	if _, err := c.ListZones(context.Background(), ListZonesOptions{}); err == nil {
+		t.Fatal("want an error for a malformed response")
+	}
+}